@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"Service-schema/core/auth"
+	"Service-schema/domain/domainerror"
+	"github.com/labstack/echo/v4"
+	"strings"
+)
+
+const (
+	invalidTokenCode  = "invalid_token"
+	adminRequiredCode = "admin_required"
+)
+
+const bearerPrefix = "Bearer "
+
+// AdminTokenMiddleware marks requests presenting the admin bearer token in the request context. Requests without an
+// Authorization header stay anonymous and are refused only by admin routes; any other token is always refused.
+func AdminTokenMiddleware(adminToken *auth.AdminToken) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			authorization := c.Request().Header.Get(echo.HeaderAuthorization)
+			if authorization == "" {
+				return next(c)
+			}
+
+			token, isBearer := strings.CutPrefix(authorization, bearerPrefix)
+			if !isBearer || !adminToken.Matches(token) {
+				return domainerror.Unauthorized(invalidTokenCode, "Bearer token is not valid")
+			}
+			c.SetRequest(c.Request().WithContext(auth.WithAdmin(c.Request().Context())))
+			return next(c)
+		}
+	}
+}
+
+// RequireAdmin refuses requests without the admin token to the routes it wraps.
+func RequireAdmin(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if !auth.IsAdmin(c.Request().Context()) {
+			return domainerror.Unauthorized(adminRequiredCode, "The admin token is required")
+		}
+		return next(c)
+	}
+}
+
+func requireAdmin(c echo.Context, reason string) error {
+	if !auth.IsAdmin(c.Request().Context()) {
+		return domainerror.Unauthorized(adminRequiredCode, "The admin token is required to "+reason)
+	}
+	return nil
+}
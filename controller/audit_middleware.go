@@ -0,0 +1,24 @@
+package controller
+
+import (
+	"Service-schema/core/audit"
+	"Service-schema/core/auth"
+	"github.com/labstack/echo/v4"
+)
+
+const headerChangeReason = "X-Change-Reason"
+
+// AuditMiddleware stores the actor and change reason of the request in the request context. The actor is
+// audit.AdminActor for requests marked by AdminTokenMiddleware, which must run first, and audit.AnonymousActor
+// otherwise; a caller supplied header never names it. The reason is free text supplied by the caller and is recorded
+// as given.
+func AuditMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		change := audit.Change{Reason: c.Request().Header.Get(headerChangeReason)}
+		if auth.IsAdmin(c.Request().Context()) {
+			change.Actor = audit.AdminActor
+		}
+		c.SetRequest(c.Request().WithContext(audit.WithChange(c.Request().Context(), change)))
+		return next(c)
+	}
+}
@@ -0,0 +1,141 @@
+package controller
+
+import (
+	"Service-schema/controller/request"
+	"Service-schema/controller/response"
+	"Service-schema/service"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"strconv"
+)
+
+type CategoryController struct {
+	categoryService service.ICategoryService
+}
+
+func NewCategoryController(categoryService service.ICategoryService) *CategoryController {
+	return &CategoryController{categoryService: categoryService}
+}
+
+func (categoryController *CategoryController) RegisterRoutes(e *echo.Echo) {
+
+	e.GET("/api/v1/categories/:id", categoryController.GetCategoryById)
+	e.GET("/api/v1/categories/", categoryController.GetCategoryTree)
+	e.POST("/api/v1/categories/", categoryController.Add)
+	e.PUT("/api/v1/categories/:id", categoryController.Update)
+	e.DELETE("/api/v1/categories/:id", categoryController.DeleteCategoryById)
+	e.GET("/api/v1/products/:id/categories", categoryController.GetProductCategories)
+	e.PUT("/api/v1/products/:id/categories", categoryController.SetProductCategories)
+}
+
+func (categoryController *CategoryController) GetCategoryById(c echo.Context) error {
+	categoryId, convertErr := parseCategoryId(c)
+	if convertErr != nil {
+		return badRequest(convertErr)
+	}
+
+	category, err := categoryController.categoryService.GetById(c.Request().Context(), categoryId)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, response.ToCategoryResponse(category))
+}
+
+func (categoryController *CategoryController) GetCategoryTree(c echo.Context) error {
+	categoryTree, err := categoryController.categoryService.GetCategoryTree(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, response.ToCategoryTreeResponseList(categoryTree))
+}
+
+func (categoryController *CategoryController) Add(c echo.Context) error {
+	var createCategoryRequest request.CreateCategoryRequest
+	bindErr := c.Bind(&createCategoryRequest)
+	if bindErr != nil {
+		return bindErr
+	}
+
+	category, err := categoryController.categoryService.Add(c.Request().Context(), createCategoryRequest.ToDto())
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set(echo.HeaderLocation, fmt.Sprintf("/api/v1/categories/%d", category.Id))
+	return c.JSON(http.StatusCreated, response.ToCategoryResponse(category))
+}
+
+func (categoryController *CategoryController) Update(c echo.Context) error {
+	categoryId, convertErr := parseCategoryId(c)
+	if convertErr != nil {
+		return badRequest(convertErr)
+	}
+
+	var updateCategoryRequest request.UpdateCategoryRequest
+	bindErr := c.Bind(&updateCategoryRequest)
+	if bindErr != nil {
+		return bindErr
+	}
+
+	category, err := categoryController.categoryService.Update(c.Request().Context(), updateCategoryRequest.ToDto(categoryId))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, response.ToCategoryResponse(category))
+}
+
+func (categoryController *CategoryController) DeleteCategoryById(c echo.Context) error {
+	categoryId, convertErr := parseCategoryId(c)
+	if convertErr != nil {
+		return badRequest(convertErr)
+	}
+
+	err := categoryController.categoryService.Delete(c.Request().Context(), categoryId)
+	if err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusAccepted)
+}
+
+func (categoryController *CategoryController) GetProductCategories(c echo.Context) error {
+	productId, convertErr := parseProductId(c)
+	if convertErr != nil {
+		return badRequest(convertErr)
+	}
+
+	categories, err := categoryController.categoryService.GetProductCategories(c.Request().Context(), productId)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, response.ToCategoryResponseList(categories))
+}
+
+func (categoryController *CategoryController) SetProductCategories(c echo.Context) error {
+	productId, convertErr := parseProductId(c)
+	if convertErr != nil {
+		return badRequest(convertErr)
+	}
+
+	var setProductCategoriesRequest request.SetProductCategoriesRequest
+	bindErr := c.Bind(&setProductCategoriesRequest)
+	if bindErr != nil {
+		return bindErr
+	}
+
+	categories, err := categoryController.categoryService.SetProductCategories(c.Request().Context(), setProductCategoriesRequest.ToDto(productId))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, response.ToCategoryResponseList(categories))
+}
+
+func parseCategoryId(c echo.Context) (int64, error) {
+	return strconv.ParseInt(c.Param("id"), 10, 64)
+}
@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"Service-schema/controller/response"
+	"Service-schema/core/logging"
+	"Service-schema/domain/domainerror"
+	"errors"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+const internalErrorCode = "internal_error"
+
+// NewHTTPErrorHandler renders every error returned by a handler as an ErrorResponse with a status derived from its
+// kind, logging the server errors.
+func NewHTTPErrorHandler(logger *slog.Logger) echo.HTTPErrorHandler {
+	return func(err error, c echo.Context) {
+		if c.Response().Committed {
+			return
+		}
+
+		ctx := c.Request().Context()
+		status, errorResponse := toErrorResponse(err)
+		if status >= http.StatusInternalServerError {
+			logger.ErrorContext(ctx, "Error occurred handling request", "method", c.Request().Method, logging.ErrorKey, err)
+		}
+
+		var writeErr error
+		if c.Request().Method == http.MethodHead {
+			writeErr = c.NoContent(status)
+		} else {
+			writeErr = c.JSON(status, errorResponse)
+		}
+		if writeErr != nil {
+			logger.ErrorContext(ctx, "Error occurred writing error response", logging.ErrorKey, writeErr)
+		}
+	}
+}
+
+func toErrorResponse(err error) (int, response.ErrorResponse) {
+	var domainError *domainerror.Error
+	if errors.As(err, &domainError) {
+		status := statusOf(domainError)
+		errorResponse := response.ErrorResponse{
+			ErrorCode:        domainError.Code,
+			ErrorDescription: domainError.Message,
+		}
+		if status == http.StatusInternalServerError {
+			errorResponse.ErrorDescription = http.StatusText(status)
+		}
+		for _, field := range domainError.Fields {
+			errorResponse.Fields = append(errorResponse.Fields, response.FieldErrorResponse{Field: field.Field, Message: field.Message})
+		}
+		return status, errorResponse
+	}
+
+	var httpError *echo.HTTPError
+	if errors.As(err, &httpError) {
+		return httpError.Code, response.ErrorResponse{
+			ErrorCode:        strings.ReplaceAll(strings.ToLower(http.StatusText(httpError.Code)), " ", "_"),
+			ErrorDescription: fmt.Sprint(httpError.Message),
+		}
+	}
+
+	return http.StatusInternalServerError, response.ErrorResponse{
+		ErrorCode:        internalErrorCode,
+		ErrorDescription: http.StatusText(http.StatusInternalServerError),
+	}
+}
+
+func statusOf(domainError *domainerror.Error) int {
+	switch {
+	case errors.Is(domainError, domainerror.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(domainError, domainerror.ErrValidation):
+		return http.StatusUnprocessableEntity
+	case errors.Is(domainError, domainerror.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(domainError, domainerror.ErrPreconditionFailed):
+		return http.StatusPreconditionFailed
+	case errors.Is(domainError, domainerror.ErrUnauthorized):
+		return http.StatusUnauthorized
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func badRequest(err error) error {
+	return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+}
@@ -0,0 +1,36 @@
+package controller
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const headerETag = "ETag"
+const headerIfMatch = "If-Match"
+
+func productETag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// parseIfMatch returns the product version named by the If-Match header. A missing header or * returns 0,
+// which skips the version check; anything other than a single ETag issued by productETag fails the
+// precondition, since it can never match.
+func parseIfMatch(c echo.Context) (int64, error) {
+	ifMatch := strings.TrimSpace(c.Request().Header.Get(headerIfMatch))
+	if ifMatch == "" || ifMatch == "*" {
+		return 0, nil
+	}
+
+	unquoted, unquoteErr := strconv.Unquote(ifMatch)
+	if unquoteErr != nil || !strings.HasPrefix(ifMatch, `"`) {
+		return 0, echo.NewHTTPError(http.StatusPreconditionFailed, "If-Match must be a single ETag returned for the product")
+	}
+	version, parseErr := strconv.ParseInt(unquoted, 10, 64)
+	if parseErr != nil || version < 1 {
+		return 0, echo.NewHTTPError(http.StatusPreconditionFailed, "If-Match must be a single ETag returned for the product")
+	}
+
+	return version, nil
+}
@@ -0,0 +1,37 @@
+package controller
+
+import (
+	"Service-schema/controller/response"
+	"Service-schema/core/health"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+type HealthController struct {
+	healthRegistry health.IRegistry
+}
+
+func NewHealthController(healthRegistry health.IRegistry) *HealthController {
+	return &HealthController{healthRegistry: healthRegistry}
+}
+
+func (healthController *HealthController) RegisterRoutes(e *echo.Echo) {
+
+	e.GET("/health/live", healthController.Live)
+	e.GET("/health/ready", healthController.Ready)
+}
+
+// Live only tells the orchestrator the process is serving requests; it never touches dependencies, so a database
+// outage does not get the service restarted.
+func (healthController *HealthController) Live(c echo.Context) error {
+	return c.JSON(http.StatusOK, response.HealthResponse{Status: health.StatusUp})
+}
+
+func (healthController *HealthController) Ready(c echo.Context) error {
+	report := healthController.healthRegistry.Check(c.Request().Context())
+	status := http.StatusOK
+	if report.Status != health.StatusUp {
+		status = http.StatusServiceUnavailable
+	}
+	return c.JSON(status, response.ToHealthResponse(report))
+}
@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"strconv"
+	"time"
+)
+
+// unmatchedRoute labels requests that matched no route, so that arbitrary paths do not become label values.
+const unmatchedRoute = "unmatched"
+
+// MetricsMiddleware counts requests and records their latency by method, echo route and status, except for the
+// requests for which skipper returns true.
+func MetricsMiddleware(registerer prometheus.Registerer, skipper middleware.Skipper) echo.MiddlewareFunc {
+	labels := []string{"method", "route", "status"}
+	requests := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Number of HTTP requests handled.",
+	}, labels)
+	requestDuration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Latency of HTTP requests.",
+		Buckets: prometheus.DefBuckets,
+	}, labels)
+	registerer.MustRegister(requests, requestDuration)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if skipper(c) {
+				return next(c)
+			}
+			start := time.Now()
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+
+			route := c.Path()
+			if route == "" || route == "/*" {
+				route = unmatchedRoute
+			}
+			status := strconv.Itoa(c.Response().Status)
+			requests.WithLabelValues(c.Request().Method, route, status).Inc()
+			requestDuration.WithLabelValues(c.Request().Method, route, status).Observe(time.Since(start).Seconds())
+			return nil
+		}
+	}
+}
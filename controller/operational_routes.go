@@ -0,0 +1,12 @@
+package controller
+
+import (
+	"github.com/labstack/echo/v4"
+	"strings"
+)
+
+// SkipOperationalRoutes skips the health probes and the metrics scrape, which are polled continuously and would
+// otherwise drown the request logs, traces and request metrics.
+func SkipOperationalRoutes(c echo.Context) bool {
+	return strings.HasPrefix(c.Path(), "/health/") || c.Path() == "/metrics"
+}
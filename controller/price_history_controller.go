@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"Service-schema/controller/request"
+	"Service-schema/controller/response"
+	"Service-schema/service"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+type PriceHistoryController struct {
+	priceHistoryService service.IPriceHistoryService
+}
+
+func NewPriceHistoryController(priceHistoryService service.IPriceHistoryService) *PriceHistoryController {
+	return &PriceHistoryController{priceHistoryService: priceHistoryService}
+}
+
+func (priceHistoryController *PriceHistoryController) RegisterRoutes(e *echo.Echo) {
+
+	e.GET("/api/v1/products/:id/price-history", priceHistoryController.GetPriceHistory)
+}
+
+func (priceHistoryController *PriceHistoryController) GetPriceHistory(c echo.Context) error {
+	productId, convertErr := parseProductId(c)
+	if convertErr != nil {
+		return badRequest(convertErr)
+	}
+
+	var priceHistoryRequest request.PriceHistoryRequest
+	bindErr := c.Bind(&priceHistoryRequest)
+	if bindErr != nil {
+		return bindErr
+	}
+
+	query, queryErr := priceHistoryRequest.ToQuery(productId)
+	if queryErr != nil {
+		return badRequest(queryErr)
+	}
+	if query.IncludeDeleted {
+		if authorizeErr := requireAdmin(c, "read the price history of deleted products"); authorizeErr != nil {
+			return authorizeErr
+		}
+	}
+
+	priceChanges, err := priceHistoryController.priceHistoryService.GetPriceHistory(c.Request().Context(), query)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, response.ToPriceChangeResponseList(priceChanges))
+}
@@ -3,27 +3,48 @@ package controller
 import (
 	"Service-schema/controller/request"
 	"Service-schema/controller/response"
+	"Service-schema/core/auth"
+	"Service-schema/core/logging"
+	"Service-schema/domain"
 	"Service-schema/service"
+	"encoding/json"
+	"fmt"
 	"github.com/labstack/echo/v4"
+	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
 )
 
+const mimeApplicationMergePatchJSON = "application/merge-patch+json"
+
 type ProductController struct {
 	productService service.IProductService
+	authConfig     auth.Config
+	logger         *slog.Logger
 }
 
-func NewProductController(productService service.IProductService) *ProductController {
-	return &ProductController{productService: productService}
+func NewProductController(productService service.IProductService, authConfig auth.Config, logger *slog.Logger) *ProductController {
+	return &ProductController{productService: productService, authConfig: authConfig, logger: logger}
 }
 
 func (productController *ProductController) RegisterRoutes(e *echo.Echo) {
 
 	e.GET("/api/v1/products/:id", productController.GetProductById)
 	e.GET("/api/v1/products/", productController.GetAllProducts)
+	e.GET("/api/v1/products/export", productController.ExportProducts)
+	e.GET("/api/v1/products/search", productController.SearchProducts)
 	e.POST("/api/v1/products/", productController.Add)
-	e.PUT("/api/v1/products/", productController.UpdatePrice)
+	var priceChangeMiddlewares []echo.MiddlewareFunc
+	if productController.authConfig.RequireTokenForPriceChanges {
+		priceChangeMiddlewares = append(priceChangeMiddlewares, RequireAdmin)
+	}
+	e.PUT("/api/v1/products/", productController.UpdatePrice, priceChangeMiddlewares...)
+	e.PUT("/api/v1/products/:id", productController.Replace, priceChangeMiddlewares...)
+	e.PATCH("/api/v1/products/:id", productController.Patch, priceChangeMiddlewares...)
 	e.DELETE("/api/v1/products/:id", productController.DeleteProductById)
+	e.POST("/api/v1/products/:id/restore", productController.Restore)
+	e.GET("/api/v1/stores/:id/products", productController.GetStoreProducts)
 }
 
 func (productController *ProductController) GetProductById(c echo.Context) error {
@@ -31,44 +52,183 @@ func (productController *ProductController) GetProductById(c echo.Context) error
 	productId, convertErr := strconv.Atoi(id)
 
 	if convertErr != nil {
-		return c.JSON(http.StatusBadRequest, response.ErrorResponse{ErrorDescription: convertErr.Error()})
+		return badRequest(convertErr)
+	}
+
+	var productRequest request.ProductRequest
+	bindErr := c.Bind(&productRequest)
+	if bindErr != nil {
+		return bindErr
+	}
+
+	at, atErr := productRequest.ToTime()
+	if atErr != nil {
+		return badRequest(atErr)
 	}
 
-	product, err := productController.productService.GetById(int64(productId))
+	product, err := productController.productService.GetByIdAt(c.Request().Context(), int64(productId), at)
 
 	if err != nil {
-		return c.JSON(http.StatusNotFound, response.ErrorResponse{ErrorDescription: err.Error()})
+		return err
 	}
 
+	c.Response().Header().Set(headerETag, productETag(product.Version))
 	return c.JSON(http.StatusOK, response.ToProductResponse(product))
 }
 
 func (productController *ProductController) GetAllProducts(c echo.Context) error {
-	store := c.QueryParam("store")
+	var productListRequest request.ProductListRequest
+	bindErr := c.Bind(&productListRequest)
+	if bindErr != nil {
+		return bindErr
+	}
+
+	query, queryErr := productListRequest.ToQuery()
+	if queryErr != nil {
+		return badRequest(queryErr)
+	}
+	if authorizeErr := authorizeProductQuery(c, query); authorizeErr != nil {
+		return authorizeErr
+	}
+
+	productPage, err := productController.productService.GetProducts(c.Request().Context(), query)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, response.ToProductPageResponse(productPage))
+}
+
+// ExportProducts streams the products matching the list filters as CSV, NDJSON or XLSX. The response is only
+// committed once the first product is ready, so a rejected query still gets a regular error response; a failure
+// after that aborts the connection, leaving the client with a visibly incomplete download rather than a
+// truncated file that looks complete.
+func (productController *ProductController) ExportProducts(c echo.Context) error {
+	var productExportRequest request.ProductExportRequest
+	bindErr := c.Bind(&productExportRequest)
+	if bindErr != nil {
+		return bindErr
+	}
+
+	format, formatErr := productExportRequest.ToFormat(c.Request().Header.Get(echo.HeaderAccept))
+	if formatErr != nil {
+		return echo.NewHTTPError(http.StatusNotAcceptable, formatErr.Error())
+	}
+	query, queryErr := productExportRequest.ToQuery()
+	if queryErr != nil {
+		return badRequest(queryErr)
+	}
+	if authorizeErr := authorizeProductQuery(c, query); authorizeErr != nil {
+		return authorizeErr
+	}
+
+	var exportWriter response.IProductExportWriter
+	start := func() error {
+		c.Response().Header().Set(echo.HeaderContentType, format.ContentType())
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", format.FileName()))
+		c.Response().WriteHeader(http.StatusOK)
+
+		var writerErr error
+		exportWriter, writerErr = response.NewProductExportWriter(format, c.Response())
+		return writerErr
+	}
+
+	err := productController.productService.ExportProducts(c.Request().Context(), query, func(product domain.Product) error {
+		if exportWriter == nil {
+			if startErr := start(); startErr != nil {
+				return startErr
+			}
+		}
+		return exportWriter.Write(product)
+	})
+	if err == nil && exportWriter == nil {
+		err = start()
+	}
+	if err == nil {
+		err = exportWriter.Close()
+	}
+	if err != nil && c.Response().Committed {
+		productController.logger.ErrorContext(c.Request().Context(), "Aborted product export", logging.ErrorKey, err)
+		abortResponse(c)
+		return nil
+	}
+	return err
+}
+
+// abortResponse fails a response whose body has already been started: the status recorded by the middlewares
+// becomes 500, and closing the connection keeps the client from mistaking the truncated body for a complete one.
+func abortResponse(c echo.Context) {
+	c.Response().Status = http.StatusInternalServerError
+	if conn, _, hijackErr := http.NewResponseController(c.Response()).Hijack(); hijackErr == nil {
+		_ = conn.Close()
+	}
+}
+
+func (productController *ProductController) SearchProducts(c echo.Context) error {
+	var productSearchRequest request.ProductSearchRequest
+	bindErr := c.Bind(&productSearchRequest)
+	if bindErr != nil {
+		return bindErr
+	}
+
+	query, queryErr := productSearchRequest.ToQuery()
+	if queryErr != nil {
+		return badRequest(queryErr)
+	}
+
+	result, err := productController.productService.SearchProducts(c.Request().Context(), query)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, response.ToProductSearchResponse(result))
+}
+
+func (productController *ProductController) GetStoreProducts(c echo.Context) error {
+	storeId, convertErr := parseStoreId(c)
+	if convertErr != nil {
+		return badRequest(convertErr)
+	}
+
+	var productListRequest request.ProductListRequest
+	bindErr := c.Bind(&productListRequest)
+	if bindErr != nil {
+		return bindErr
+	}
 
-	if len(store) == 0 {
-		products := productController.productService.GetAllProducts()
-		return c.JSON(http.StatusOK, response.ToProductResponseList(products))
+	query, queryErr := productListRequest.ToQuery()
+	if queryErr != nil {
+		return badRequest(queryErr)
+	}
+	if authorizeErr := authorizeProductQuery(c, query); authorizeErr != nil {
+		return authorizeErr
+	}
+	query.StoreId = storeId
+
+	productPage, err := productController.productService.GetProducts(c.Request().Context(), query)
+	if err != nil {
+		return err
 	}
 
-	productsWithStoreName := productController.productService.GetAllProductsByStoreName(store)
-	return c.JSON(http.StatusOK, response.ToProductResponseList(productsWithStoreName))
+	return c.JSON(http.StatusOK, response.ToProductPageResponse(productPage))
 }
 
 func (productController *ProductController) Add(c echo.Context) error {
 	var createProductRequest request.CreateProductRequest
 	bindErr := c.Bind(&createProductRequest)
 	if bindErr != nil {
-		return c.JSON(http.StatusBadRequest, response.ErrorResponse{ErrorDescription: bindErr.Error()})
+		return bindErr
 	}
 
-	err := productController.productService.Add(createProductRequest.ToDto())
+	product, err := productController.productService.Add(c.Request().Context(), createProductRequest.ToDto())
 
 	if err != nil {
-		return c.JSON(http.StatusUnprocessableEntity, response.ErrorResponse{ErrorDescription: err.Error()})
+		return err
 	}
 
-	return c.NoContent(http.StatusCreated)
+	c.Response().Header().Set(echo.HeaderLocation, fmt.Sprintf("/api/v1/products/%d", product.Id))
+	c.Response().Header().Set(headerETag, productETag(product.Version))
+	return c.JSON(http.StatusCreated, response.ToProductResponse(product))
 }
 
 func (productController *ProductController) UpdatePrice(c echo.Context) error {
@@ -76,29 +236,129 @@ func (productController *ProductController) UpdatePrice(c echo.Context) error {
 	bindErr := c.Bind(&updateProductPriceRequest)
 
 	if bindErr != nil {
-		return c.JSON(http.StatusBadRequest, response.ErrorResponse{ErrorDescription: bindErr.Error()})
+		return bindErr
 	}
 
-	err := productController.productService.UpdatePrice(updateProductPriceRequest.ToDto())
+	expectedVersion, ifMatchErr := parseIfMatch(c)
+	if ifMatchErr != nil {
+		return ifMatchErr
+	}
+
+	updateProductRequestDto := updateProductPriceRequest.ToDto()
+	updateProductRequestDto.ExpectedVersion = expectedVersion
+	err := productController.productService.UpdatePrice(c.Request().Context(), updateProductRequestDto)
 	if err != nil {
-		return c.JSON(http.StatusUnprocessableEntity, response.ErrorResponse{ErrorDescription: err.Error()})
+		return err
 	}
 
 	return c.NoContent(http.StatusAccepted)
 }
 
+func (productController *ProductController) Replace(c echo.Context) error {
+	productId, convertErr := parseProductId(c)
+	if convertErr != nil {
+		return badRequest(convertErr)
+	}
+
+	var replaceProductRequest request.ReplaceProductRequest
+	bindErr := c.Bind(&replaceProductRequest)
+	if bindErr != nil {
+		return bindErr
+	}
+
+	expectedVersion, ifMatchErr := parseIfMatch(c)
+	if ifMatchErr != nil {
+		return ifMatchErr
+	}
+
+	replaceProductRequestDto := replaceProductRequest.ToDto(productId)
+	replaceProductRequestDto.ExpectedVersion = expectedVersion
+	product, err := productController.productService.Replace(c.Request().Context(), replaceProductRequestDto)
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set(headerETag, productETag(product.Version))
+	return c.JSON(http.StatusOK, response.ToProductResponse(product))
+}
+
+func (productController *ProductController) Patch(c echo.Context) error {
+	productId, convertErr := parseProductId(c)
+	if convertErr != nil {
+		return badRequest(convertErr)
+	}
+
+	contentType := c.Request().Header.Get(echo.HeaderContentType)
+	if !strings.HasPrefix(contentType, mimeApplicationMergePatchJSON) && !strings.HasPrefix(contentType, echo.MIMEApplicationJSON) {
+		return echo.ErrUnsupportedMediaType
+	}
+
+	var patchProductRequest request.PatchProductRequest
+	decodeErr := json.NewDecoder(c.Request().Body).Decode(&patchProductRequest)
+	if decodeErr != nil {
+		return badRequest(decodeErr)
+	}
+
+	expectedVersion, ifMatchErr := parseIfMatch(c)
+	if ifMatchErr != nil {
+		return ifMatchErr
+	}
+
+	patchProductRequestDto := patchProductRequest.ToDto(productId)
+	patchProductRequestDto.ExpectedVersion = expectedVersion
+	product, err := productController.productService.Patch(c.Request().Context(), patchProductRequestDto)
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set(headerETag, productETag(product.Version))
+	return c.JSON(http.StatusOK, response.ToProductResponse(product))
+}
+
 func (productController *ProductController) DeleteProductById(c echo.Context) error {
 	id := c.Param("id")
 	productId, converErr := strconv.Atoi(id)
 	if converErr != nil {
-		return c.JSON(http.StatusBadRequest, response.ErrorResponse{ErrorDescription: converErr.Error()})
+		return badRequest(converErr)
+	}
+
+	expectedVersion, ifMatchErr := parseIfMatch(c)
+	if ifMatchErr != nil {
+		return ifMatchErr
 	}
 
-	err := productController.productService.Delete(int64(productId))
+	err := productController.productService.Delete(c.Request().Context(), int64(productId), expectedVersion)
 
 	if err != nil {
-		return c.JSON(http.StatusNotFound, response.ErrorResponse{ErrorDescription: err.Error()})
+		return err
 	}
 
 	return c.NoContent(http.StatusAccepted)
 }
+
+func (productController *ProductController) Restore(c echo.Context) error {
+	productId, convertErr := parseProductId(c)
+	if convertErr != nil {
+		return badRequest(convertErr)
+	}
+
+	product, err := productController.productService.Restore(c.Request().Context(), productId)
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set(headerETag, productETag(product.Version))
+	return c.JSON(http.StatusOK, response.ToProductResponse(product))
+}
+
+func parseProductId(c echo.Context) (int64, error) {
+	return strconv.ParseInt(c.Param("id"), 10, 64)
+}
+
+// authorizeProductQuery keeps deleted products, which are pending purge, visible to administrators only.
+func authorizeProductQuery(c echo.Context, query domain.ProductQuery) error {
+	if !query.IncludeDeleted {
+		return nil
+	}
+	return requireAdmin(c, "list deleted products")
+}
@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"Service-schema/controller/request"
+	"Service-schema/controller/response"
+	"Service-schema/domain"
+	"Service-schema/service"
+	"errors"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+type ProductImportController struct {
+	productImportService service.IProductImportService
+}
+
+func NewProductImportController(productImportService service.IProductImportService) *ProductImportController {
+	return &ProductImportController{productImportService: productImportService}
+}
+
+func (productImportController *ProductImportController) RegisterRoutes(e *echo.Echo) {
+
+	e.POST("/api/v1/products/bulk", productImportController.Import)
+}
+
+// Import responds with the per-row report: 422 when an all-or-nothing import was rolled back, 200 otherwise,
+// even if a best-effort import skipped rows.
+func (productImportController *ProductImportController) Import(c echo.Context) error {
+	productImportRequest := request.ProductImportRequest{Mode: c.QueryParam("mode")}
+	productImportRequestDto, requestErr := productImportRequest.ToDto(c.Request().Header.Get(echo.HeaderContentType), c.Request().Body)
+	if errors.Is(requestErr, request.ErrUnsupportedProductImportType) {
+		return echo.NewHTTPError(http.StatusUnsupportedMediaType, requestErr.Error())
+	}
+	if requestErr != nil {
+		return badRequest(requestErr)
+	}
+
+	report, err := productImportController.productImportService.Import(c.Request().Context(), productImportRequestDto)
+	if err != nil {
+		return err
+	}
+
+	status := http.StatusOK
+	if report.Mode == domain.AllOrNothingImport && report.Failed > 0 {
+		status = http.StatusUnprocessableEntity
+	}
+	return c.JSON(status, response.ToProductImportReportResponse(report))
+}
@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"Service-schema/controller/request"
+	"Service-schema/controller/response"
+	"Service-schema/service"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"strconv"
+)
+
+type PromotionController struct {
+	promotionService service.IPromotionService
+}
+
+func NewPromotionController(promotionService service.IPromotionService) *PromotionController {
+	return &PromotionController{promotionService: promotionService}
+}
+
+func (promotionController *PromotionController) RegisterRoutes(e *echo.Echo) {
+
+	e.GET("/api/v1/promotions/:id", promotionController.GetPromotionById)
+	e.GET("/api/v1/promotions/", promotionController.GetAllPromotions)
+	e.POST("/api/v1/promotions/", promotionController.Add)
+	e.PUT("/api/v1/promotions/:id", promotionController.Update)
+	e.DELETE("/api/v1/promotions/:id", promotionController.DeletePromotionById)
+}
+
+func (promotionController *PromotionController) GetPromotionById(c echo.Context) error {
+	promotionId, convertErr := parsePromotionId(c)
+	if convertErr != nil {
+		return badRequest(convertErr)
+	}
+
+	promotion, err := promotionController.promotionService.GetById(c.Request().Context(), promotionId)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, response.ToPromotionResponse(promotion))
+}
+
+func (promotionController *PromotionController) GetAllPromotions(c echo.Context) error {
+	promotions, err := promotionController.promotionService.GetAllPromotions(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, response.ToPromotionResponseList(promotions))
+}
+
+func (promotionController *PromotionController) Add(c echo.Context) error {
+	var promotionRequest request.PromotionRequest
+	bindErr := c.Bind(&promotionRequest)
+	if bindErr != nil {
+		return bindErr
+	}
+
+	promotion, err := promotionController.promotionService.Add(c.Request().Context(), promotionRequest.ToDto(0))
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set(echo.HeaderLocation, fmt.Sprintf("/api/v1/promotions/%d", promotion.Id))
+	return c.JSON(http.StatusCreated, response.ToPromotionResponse(promotion))
+}
+
+func (promotionController *PromotionController) Update(c echo.Context) error {
+	promotionId, convertErr := parsePromotionId(c)
+	if convertErr != nil {
+		return badRequest(convertErr)
+	}
+
+	var promotionRequest request.PromotionRequest
+	bindErr := c.Bind(&promotionRequest)
+	if bindErr != nil {
+		return bindErr
+	}
+
+	promotion, err := promotionController.promotionService.Update(c.Request().Context(), promotionRequest.ToDto(promotionId))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, response.ToPromotionResponse(promotion))
+}
+
+func (promotionController *PromotionController) DeletePromotionById(c echo.Context) error {
+	promotionId, convertErr := parsePromotionId(c)
+	if convertErr != nil {
+		return badRequest(convertErr)
+	}
+
+	err := promotionController.promotionService.Delete(c.Request().Context(), promotionId)
+	if err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusAccepted)
+}
+
+func parsePromotionId(c echo.Context) (int64, error) {
+	return strconv.ParseInt(c.Param("id"), 10, 64)
+}
@@ -0,0 +1,49 @@
+package request
+
+import (
+	"Service-schema/controller/response"
+	"fmt"
+	"mime"
+	"strings"
+)
+
+var ErrUnsupportedProductExportFormat = fmt.Errorf("format must be %s, %s or %s", response.CSVProductExport, response.NDJSONProductExport, response.XLSXProductExport)
+
+var productExportMediaTypes = map[string]response.ProductExportFormat{
+	MIMETextCSV:                  response.CSVProductExport,
+	MIMEApplicationNDJSON:        response.NDJSONProductExport,
+	MIMEApplicationJSONLines:     response.NDJSONProductExport,
+	response.MIMEApplicationXLSX: response.XLSXProductExport,
+	"*/*":                        response.CSVProductExport,
+}
+
+type ProductExportRequest struct {
+	ProductListRequest
+	Format string `query:"format"`
+}
+
+// ToFormat prefers the format query parameter over the Accept header, taking the first acceptable media type
+// the export supports. Without either, the export is CSV.
+func (productExportRequest ProductExportRequest) ToFormat(accept string) (response.ProductExportFormat, error) {
+	if productExportRequest.Format != "" {
+		format := response.ProductExportFormat(strings.ToLower(productExportRequest.Format))
+		if format != response.CSVProductExport && format != response.NDJSONProductExport && format != response.XLSXProductExport {
+			return "", ErrUnsupportedProductExportFormat
+		}
+		return format, nil
+	}
+
+	if strings.TrimSpace(accept) == "" {
+		return response.CSVProductExport, nil
+	}
+	for _, acceptedType := range strings.Split(accept, ",") {
+		mediaType, _, parseErr := mime.ParseMediaType(acceptedType)
+		if parseErr != nil {
+			continue
+		}
+		if format, supported := productExportMediaTypes[mediaType]; supported {
+			return format, nil
+		}
+	}
+	return "", ErrUnsupportedProductExportFormat
+}
@@ -0,0 +1,170 @@
+package request
+
+import (
+	"Service-schema/domain"
+	"Service-schema/service/dto"
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"mime"
+	"slices"
+	"strings"
+)
+
+const (
+	MIMETextCSV              = "text/csv"
+	MIMEApplicationNDJSON    = "application/x-ndjson"
+	MIMEApplicationJSONLines = "application/jsonl"
+)
+
+const maxProductImportLineSize = 1 << 20
+
+var ErrUnsupportedProductImportType = fmt.Errorf("content type must be %s or %s", MIMETextCSV, MIMEApplicationNDJSON)
+
+var productImportColumns = []string{"name", "price", "currency", "discount", "store_id"}
+var requiredProductImportColumns = []string{"name", "price", "store_id"}
+
+type ProductImportRequest struct {
+	Mode string `query:"mode"`
+}
+
+// ToDto reads the rows of body lazily, so the import is streamed rather than buffered. Only a body that
+// cannot be imported at all, such as an unsupported content type or a malformed CSV header, is an error;
+// problems with single rows are carried by the rows themselves.
+func (productImportRequest ProductImportRequest) ToDto(contentType string, body io.Reader) (dto.ProductImportRequestDto, error) {
+	mode := domain.ImportMode(productImportRequest.Mode)
+	if mode == "" {
+		mode = domain.AllOrNothingImport
+	}
+
+	mediaType, _, parseErr := mime.ParseMediaType(contentType)
+	if parseErr != nil {
+		return dto.ProductImportRequestDto{}, ErrUnsupportedProductImportType
+	}
+
+	var rows iter.Seq[dto.ProductImportRowDto]
+	var rowsErr error
+	switch mediaType {
+	case MIMETextCSV:
+		rows, rowsErr = csvProductImportRows(body)
+	case MIMEApplicationNDJSON, MIMEApplicationJSONLines:
+		rows = ndjsonProductImportRows(body)
+	default:
+		rowsErr = ErrUnsupportedProductImportType
+	}
+	if rowsErr != nil {
+		return dto.ProductImportRequestDto{}, rowsErr
+	}
+
+	return dto.ProductImportRequestDto{Mode: mode, Rows: rows}, nil
+}
+
+// csvProductImportRows expects a header naming the columns in any order; currency and discount may be omitted.
+func csvProductImportRows(body io.Reader) (iter.Seq[dto.ProductImportRowDto], error) {
+	reader := csv.NewReader(body)
+	reader.ReuseRecord = true
+	header, headerErr := reader.Read()
+	if headerErr != nil {
+		return nil, fmt.Errorf("csv header could not be read: %w", headerErr)
+	}
+
+	columnIndexes := map[string]int{}
+	for index, column := range header {
+		column = strings.ToLower(strings.TrimSpace(column))
+		if !slices.Contains(productImportColumns, column) {
+			return nil, fmt.Errorf("csv column %q is unknown, expected %s", column, strings.Join(productImportColumns, ", "))
+		}
+		columnIndexes[column] = index
+	}
+	for _, column := range requiredProductImportColumns {
+		if _, found := columnIndexes[column]; !found {
+			return nil, fmt.Errorf("csv column %q is missing", column)
+		}
+	}
+
+	return func(yield func(dto.ProductImportRowDto) bool) {
+		for rowNumber := 1; ; rowNumber++ {
+			record, readErr := reader.Read()
+			if errors.Is(readErr, io.EOF) {
+				return
+			}
+
+			var parseErr *csv.ParseError
+			if readErr != nil && !errors.As(readErr, &parseErr) {
+				yield(dto.ProductImportRowDto{Row: rowNumber, ParseErr: fmt.Errorf("body could not be read: %w", readErr)})
+				return
+			}
+
+			row := dto.ProductImportRowDto{Row: rowNumber}
+			if readErr != nil {
+				row.ParseErr = readErr
+			} else {
+				row.Product, row.ParseErr = parseCSVProductImportRecord(record, columnIndexes)
+			}
+			if !yield(row) {
+				return
+			}
+		}
+	}, nil
+}
+
+func parseCSVProductImportRecord(record []string, columnIndexes map[string]int) (dto.CreateProductRequestDto, error) {
+	value := func(column string) string {
+		index, found := columnIndexes[column]
+		if !found {
+			return ""
+		}
+		return strings.TrimSpace(record[index])
+	}
+
+	var errs []error
+	product := dto.CreateProductRequestDto{Name: value("name"), Currency: value("currency")}
+	if price := parseOptionalDecimal("price", value("price"), &errs); price != nil {
+		product.Price = *price
+	}
+	if discount := parseOptionalDecimal("discount", value("discount"), &errs); discount != nil {
+		product.Discount = *discount
+	}
+	product.StoreId = parseOptionalInt64("store_id", value("store_id"), &errs)
+
+	return product, errors.Join(errs...)
+}
+
+// ndjsonProductImportRows yields one row per non-blank line, each holding the same object as POST /api/v1/products/.
+func ndjsonProductImportRows(body io.Reader) iter.Seq[dto.ProductImportRowDto] {
+	return func(yield func(dto.ProductImportRowDto) bool) {
+		scanner := bufio.NewScanner(body)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxProductImportLineSize)
+
+		rowNumber := 0
+		for scanner.Scan() {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			rowNumber++
+
+			var createProductRequest CreateProductRequest
+			decoder := json.NewDecoder(bytes.NewReader(line))
+			decoder.DisallowUnknownFields()
+			row := dto.ProductImportRowDto{Row: rowNumber}
+			if decodeErr := decoder.Decode(&createProductRequest); decodeErr != nil {
+				row.ParseErr = decodeErr
+			} else {
+				row.Product = createProductRequest.ToDto()
+			}
+			if !yield(row) {
+				return
+			}
+		}
+
+		if scanErr := scanner.Err(); scanErr != nil {
+			yield(dto.ProductImportRowDto{Row: rowNumber + 1, ParseErr: fmt.Errorf("body could not be read: %w", scanErr)})
+		}
+	}
+}
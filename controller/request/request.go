@@ -1,25 +1,35 @@
 package request
 
-import "Service-schema/service/dto"
+import (
+	"Service-schema/domain"
+	"Service-schema/service/dto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
 
 type CreateProductRequest struct {
-	Name     string  `json:"name"`
-	Price    float32 `json:"price"`
-	Discount float32 `json:"discount"`
-	Store    string  `json:"store"`
+	Name     string         `json:"name"`
+	Price    domain.Decimal `json:"price"`
+	Currency string         `json:"currency"`
+	Discount domain.Decimal `json:"discount"`
+	StoreId  int64          `json:"store_id"`
 }
 
 type UpdateProductPriceRequest struct {
-	Id    int64   `json:"id"`
-	Price float32 `json:"price"`
+	Id    int64          `json:"id"`
+	Price domain.Decimal `json:"price"`
 }
 
 func (createProductRequest CreateProductRequest) ToDto() dto.CreateProductRequestDto {
 	return dto.CreateProductRequestDto{
 		Name:     createProductRequest.Name,
 		Price:    createProductRequest.Price,
+		Currency: createProductRequest.Currency,
 		Discount: createProductRequest.Discount,
-		Store:    createProductRequest.Store,
+		StoreId:  createProductRequest.StoreId,
 	}
 }
 
@@ -29,3 +39,354 @@ func (updateProductRequest UpdateProductPriceRequest) ToDto() dto.UpdateProductR
 		Price: updateProductRequest.Price,
 	}
 }
+
+type ReplaceProductRequest struct {
+	Name     string         `json:"name"`
+	Price    domain.Decimal `json:"price"`
+	Currency string         `json:"currency"`
+	Discount domain.Decimal `json:"discount"`
+	StoreId  int64          `json:"store_id"`
+}
+
+// PatchProductRequest is a JSON Merge Patch (RFC 7396) document: absent members stay unchanged and null
+// members are reset to their zero value, which the service then validates like any other value.
+type PatchProductRequest struct {
+	Name     *string
+	Price    *domain.Decimal
+	Currency *string
+	Discount *domain.Decimal
+	StoreId  *int64
+}
+
+type CreateStoreRequest struct {
+	Name string `json:"name"`
+}
+
+type UpdateStoreRequest struct {
+	Name string `json:"name"`
+}
+
+type CreateCategoryRequest struct {
+	Name     string `json:"name"`
+	ParentId *int64 `json:"parent_id"`
+}
+
+type UpdateCategoryRequest struct {
+	Name     string `json:"name"`
+	ParentId *int64 `json:"parent_id"`
+}
+
+type SetProductCategoriesRequest struct {
+	CategoryIds []int64 `json:"category_ids"`
+}
+
+type StockOperationRequest struct {
+	Quantity int64 `json:"quantity"`
+}
+
+type PriceHistoryRequest struct {
+	From           string `query:"from"`
+	To             string `query:"to"`
+	IncludeDeleted string `query:"include_deleted"`
+}
+
+type PromotionRequest struct {
+	Name      string         `json:"name"`
+	Type      string         `json:"type"`
+	Value     domain.Decimal `json:"value"`
+	Currency  string         `json:"currency"`
+	StoreId   *int64         `json:"store_id"`
+	ProductId *int64         `json:"product_id"`
+	Stackable bool           `json:"stackable"`
+	StartsAt  time.Time      `json:"starts_at"`
+	EndsAt    time.Time      `json:"ends_at"`
+}
+
+type ProductRequest struct {
+	At string `query:"at"`
+}
+
+type ProductListRequest struct {
+	At          string `query:"at"`
+	Category    string `query:"category"`
+	Name        string `query:"name"`
+	MinPrice    string `query:"min_price"`
+	MaxPrice    string `query:"max_price"`
+	MinDiscount string `query:"min_discount"`
+	MaxDiscount string `query:"max_discount"`
+	Sort        string `query:"sort"`
+	Order       string `query:"order"`
+	Limit       string `query:"limit"`
+	Offset      string `query:"offset"`
+	Cursor      string `query:"cursor"`
+
+	IncludeDeleted string `query:"include_deleted"`
+}
+
+type ProductSearchRequest struct {
+	Q      string `query:"q"`
+	Store  string `query:"store"`
+	Limit  string `query:"limit"`
+	Offset string `query:"offset"`
+	At     string `query:"at"`
+}
+
+func (replaceProductRequest ReplaceProductRequest) ToDto(productId int64) dto.ReplaceProductRequestDto {
+	return dto.ReplaceProductRequestDto{
+		Id:       productId,
+		Name:     replaceProductRequest.Name,
+		Price:    replaceProductRequest.Price,
+		Currency: replaceProductRequest.Currency,
+		Discount: replaceProductRequest.Discount,
+		StoreId:  replaceProductRequest.StoreId,
+	}
+}
+
+func (patchProductRequest *PatchProductRequest) UnmarshalJSON(content []byte) error {
+	var members map[string]json.RawMessage
+	if unmarshalErr := json.Unmarshal(content, &members); unmarshalErr != nil {
+		return unmarshalErr
+	}
+
+	var errs []error
+	for name, member := range members {
+		var memberErr error
+		switch name {
+		case "name":
+			patchProductRequest.Name, memberErr = decodeMergePatchMember[string](member)
+		case "price":
+			patchProductRequest.Price, memberErr = decodeMergePatchMember[domain.Decimal](member)
+		case "currency":
+			patchProductRequest.Currency, memberErr = decodeMergePatchMember[string](member)
+		case "discount":
+			patchProductRequest.Discount, memberErr = decodeMergePatchMember[domain.Decimal](member)
+		case "store_id":
+			patchProductRequest.StoreId, memberErr = decodeMergePatchMember[int64](member)
+		default:
+			memberErr = errors.New("unknown field")
+		}
+		if memberErr != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, memberErr))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func decodeMergePatchMember[T any](member json.RawMessage) (*T, error) {
+	value := new(T)
+	if string(member) == "null" {
+		return value, nil
+	}
+	if unmarshalErr := json.Unmarshal(member, value); unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
+	return value, nil
+}
+
+func (patchProductRequest PatchProductRequest) ToDto(productId int64) dto.PatchProductRequestDto {
+	return dto.PatchProductRequestDto{
+		Id:       productId,
+		Name:     patchProductRequest.Name,
+		Price:    patchProductRequest.Price,
+		Currency: patchProductRequest.Currency,
+		Discount: patchProductRequest.Discount,
+		StoreId:  patchProductRequest.StoreId,
+	}
+}
+
+func (createStoreRequest CreateStoreRequest) ToDto() dto.CreateStoreRequestDto {
+	return dto.CreateStoreRequestDto{
+		Name: createStoreRequest.Name,
+	}
+}
+
+func (updateStoreRequest UpdateStoreRequest) ToDto(storeId int64) dto.UpdateStoreRequestDto {
+	return dto.UpdateStoreRequestDto{
+		Id:   storeId,
+		Name: updateStoreRequest.Name,
+	}
+}
+
+func (createCategoryRequest CreateCategoryRequest) ToDto() dto.CreateCategoryRequestDto {
+	return dto.CreateCategoryRequestDto{
+		Name:     createCategoryRequest.Name,
+		ParentId: createCategoryRequest.ParentId,
+	}
+}
+
+func (updateCategoryRequest UpdateCategoryRequest) ToDto(categoryId int64) dto.UpdateCategoryRequestDto {
+	return dto.UpdateCategoryRequestDto{
+		Id:       categoryId,
+		Name:     updateCategoryRequest.Name,
+		ParentId: updateCategoryRequest.ParentId,
+	}
+}
+
+func (setProductCategoriesRequest SetProductCategoriesRequest) ToDto(productId int64) dto.SetProductCategoriesRequestDto {
+	return dto.SetProductCategoriesRequestDto{
+		ProductId:   productId,
+		CategoryIds: setProductCategoriesRequest.CategoryIds,
+	}
+}
+
+func (stockOperationRequest StockOperationRequest) ToDto(productId int64) dto.StockOperationRequestDto {
+	return dto.StockOperationRequestDto{
+		ProductId: productId,
+		Quantity:  stockOperationRequest.Quantity,
+	}
+}
+
+func (priceHistoryRequest PriceHistoryRequest) ToQuery(productId int64) (domain.PriceHistoryQuery, error) {
+	var errs []error
+	query := domain.PriceHistoryQuery{ProductId: productId}
+	query.From = parseOptionalTime("from", priceHistoryRequest.From, &errs)
+	query.To = parseOptionalTime("to", priceHistoryRequest.To, &errs)
+	query.IncludeDeleted = parseOptionalBool("include_deleted", priceHistoryRequest.IncludeDeleted, &errs)
+	return query, errors.Join(errs...)
+}
+
+func (promotionRequest PromotionRequest) ToDto(promotionId int64) dto.PromotionRequestDto {
+	return dto.PromotionRequestDto{
+		Id:        promotionId,
+		Name:      promotionRequest.Name,
+		Type:      domain.PromotionType(promotionRequest.Type),
+		Value:     promotionRequest.Value,
+		Currency:  promotionRequest.Currency,
+		StoreId:   promotionRequest.StoreId,
+		ProductId: promotionRequest.ProductId,
+		Stackable: promotionRequest.Stackable,
+		StartsAt:  promotionRequest.StartsAt,
+		EndsAt:    promotionRequest.EndsAt,
+	}
+}
+
+// ToTime returns the moment the product price is resolved at, the zero time meaning now.
+func (productRequest ProductRequest) ToTime() (time.Time, error) {
+	var errs []error
+	at := parseOptionalTime("at", productRequest.At, &errs)
+	if at == nil {
+		return time.Time{}, errors.Join(errs...)
+	}
+	return *at, nil
+}
+
+func (productListRequest ProductListRequest) ToQuery() (domain.ProductQuery, error) {
+	query := domain.ProductQuery{
+		NameContains:  productListRequest.Name,
+		SortField:     domain.ProductSortField(productListRequest.Sort),
+		SortDirection: domain.SortDirection(productListRequest.Order),
+	}
+
+	var errs []error
+	query.MinPrice = parseOptionalDecimal("min_price", productListRequest.MinPrice, &errs)
+	query.MaxPrice = parseOptionalDecimal("max_price", productListRequest.MaxPrice, &errs)
+	query.MinDiscount = parseOptionalDecimal("min_discount", productListRequest.MinDiscount, &errs)
+	query.MaxDiscount = parseOptionalDecimal("max_discount", productListRequest.MaxDiscount, &errs)
+	query.Limit = parseOptionalLimit(productListRequest.Limit, &errs)
+	query.Offset = parseOptionalInt("offset", productListRequest.Offset, &errs)
+	query.CategoryId = parseOptionalInt64("category", productListRequest.Category, &errs)
+	query.IncludeDeleted = parseOptionalBool("include_deleted", productListRequest.IncludeDeleted, &errs)
+	if at := parseOptionalTime("at", productListRequest.At, &errs); at != nil {
+		query.At = *at
+	}
+
+	if productListRequest.Cursor != "" {
+		cursor, cursorErr := domain.DecodeProductCursor(productListRequest.Cursor)
+		if cursorErr != nil {
+			errs = append(errs, cursorErr)
+		} else {
+			query.Cursor = &cursor
+		}
+	}
+
+	return query, errors.Join(errs...)
+}
+
+func (productSearchRequest ProductSearchRequest) ToQuery() (domain.ProductSearchQuery, error) {
+	query := domain.ProductSearchQuery{Text: productSearchRequest.Q}
+
+	var errs []error
+	query.StoreId = parseOptionalInt64("store", productSearchRequest.Store, &errs)
+	query.Limit = parseOptionalLimit(productSearchRequest.Limit, &errs)
+	query.Offset = parseOptionalInt("offset", productSearchRequest.Offset, &errs)
+	if at := parseOptionalTime("at", productSearchRequest.At, &errs); at != nil {
+		query.At = *at
+	}
+
+	return query, errors.Join(errs...)
+}
+
+func parseOptionalDecimal(name string, value string, errs *[]error) *domain.Decimal {
+	if value == "" {
+		return nil
+	}
+	parsed, parseErr := domain.ParseDecimal(value)
+	if parseErr != nil {
+		*errs = append(*errs, fmt.Errorf("%s %w", name, parseErr))
+		return nil
+	}
+	return &parsed
+}
+
+func parseOptionalTime(name string, value string, errs *[]error) *time.Time {
+	if value == "" {
+		return nil
+	}
+	parsed, parseErr := time.Parse(time.RFC3339, value)
+	if parseErr != nil {
+		*errs = append(*errs, fmt.Errorf("%s must be an RFC 3339 timestamp", name))
+		return nil
+	}
+	return &parsed
+}
+
+func parseOptionalInt64(name string, value string, errs *[]error) int64 {
+	if value == "" {
+		return 0
+	}
+	parsed, parseErr := strconv.ParseInt(value, 10, 64)
+	if parseErr != nil {
+		*errs = append(*errs, fmt.Errorf("%s must be an integer", name))
+		return 0
+	}
+	return parsed
+}
+
+func parseOptionalBool(name string, value string, errs *[]error) bool {
+	if value == "" {
+		return false
+	}
+	parsed, parseErr := strconv.ParseBool(value)
+	if parseErr != nil {
+		*errs = append(*errs, fmt.Errorf("%s must be true or false", name))
+		return false
+	}
+	return parsed
+}
+
+// parseOptionalLimit leaves an absent limit at zero, for which the service applies the default page size, so an
+// explicit zero is refused like any other limit out of range instead of being taken for an absent one.
+func parseOptionalLimit(value string, errs *[]error) int {
+	if value == "" {
+		return 0
+	}
+	limit, parseErr := strconv.Atoi(value)
+	if parseErr != nil || limit < 1 || limit > domain.MaxProductPageSize {
+		*errs = append(*errs, fmt.Errorf("limit must be an integer between 1 and %d", domain.MaxProductPageSize))
+		return 0
+	}
+	return limit
+}
+
+func parseOptionalInt(name string, value string, errs *[]error) int {
+	if value == "" {
+		return 0
+	}
+	parsed, parseErr := strconv.Atoi(value)
+	if parseErr != nil {
+		*errs = append(*errs, fmt.Errorf("%s must be an integer", name))
+		return 0
+	}
+	return parsed
+}
@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"Service-schema/core/logging"
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"time"
+)
+
+// requestIdPattern bounds the caller supplied ids that are propagated; any other value is replaced, so a
+// client cannot inject arbitrary content into the logs.
+var requestIdPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestLoggingMiddleware propagates the caller's X-Request-ID or assigns a new one, attaches it and the route
+// to every record logged with the request context, and logs each request once it has been handled. Requests
+// for which skipper returns true are passed through untouched.
+func RequestLoggingMiddleware(logger *slog.Logger, skipper middleware.Skipper) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if skipper(c) {
+				return next(c)
+			}
+			start := time.Now()
+			requestId := c.Request().Header.Get(echo.HeaderXRequestID)
+			if !requestIdPattern.MatchString(requestId) {
+				requestId = newRequestId()
+			}
+			c.Response().Header().Set(echo.HeaderXRequestID, requestId)
+
+			ctx := logging.WithAttrs(c.Request().Context(), slog.String(logging.RequestIdKey, requestId), slog.String(logging.RouteKey, c.Path()))
+			c.SetRequest(c.Request().WithContext(ctx))
+
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+
+			status := c.Response().Status
+			level := slog.LevelInfo
+			if status >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+			logger.LogAttrs(ctx, level, "Handled request",
+				slog.String("method", c.Request().Method),
+				slog.Int("status", status),
+				slog.Duration("latency", time.Since(start)),
+				slog.Int64("bytes_out", c.Response().Size))
+			return nil
+		}
+	}
+}
+
+func newRequestId() string {
+	randomBytes := make([]byte, 16)
+	_, _ = rand.Read(randomBytes)
+	return hex.EncodeToString(randomBytes)
+}
@@ -0,0 +1,162 @@
+package response
+
+import (
+	"Service-schema/domain"
+	"encoding/csv"
+	"encoding/json"
+	"github.com/xuri/excelize/v2"
+	"io"
+	"strconv"
+)
+
+type ProductExportFormat string
+
+const (
+	CSVProductExport    ProductExportFormat = "csv"
+	NDJSONProductExport ProductExportFormat = "ndjson"
+	XLSXProductExport   ProductExportFormat = "xlsx"
+)
+
+const MIMEApplicationXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+const productExportSheet = "Products"
+
+// productExportFlushInterval is the number of CSV rows buffered before they are handed to the client.
+const productExportFlushInterval = 100
+
+var productExportColumns = []string{"id", "name", "price", "currency", "discount", "store_id", "store", "discount_amount", "discounted_price", "version"}
+
+func (format ProductExportFormat) ContentType() string {
+	switch format {
+	case NDJSONProductExport:
+		return "application/x-ndjson"
+	case XLSXProductExport:
+		return MIMEApplicationXLSX
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+func (format ProductExportFormat) FileName() string {
+	return "products." + string(format)
+}
+
+// IProductExportWriter writes exported products one at a time. Close must be called once every product is
+// written to complete the document.
+type IProductExportWriter interface {
+	Write(product domain.Product) error
+	Close() error
+}
+
+func NewProductExportWriter(format ProductExportFormat, writer io.Writer) (IProductExportWriter, error) {
+	switch format {
+	case NDJSONProductExport:
+		return &ndjsonProductExportWriter{encoder: json.NewEncoder(writer)}, nil
+	case XLSXProductExport:
+		return newXLSXProductExportWriter(writer)
+	default:
+		csvWriter := csv.NewWriter(writer)
+		return &csvProductExportWriter{writer: csvWriter}, csvWriter.Write(productExportColumns)
+	}
+}
+
+func productExportRow(product domain.Product) []string {
+	return []string{
+		strconv.FormatInt(product.Id, 10),
+		product.Name,
+		product.Price.String(),
+		product.Price.Currency,
+		product.Discount.String(),
+		strconv.FormatInt(product.StoreId, 10),
+		product.Store,
+		product.DiscountAmount().String(),
+		product.DiscountedPrice().String(),
+		strconv.FormatInt(product.Version, 10),
+	}
+}
+
+type csvProductExportWriter struct {
+	writer       *csv.Writer
+	writtenCount int
+}
+
+func (csvWriter *csvProductExportWriter) Write(product domain.Product) error {
+	if writeErr := csvWriter.writer.Write(productExportRow(product)); writeErr != nil {
+		return writeErr
+	}
+	csvWriter.writtenCount++
+	if csvWriter.writtenCount%productExportFlushInterval == 0 {
+		csvWriter.writer.Flush()
+	}
+	return csvWriter.writer.Error()
+}
+
+func (csvWriter *csvProductExportWriter) Close() error {
+	csvWriter.writer.Flush()
+	return csvWriter.writer.Error()
+}
+
+type ndjsonProductExportWriter struct {
+	encoder *json.Encoder
+}
+
+func (ndjsonWriter *ndjsonProductExportWriter) Write(product domain.Product) error {
+	return ndjsonWriter.encoder.Encode(ToProductResponse(product))
+}
+
+func (ndjsonWriter *ndjsonProductExportWriter) Close() error {
+	return nil
+}
+
+// xlsxProductExportWriter streams rows into the sheet, which excelize keeps in a temporary file rather than in
+// memory once it grows. The workbook is a zip archive whose directory comes last, so nothing reaches the client
+// before Close.
+type xlsxProductExportWriter struct {
+	file         *excelize.File
+	streamWriter *excelize.StreamWriter
+	writer       io.Writer
+	nextRow      int
+}
+
+func newXLSXProductExportWriter(writer io.Writer) (IProductExportWriter, error) {
+	file := excelize.NewFile()
+	if renameErr := file.SetSheetName("Sheet1", productExportSheet); renameErr != nil {
+		return nil, renameErr
+	}
+	streamWriter, streamErr := file.NewStreamWriter(productExportSheet)
+	if streamErr != nil {
+		return nil, streamErr
+	}
+
+	xlsxWriter := &xlsxProductExportWriter{file: file, streamWriter: streamWriter, writer: writer, nextRow: 1}
+	header := make([]interface{}, 0, len(productExportColumns))
+	for _, column := range productExportColumns {
+		header = append(header, column)
+	}
+	return xlsxWriter, xlsxWriter.writeRow(header)
+}
+
+func (xlsxWriter *xlsxProductExportWriter) Write(product domain.Product) error {
+	row := make([]interface{}, 0, len(productExportColumns))
+	for _, value := range productExportRow(product) {
+		row = append(row, value)
+	}
+	return xlsxWriter.writeRow(row)
+}
+
+func (xlsxWriter *xlsxProductExportWriter) writeRow(values []interface{}) error {
+	cell, cellErr := excelize.CoordinatesToCellName(1, xlsxWriter.nextRow)
+	if cellErr != nil {
+		return cellErr
+	}
+	xlsxWriter.nextRow++
+	return xlsxWriter.streamWriter.SetRow(cell, values)
+}
+
+func (xlsxWriter *xlsxProductExportWriter) Close() error {
+	defer xlsxWriter.file.Close()
+	if flushErr := xlsxWriter.streamWriter.Flush(); flushErr != nil {
+		return flushErr
+	}
+	return xlsxWriter.file.Write(xlsxWriter.writer)
+}
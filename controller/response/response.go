@@ -1,27 +1,152 @@
 package response
 
-import "Service-schema/domain"
+import (
+	"Service-schema/core/health"
+	"Service-schema/domain"
+	"time"
+)
+
+type FieldErrorResponse struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
 
 type ErrorResponse struct {
-	ErrorDescription string `json:"error_description"`
+	ErrorCode        string               `json:"error_code"`
+	ErrorDescription string               `json:"error_description"`
+	Fields           []FieldErrorResponse `json:"fields,omitempty"`
 }
 
 type ProductResponse struct {
-	Name     string  `json:"name"`
-	Price    float32 `json:"price"`
-	Discount float32 `json:"discount"`
-	Store    string  `json:"store"`
+	Id              int64          `json:"id"`
+	Name            string         `json:"name"`
+	Price           string         `json:"price"`
+	Currency        string         `json:"currency"`
+	Discount        domain.Decimal `json:"discount"`
+	StoreId         int64          `json:"store_id"`
+	Store           string         `json:"store"`
+	DiscountAmount  string         `json:"discount_amount"`
+	DiscountedPrice string         `json:"discounted_price"`
+	Version         int64          `json:"version"`
+	DeletedAt       *time.Time     `json:"deleted_at,omitempty"`
+
+	AppliedPromotions []AppliedPromotionResponse `json:"applied_promotions"`
+}
+
+type AppliedPromotionResponse struct {
+	Id   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type PromotionResponse struct {
+	Id        int64          `json:"id"`
+	Name      string         `json:"name"`
+	Type      string         `json:"type"`
+	Value     domain.Decimal `json:"value"`
+	Currency  string         `json:"currency,omitempty"`
+	StoreId   *int64         `json:"store_id"`
+	ProductId *int64         `json:"product_id"`
+	Stackable bool           `json:"stackable"`
+	StartsAt  time.Time      `json:"starts_at"`
+	EndsAt    time.Time      `json:"ends_at"`
+}
+
+type StoreResponse struct {
+	Id   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type CategoryResponse struct {
+	Id       int64  `json:"id"`
+	Name     string `json:"name"`
+	ParentId *int64 `json:"parent_id"`
+}
+
+type CategoryTreeResponse struct {
+	Id       int64                  `json:"id"`
+	Name     string                 `json:"name"`
+	ParentId *int64                 `json:"parent_id"`
+	Children []CategoryTreeResponse `json:"children"`
+}
+
+type StockLevelResponse struct {
+	ProductId int64 `json:"product_id"`
+	StoreId   int64 `json:"store_id"`
+	OnHand    int64 `json:"on_hand"`
+	Reserved  int64 `json:"reserved"`
+	Available int64 `json:"available"`
+}
+
+type PriceChangeResponse struct {
+	Id          int64          `json:"id"`
+	ProductName string         `json:"product_name"`
+	OldPrice    string         `json:"old_price"`
+	NewPrice    string         `json:"new_price"`
+	OldCurrency string         `json:"old_currency"`
+	NewCurrency string         `json:"new_currency"`
+	OldDiscount domain.Decimal `json:"old_discount"`
+	NewDiscount domain.Decimal `json:"new_discount"`
+	Actor       string         `json:"actor"`
+	Reason      string         `json:"reason"`
+	ChangedAt   time.Time      `json:"changed_at"`
+}
+
+type ProductPageResponse struct {
+	Items      []ProductResponse `json:"items"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+	TotalCount int64             `json:"total_count"`
+}
+
+type ProductSearchResponse struct {
+	Items      []ProductSearchHitResponse  `json:"items"`
+	TotalCount int64                       `json:"total_count"`
+	Facets     ProductSearchFacetsResponse `json:"facets"`
+}
+
+type ProductSearchHitResponse struct {
+	ProductResponse
+	Rank    float64 `json:"rank"`
+	Snippet string  `json:"snippet"`
+}
+
+type ProductSearchFacetsResponse struct {
+	Stores []StoreFacetResponse `json:"stores"`
+}
+
+type StoreFacetResponse struct {
+	StoreId int64  `json:"store_id"`
+	Store   string `json:"store"`
+	Count   int64  `json:"count"`
 }
 
 func ToProductResponse(product domain.Product) ProductResponse {
 	return ProductResponse{
-		Name:     product.Name,
-		Price:    product.Price,
-		Discount: product.Discount,
-		Store:    product.Store,
+		Id:              product.Id,
+		Name:            product.Name,
+		Price:           product.Price.String(),
+		Currency:        product.Price.Currency,
+		Discount:        product.Discount,
+		StoreId:         product.StoreId,
+		Store:           product.Store,
+		DiscountAmount:  product.DiscountAmount().String(),
+		DiscountedPrice: product.DiscountedPrice().String(),
+		Version:         product.Version,
+		DeletedAt:       product.DeletedAt,
+
+		AppliedPromotions: toAppliedPromotionResponseList(product.AppliedPromotions),
 	}
 }
 
+func toAppliedPromotionResponseList(promotions []domain.Promotion) []AppliedPromotionResponse {
+	var appliedPromotionResponses = []AppliedPromotionResponse{}
+
+	for _, promotion := range promotions {
+		appliedPromotionResponses = append(appliedPromotionResponses, AppliedPromotionResponse{Id: promotion.Id, Name: promotion.Name})
+	}
+
+	return appliedPromotionResponses
+}
+
 func ToProductResponseList(products []domain.Product) []ProductResponse {
 	var productResponses = []ProductResponse{}
 
@@ -31,3 +156,202 @@ func ToProductResponseList(products []domain.Product) []ProductResponse {
 
 	return productResponses
 }
+
+func ToProductPageResponse(productPage domain.ProductPage) ProductPageResponse {
+	return ProductPageResponse{
+		Items:      ToProductResponseList(productPage.Items),
+		NextCursor: productPage.NextCursor,
+		TotalCount: productPage.TotalCount,
+	}
+}
+
+func ToProductSearchResponse(result domain.ProductSearchResult) ProductSearchResponse {
+	searchResponse := ProductSearchResponse{
+		Items:      []ProductSearchHitResponse{},
+		TotalCount: result.TotalCount,
+		Facets:     ProductSearchFacetsResponse{Stores: []StoreFacetResponse{}},
+	}
+	for _, hit := range result.Hits {
+		searchResponse.Items = append(searchResponse.Items, ProductSearchHitResponse{ProductResponse: ToProductResponse(hit.Product), Rank: hit.Rank, Snippet: hit.Snippet})
+	}
+	for _, facet := range result.StoreFacets {
+		searchResponse.Facets.Stores = append(searchResponse.Facets.Stores, StoreFacetResponse{StoreId: facet.StoreId, Store: facet.Store, Count: facet.Count})
+	}
+	return searchResponse
+}
+
+func ToStoreResponse(store domain.Store) StoreResponse {
+	return StoreResponse{
+		Id:   store.Id,
+		Name: store.Name,
+	}
+}
+
+func ToStoreResponseList(stores []domain.Store) []StoreResponse {
+	var storeResponses = []StoreResponse{}
+
+	for _, store := range stores {
+		storeResponses = append(storeResponses, ToStoreResponse(store))
+	}
+
+	return storeResponses
+}
+
+func ToCategoryResponse(category domain.Category) CategoryResponse {
+	return CategoryResponse{
+		Id:       category.Id,
+		Name:     category.Name,
+		ParentId: category.ParentId,
+	}
+}
+
+func ToCategoryResponseList(categories []domain.Category) []CategoryResponse {
+	var categoryResponses = []CategoryResponse{}
+
+	for _, category := range categories {
+		categoryResponses = append(categoryResponses, ToCategoryResponse(category))
+	}
+
+	return categoryResponses
+}
+
+func ToCategoryTreeResponseList(categoryTrees []domain.CategoryTree) []CategoryTreeResponse {
+	var categoryTreeResponses = []CategoryTreeResponse{}
+
+	for _, categoryTree := range categoryTrees {
+		categoryTreeResponses = append(categoryTreeResponses, CategoryTreeResponse{
+			Id:       categoryTree.Id,
+			Name:     categoryTree.Name,
+			ParentId: categoryTree.ParentId,
+			Children: ToCategoryTreeResponseList(categoryTree.Children),
+		})
+	}
+
+	return categoryTreeResponses
+}
+
+func ToStockLevelResponse(stockLevel domain.StockLevel) StockLevelResponse {
+	return StockLevelResponse{
+		ProductId: stockLevel.ProductId,
+		StoreId:   stockLevel.StoreId,
+		OnHand:    stockLevel.OnHand,
+		Reserved:  stockLevel.Reserved,
+		Available: stockLevel.Available(),
+	}
+}
+
+func ToStockLevelResponseList(stockLevels []domain.StockLevel) []StockLevelResponse {
+	var stockLevelResponses = []StockLevelResponse{}
+
+	for _, stockLevel := range stockLevels {
+		stockLevelResponses = append(stockLevelResponses, ToStockLevelResponse(stockLevel))
+	}
+
+	return stockLevelResponses
+}
+
+func ToPriceChangeResponseList(priceChanges []domain.PriceChange) []PriceChangeResponse {
+	var priceChangeResponses = []PriceChangeResponse{}
+
+	for _, priceChange := range priceChanges {
+		priceChangeResponses = append(priceChangeResponses, PriceChangeResponse{
+			Id:          priceChange.Id,
+			ProductName: priceChange.ProductName,
+			OldPrice:    priceChange.OldPrice.String(),
+			NewPrice:    priceChange.NewPrice.String(),
+			OldCurrency: priceChange.OldPrice.Currency,
+			NewCurrency: priceChange.NewPrice.Currency,
+			OldDiscount: priceChange.OldDiscount,
+			NewDiscount: priceChange.NewDiscount,
+			Actor:       priceChange.Actor,
+			Reason:      priceChange.Reason,
+			ChangedAt:   priceChange.ChangedAt,
+		})
+	}
+
+	return priceChangeResponses
+}
+
+func ToPromotionResponse(promotion domain.Promotion) PromotionResponse {
+	return PromotionResponse{
+		Id:        promotion.Id,
+		Name:      promotion.Name,
+		Type:      string(promotion.Type),
+		Value:     promotion.Value,
+		Currency:  promotion.Currency,
+		StoreId:   promotion.StoreId,
+		ProductId: promotion.ProductId,
+		Stackable: promotion.Stackable,
+		StartsAt:  promotion.StartsAt,
+		EndsAt:    promotion.EndsAt,
+	}
+}
+
+func ToPromotionResponseList(promotions []domain.Promotion) []PromotionResponse {
+	var promotionResponses = []PromotionResponse{}
+
+	for _, promotion := range promotions {
+		promotionResponses = append(promotionResponses, ToPromotionResponse(promotion))
+	}
+
+	return promotionResponses
+}
+
+type ProductImportReportResponse struct {
+	Mode    string                     `json:"mode"`
+	Created int                        `json:"created"`
+	Failed  int                        `json:"failed"`
+	Rows    []ProductImportRowResponse `json:"rows"`
+}
+
+type ProductImportRowResponse struct {
+	Row       int                  `json:"row"`
+	Status    string               `json:"status"`
+	ProductId int64                `json:"product_id,omitempty"`
+	Error     string               `json:"error,omitempty"`
+	Fields    []FieldErrorResponse `json:"fields,omitempty"`
+}
+
+func ToProductImportReportResponse(report domain.ProductImportReport) ProductImportReportResponse {
+	var rowResponses = []ProductImportRowResponse{}
+
+	for _, row := range report.Rows {
+		rowResponse := ProductImportRowResponse{Row: row.Row, Status: string(row.Status), ProductId: row.ProductId, Error: row.Message}
+		for _, field := range row.Fields {
+			rowResponse.Fields = append(rowResponse.Fields, FieldErrorResponse{Field: field.Field, Message: field.Message})
+		}
+		rowResponses = append(rowResponses, rowResponse)
+	}
+
+	return ProductImportReportResponse{
+		Mode:    string(report.Mode),
+		Created: report.Created,
+		Failed:  report.Failed,
+		Rows:    rowResponses,
+	}
+}
+
+type HealthResponse struct {
+	Status string                         `json:"status"`
+	Checks map[string]HealthCheckResponse `json:"checks,omitempty"`
+}
+
+type HealthCheckResponse struct {
+	Status     string    `json:"status"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs float64   `json:"duration_ms"`
+	CheckedAt  time.Time `json:"checked_at"`
+}
+
+func ToHealthResponse(report health.Report) HealthResponse {
+	checks := make(map[string]HealthCheckResponse, len(report.Checks))
+	for name, result := range report.Checks {
+		checks[name] = HealthCheckResponse{
+			Status:     result.Status,
+			Error:      result.Error,
+			DurationMs: float64(result.Duration.Microseconds()) / 1000,
+			CheckedAt:  result.CheckedAt,
+		}
+	}
+	return HealthResponse{Status: report.Status, Checks: checks}
+}
@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"Service-schema/controller/request"
+	"Service-schema/controller/response"
+	"Service-schema/domain"
+	"Service-schema/service"
+	"Service-schema/service/dto"
+	"context"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+type StockController struct {
+	stockService service.IStockService
+}
+
+func NewStockController(stockService service.IStockService) *StockController {
+	return &StockController{stockService: stockService}
+}
+
+func (stockController *StockController) RegisterRoutes(e *echo.Echo) {
+
+	e.GET("/api/v1/products/:id/stock", stockController.GetProductStock)
+	e.POST("/api/v1/products/:id/stock/adjust", stockController.Adjust)
+	e.POST("/api/v1/products/:id/stock/reserve", stockController.Reserve)
+	e.POST("/api/v1/products/:id/stock/release", stockController.Release)
+	e.POST("/api/v1/products/:id/stock/commit", stockController.Commit)
+	e.GET("/api/v1/stores/:id/stock", stockController.GetStoreStock)
+}
+
+func (stockController *StockController) GetProductStock(c echo.Context) error {
+	productId, convertErr := parseProductId(c)
+	if convertErr != nil {
+		return badRequest(convertErr)
+	}
+
+	stockLevel, err := stockController.stockService.GetProductStock(c.Request().Context(), productId)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, response.ToStockLevelResponse(stockLevel))
+}
+
+func (stockController *StockController) GetStoreStock(c echo.Context) error {
+	storeId, convertErr := parseStoreId(c)
+	if convertErr != nil {
+		return badRequest(convertErr)
+	}
+
+	stockLevels, err := stockController.stockService.GetStoreStock(c.Request().Context(), storeId)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, response.ToStockLevelResponseList(stockLevels))
+}
+
+func (stockController *StockController) Adjust(c echo.Context) error {
+	return stockController.applyOperation(c, stockController.stockService.Adjust)
+}
+
+func (stockController *StockController) Reserve(c echo.Context) error {
+	return stockController.applyOperation(c, stockController.stockService.Reserve)
+}
+
+func (stockController *StockController) Release(c echo.Context) error {
+	return stockController.applyOperation(c, stockController.stockService.Release)
+}
+
+func (stockController *StockController) Commit(c echo.Context) error {
+	return stockController.applyOperation(c, stockController.stockService.Commit)
+}
+
+func (stockController *StockController) applyOperation(c echo.Context, operation func(ctx context.Context, stockOperationRequestDto dto.StockOperationRequestDto) (domain.StockLevel, error)) error {
+	productId, convertErr := parseProductId(c)
+	if convertErr != nil {
+		return badRequest(convertErr)
+	}
+
+	var stockOperationRequest request.StockOperationRequest
+	bindErr := c.Bind(&stockOperationRequest)
+	if bindErr != nil {
+		return bindErr
+	}
+
+	stockLevel, err := operation(c.Request().Context(), stockOperationRequest.ToDto(productId))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, response.ToStockLevelResponse(stockLevel))
+}
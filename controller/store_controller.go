@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"Service-schema/controller/request"
+	"Service-schema/controller/response"
+	"Service-schema/service"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"strconv"
+)
+
+type StoreController struct {
+	storeService service.IStoreService
+}
+
+func NewStoreController(storeService service.IStoreService) *StoreController {
+	return &StoreController{storeService: storeService}
+}
+
+func (storeController *StoreController) RegisterRoutes(e *echo.Echo) {
+
+	e.GET("/api/v1/stores/:id", storeController.GetStoreById)
+	e.GET("/api/v1/stores/", storeController.GetAllStores)
+	e.POST("/api/v1/stores/", storeController.Add)
+	e.PUT("/api/v1/stores/:id", storeController.Update)
+	e.DELETE("/api/v1/stores/:id", storeController.DeleteStoreById)
+}
+
+func (storeController *StoreController) GetStoreById(c echo.Context) error {
+	storeId, convertErr := parseStoreId(c)
+	if convertErr != nil {
+		return badRequest(convertErr)
+	}
+
+	store, err := storeController.storeService.GetById(c.Request().Context(), storeId)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, response.ToStoreResponse(store))
+}
+
+func (storeController *StoreController) GetAllStores(c echo.Context) error {
+	stores, err := storeController.storeService.GetAllStores(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, response.ToStoreResponseList(stores))
+}
+
+func (storeController *StoreController) Add(c echo.Context) error {
+	var createStoreRequest request.CreateStoreRequest
+	bindErr := c.Bind(&createStoreRequest)
+	if bindErr != nil {
+		return bindErr
+	}
+
+	store, err := storeController.storeService.Add(c.Request().Context(), createStoreRequest.ToDto())
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set(echo.HeaderLocation, fmt.Sprintf("/api/v1/stores/%d", store.Id))
+	return c.JSON(http.StatusCreated, response.ToStoreResponse(store))
+}
+
+func (storeController *StoreController) Update(c echo.Context) error {
+	storeId, convertErr := parseStoreId(c)
+	if convertErr != nil {
+		return badRequest(convertErr)
+	}
+
+	var updateStoreRequest request.UpdateStoreRequest
+	bindErr := c.Bind(&updateStoreRequest)
+	if bindErr != nil {
+		return bindErr
+	}
+
+	store, err := storeController.storeService.Update(c.Request().Context(), updateStoreRequest.ToDto(storeId))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, response.ToStoreResponse(store))
+}
+
+func (storeController *StoreController) DeleteStoreById(c echo.Context) error {
+	storeId, convertErr := parseStoreId(c)
+	if convertErr != nil {
+		return badRequest(convertErr)
+	}
+
+	err := storeController.storeService.Delete(c.Request().Context(), storeId)
+	if err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusAccepted)
+}
+
+func parseStoreId(c echo.Context) (int64, error) {
+	return strconv.ParseInt(c.Param("id"), 10, 64)
+}
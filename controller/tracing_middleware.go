@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+)
+
+const controllerTracerName = "Service-schema/controller"
+
+// TracingMiddleware starts a server span for every request, continuing the trace of the caller when the
+// request carries trace context headers, and stores it in the request context for the spans of the layers below.
+// Requests for which skipper returns true are not traced.
+func TracingMiddleware(tracerProvider trace.TracerProvider, propagator propagation.TextMapPropagator, skipper middleware.Skipper) echo.MiddlewareFunc {
+	tracer := tracerProvider.Tracer(controllerTracerName)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if skipper(c) {
+				return next(c)
+			}
+			route := c.Path()
+			if route == "" || route == "/*" {
+				route = unmatchedRoute
+			}
+
+			ctx := propagator.Extract(c.Request().Context(), propagation.HeaderCarrier(c.Request().Header))
+			ctx, span := tracer.Start(ctx, c.Request().Method+" "+route,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(semconv.HTTPRequestMethodKey.String(c.Request().Method), semconv.HTTPRoute(route), semconv.URLPath(c.Request().URL.Path)))
+			defer span.End()
+			c.SetRequest(c.Request().WithContext(ctx))
+
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+
+			status := c.Response().Status
+			span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+			if status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(status))
+			}
+			return nil
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package app
+
+import (
+	"Service-schema/core/server"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"log/slog"
+	"net/http"
+	"os/signal"
+	"syscall"
+)
+
+type shutdownHook struct {
+	name  string
+	close func(ctx context.Context) error
+}
+
+type Application struct {
+	echo          *echo.Echo
+	serverConfig  server.Config
+	shutdownHooks []shutdownHook
+	logger        *slog.Logger
+}
+
+func NewApplication(e *echo.Echo, serverConfig server.Config, logger *slog.Logger) *Application {
+	e.Server.ReadTimeout = serverConfig.ReadTimeout
+	e.Server.WriteTimeout = serverConfig.WriteTimeout
+	return &Application{
+		echo:         e,
+		serverConfig: serverConfig,
+		logger:       logger,
+	}
+}
+
+// OnShutdown registers a resource to release once the HTTP server has drained.
+// Hooks run in reverse registration order, so dependencies registered first are closed last.
+func (application *Application) OnShutdown(name string, close func(ctx context.Context) error) {
+	application.shutdownHooks = append(application.shutdownHooks, shutdownHook{name: name, close: close})
+}
+
+// Run serves HTTP until SIGINT or SIGTERM is received or the server fails to start, then shuts down gracefully.
+func (application *Application) Run(ctx context.Context) error {
+	signalCtx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	serverErrs := make(chan error, 1)
+	go func() {
+		serverErrs <- application.echo.Start(application.serverConfig.Address())
+	}()
+
+	var serveErr error
+	select {
+	case startErr := <-serverErrs:
+		if !errors.Is(startErr, http.ErrServerClosed) {
+			serveErr = fmt.Errorf("http server: %w", startErr)
+		}
+	case <-signalCtx.Done():
+		application.logger.InfoContext(ctx, "Shutdown signal received, draining in-flight requests")
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), application.serverConfig.ShutdownTimeout)
+	defer cancel()
+
+	var errs []error
+	errs = append(errs, serveErr)
+	if shutdownErr := application.echo.Shutdown(shutdownCtx); shutdownErr != nil {
+		errs = append(errs, fmt.Errorf("http server shutdown: %w", shutdownErr))
+	}
+
+	for index := len(application.shutdownHooks) - 1; index >= 0; index-- {
+		hook := application.shutdownHooks[index]
+		if closeErr := hook.close(shutdownCtx); closeErr != nil {
+			errs = append(errs, fmt.Errorf("%s shutdown: %w", hook.name, closeErr))
+		}
+	}
+
+	application.logger.InfoContext(ctx, "Application stopped")
+	return errors.Join(errs...)
+}
@@ -0,0 +1,265 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const configFileFlag = "config"
+const configFileEnvironmentVariable = "CONFIG_FILE"
+
+type configurationKey struct {
+	name         string
+	defaultValue string
+	apply        func(configurationManager *ConfigurationManager, value string) error
+}
+
+func configurationKeys() []configurationKey {
+	return []configurationKey{
+		stringKey("postgresql.host", "localhost", func(m *ConfigurationManager) *string { return &m.PostgresqlConfig.Host }),
+		intKey("postgresql.port", "6432", func(m *ConfigurationManager) *int { return &m.PostgresqlConfig.Port }),
+		stringKey("postgresql.user_name", "postgres", func(m *ConfigurationManager) *string { return &m.PostgresqlConfig.UserName }),
+		stringKey("postgresql.password", "password", func(m *ConfigurationManager) *string { return &m.PostgresqlConfig.Password }),
+		stringKey("postgresql.db_name", "product_service", func(m *ConfigurationManager) *string { return &m.PostgresqlConfig.DbName }),
+		intKey("postgresql.max_connections", "10", func(m *ConfigurationManager) *int { return &m.PostgresqlConfig.MaxConnections }),
+		durationKey("postgresql.max_connection_idle_time", "30s", func(m *ConfigurationManager) *time.Duration { return &m.PostgresqlConfig.MaxConnectionIdleTime }),
+		durationKey("postgresql.read_query_timeout", "5s", func(m *ConfigurationManager) *time.Duration { return &m.PostgresqlConfig.QueryTimeouts.Read }),
+		durationKey("postgresql.write_query_timeout", "10s", func(m *ConfigurationManager) *time.Duration { return &m.PostgresqlConfig.QueryTimeouts.Write }),
+		durationKey("postgresql.export_query_timeout", "10m", func(m *ConfigurationManager) *time.Duration { return &m.PostgresqlConfig.QueryTimeouts.Export }),
+		stringKey("postgresql.isolation_level", "repeatable read", func(m *ConfigurationManager) *string { return &m.PostgresqlConfig.TransactionOptions.IsolationLevel }),
+		intKey("postgresql.serialization_retries", "3", func(m *ConfigurationManager) *int { return &m.PostgresqlConfig.TransactionOptions.MaxRetries }),
+		durationKey("postgresql.connect_retry_timeout", "1m", func(m *ConfigurationManager) *time.Duration { return &m.PostgresqlConfig.ConnectRetry.Timeout }),
+		durationKey("postgresql.connect_retry_initial_backoff", "500ms", func(m *ConfigurationManager) *time.Duration { return &m.PostgresqlConfig.ConnectRetry.InitialBackoff }),
+		durationKey("postgresql.connect_retry_max_backoff", "10s", func(m *ConfigurationManager) *time.Duration { return &m.PostgresqlConfig.ConnectRetry.MaxBackoff }),
+		stringKey("server.host", "localhost", func(m *ConfigurationManager) *string { return &m.ServerConfig.Host }),
+		intKey("server.port", "8080", func(m *ConfigurationManager) *int { return &m.ServerConfig.Port }),
+		durationKey("server.read_timeout", "10s", func(m *ConfigurationManager) *time.Duration { return &m.ServerConfig.ReadTimeout }),
+		durationKey("server.write_timeout", "10s", func(m *ConfigurationManager) *time.Duration { return &m.ServerConfig.WriteTimeout }),
+		durationKey("server.shutdown_timeout", "15s", func(m *ConfigurationManager) *time.Duration { return &m.ServerConfig.ShutdownTimeout }),
+		durationKey("purge.retention", "720h", func(m *ConfigurationManager) *time.Duration { return &m.PurgeConfig.Retention }),
+		durationKey("purge.interval", "1h", func(m *ConfigurationManager) *time.Duration { return &m.PurgeConfig.Interval }),
+		stringKey("logging.format", "text", func(m *ConfigurationManager) *string { return &m.LoggingConfig.Format }),
+		stringKey("logging.level", "info", func(m *ConfigurationManager) *string { return &m.LoggingConfig.Level }),
+		stringKey("tracing.exporter", "none", func(m *ConfigurationManager) *string { return &m.TracingConfig.Exporter }),
+		stringKey("tracing.otlp_endpoint", "http://localhost:4318", func(m *ConfigurationManager) *string { return &m.TracingConfig.OTLPEndpoint }),
+		stringKey("tracing.service_name", "product-service", func(m *ConfigurationManager) *string { return &m.TracingConfig.ServiceName }),
+		stringKey("auth.admin_token_sha256", "", func(m *ConfigurationManager) *string { return &m.AuthConfig.AdminTokenSHA256 }),
+		boolKey("auth.require_token_for_price_changes", "false", func(m *ConfigurationManager) *bool { return &m.AuthConfig.RequireTokenForPriceChanges }),
+		durationKey("health.check_timeout", "2s", func(m *ConfigurationManager) *time.Duration { return &m.HealthConfig.CheckTimeout }),
+		durationKey("health.cache_ttl", "5s", func(m *ConfigurationManager) *time.Duration { return &m.HealthConfig.CacheTTL }),
+		intKey("health.pool_saturation_percent", "90", func(m *ConfigurationManager) *int { return &m.HealthConfig.PoolSaturationPercent }),
+	}
+}
+
+func stringKey(name string, defaultValue string, field func(m *ConfigurationManager) *string) configurationKey {
+	return configurationKey{
+		name:         name,
+		defaultValue: defaultValue,
+		apply: func(configurationManager *ConfigurationManager, value string) error {
+			*field(configurationManager) = value
+			return nil
+		},
+	}
+}
+
+func intKey(name string, defaultValue string, field func(m *ConfigurationManager) *int) configurationKey {
+	return configurationKey{
+		name:         name,
+		defaultValue: defaultValue,
+		apply: func(configurationManager *ConfigurationManager, value string) error {
+			parsed, parseErr := strconv.Atoi(value)
+			if parseErr != nil {
+				return fmt.Errorf("%q is not an integer", value)
+			}
+			*field(configurationManager) = parsed
+			return nil
+		},
+	}
+}
+
+func boolKey(name string, defaultValue string, field func(m *ConfigurationManager) *bool) configurationKey {
+	return configurationKey{
+		name:         name,
+		defaultValue: defaultValue,
+		apply: func(configurationManager *ConfigurationManager, value string) error {
+			parsed, parseErr := strconv.ParseBool(value)
+			if parseErr != nil {
+				return fmt.Errorf("%q is not a boolean", value)
+			}
+			*field(configurationManager) = parsed
+			return nil
+		},
+	}
+}
+
+func durationKey(name string, defaultValue string, field func(m *ConfigurationManager) *time.Duration) configurationKey {
+	return configurationKey{
+		name:         name,
+		defaultValue: defaultValue,
+		apply: func(configurationManager *ConfigurationManager, value string) error {
+			parsed, parseErr := time.ParseDuration(value)
+			if parseErr != nil {
+				return fmt.Errorf("%q is not a duration", value)
+			}
+			*field(configurationManager) = parsed
+			return nil
+		},
+	}
+}
+
+// environmentVariableName maps a key such as postgresql.max_connections to POSTGRESQL_MAX_CONNECTIONS.
+func environmentVariableName(key string) string {
+	return strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+type configurationLoader struct {
+	keys []configurationKey
+}
+
+func newConfigurationLoader(keys []configurationKey) *configurationLoader {
+	return &configurationLoader{keys: keys}
+}
+
+// load applies defaults, the configuration file, environment variables and command line flags in that order.
+// It returns the positional arguments left after flag parsing and one error per invalid key; a key that fails
+// to parse keeps its default so that validation does not report it twice.
+func (loader *configurationLoader) load(configurationManager *ConfigurationManager, args []string) ([]string, []error, error) {
+	values := map[string]string{}
+	for _, key := range loader.keys {
+		values[key.name] = key.defaultValue
+		if defaultErr := key.apply(configurationManager, key.defaultValue); defaultErr != nil {
+			panic(defaultErr)
+		}
+	}
+
+	flagValues, configFile, remainingArgs, flagErr := loader.parseFlags(args)
+	if flagErr != nil {
+		return nil, nil, flagErr
+	}
+
+	if configFile == "" {
+		configFile = os.Getenv(configFileEnvironmentVariable)
+	}
+
+	var errs []error
+	if configFile != "" {
+		fileValues, fileErr := readConfigurationFile(configFile)
+		if fileErr != nil {
+			return nil, nil, fileErr
+		}
+		for _, name := range slices.Sorted(maps.Keys(fileValues)) {
+			value := fileValues[name]
+			if _, known := values[name]; !known {
+				errs = append(errs, invalidKeyError(name, "unknown key in "+configFile))
+				continue
+			}
+			values[name] = value
+		}
+	}
+
+	for _, key := range loader.keys {
+		if value, found := os.LookupEnv(environmentVariableName(key.name)); found {
+			values[key.name] = value
+		}
+	}
+
+	for name, value := range flagValues {
+		values[name] = value
+	}
+
+	for _, key := range loader.keys {
+		if values[key.name] == key.defaultValue {
+			continue
+		}
+		if applyErr := key.apply(configurationManager, values[key.name]); applyErr != nil {
+			errs = append(errs, invalidKeyError(key.name, applyErr.Error()))
+		}
+	}
+
+	return remainingArgs, errs, nil
+}
+
+func (loader *configurationLoader) parseFlags(args []string) (map[string]string, string, []string, error) {
+	flagSet := flag.NewFlagSet(filepath.Base(os.Args[0]), flag.ContinueOnError)
+	configFile := flagSet.String(configFileFlag, "", "path to a YAML or JSON configuration file")
+	for _, key := range loader.keys {
+		flagSet.String(key.name, key.defaultValue, "overrides "+environmentVariableName(key.name))
+	}
+
+	// Flags may follow positional arguments, as in "migrate up -postgresql.host=db"; parsing resumes after each
+	// positional argument until the arguments run out or "--" ends the flags.
+	var positionalArgs []string
+	for remainingArgs := args; ; {
+		if parseErr := flagSet.Parse(remainingArgs); parseErr != nil {
+			return nil, "", nil, parseErr
+		}
+		unparsedArgs := flagSet.Args()
+		terminated := len(unparsedArgs) < len(remainingArgs) && remainingArgs[len(remainingArgs)-len(unparsedArgs)-1] == "--"
+		if len(unparsedArgs) == 0 || terminated {
+			positionalArgs = append(positionalArgs, unparsedArgs...)
+			break
+		}
+		positionalArgs = append(positionalArgs, unparsedArgs[0])
+		remainingArgs = unparsedArgs[1:]
+	}
+
+	flagValues := map[string]string{}
+	flagSet.Visit(func(setFlag *flag.Flag) {
+		if setFlag.Name != configFileFlag {
+			flagValues[setFlag.Name] = setFlag.Value.String()
+		}
+	})
+
+	return flagValues, *configFile, positionalArgs, nil
+}
+
+func readConfigurationFile(path string) (map[string]string, error) {
+	content, readErr := os.ReadFile(path)
+	if readErr != nil {
+		return nil, fmt.Errorf("reading configuration file: %w", readErr)
+	}
+
+	document := map[string]interface{}{}
+	var unmarshalErr error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		// Numbers stay json.Number so that large integers are not flattened into float notation such as 1e+06.
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.UseNumber()
+		unmarshalErr = decoder.Decode(&document)
+	case ".yaml", ".yml":
+		unmarshalErr = yaml.Unmarshal(content, &document)
+	default:
+		return nil, fmt.Errorf("unsupported configuration file format %q", filepath.Ext(path))
+	}
+	if unmarshalErr != nil {
+		return nil, fmt.Errorf("parsing configuration file %s: %w", path, unmarshalErr)
+	}
+
+	values := map[string]string{}
+	flattenConfigurationDocument("", document, values)
+	return values, nil
+}
+
+func flattenConfigurationDocument(prefix string, document map[string]interface{}, values map[string]string) {
+	for name, value := range document {
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+		if section, isSection := value.(map[string]interface{}); isSection {
+			flattenConfigurationDocument(name, section, values)
+			continue
+		}
+		values[name] = fmt.Sprint(value)
+	}
+}
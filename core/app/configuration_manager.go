@@ -1,26 +1,151 @@
 package app
 
-import "Service-schema/core/postgresql"
+import (
+	"Service-schema/core/auth"
+	"Service-schema/core/health"
+	"Service-schema/core/logging"
+	"Service-schema/core/postgresql"
+	"Service-schema/core/purge"
+	"Service-schema/core/server"
+	"Service-schema/core/tracing"
+	"errors"
+	"fmt"
+	"net/url"
+)
 
 type ConfigurationManager struct {
 	PostgresqlConfig postgresql.Config
+	ServerConfig     server.Config
+	PurgeConfig      purge.Config
+	LoggingConfig    logging.Config
+	TracingConfig    tracing.Config
+	HealthConfig     health.Config
+	AuthConfig       auth.Config
+	Args             []string
 }
 
-func NewConfigurationManager() *ConfigurationManager {
-	postgreSqlConfig := getPostgreSqlConfig()
-	return &ConfigurationManager{
-		PostgresqlConfig: postgreSqlConfig,
+func NewConfigurationManager(args []string) (*ConfigurationManager, error) {
+	configurationManager := &ConfigurationManager{}
+
+	remainingArgs, keyErrs, loadErr := newConfigurationLoader(configurationKeys()).load(configurationManager, args)
+	if loadErr != nil {
+		return nil, loadErr
+	}
+	configurationManager.Args = remainingArgs
+
+	validationErr := errors.Join(configurationManager.validate(keyErrs)...)
+	if validationErr != nil {
+		return nil, validationErr
 	}
+
+	return configurationManager, nil
 }
 
-func getPostgreSqlConfig() postgresql.Config {
-	return postgresql.Config{
-		Host:                  "localhost",
-		Port:                  "6432",
-		UserName:              "postgres",
-		Password:              "password",
-		DbName:                "product_service",
-		MaxConnections:        "10",
-		MaxConnectionIdleTime: "30s",
+func (configurationManager *ConfigurationManager) validate(errs []error) []error {
+	postgresqlConfig := configurationManager.PostgresqlConfig
+	if postgresqlConfig.Host == "" {
+		errs = append(errs, invalidKeyError("postgresql.host", "must be specified"))
+	}
+	if postgresqlConfig.Port < 1 || postgresqlConfig.Port > 65535 {
+		errs = append(errs, invalidKeyError("postgresql.port", "must be between 1 and 65535"))
+	}
+	if postgresqlConfig.UserName == "" {
+		errs = append(errs, invalidKeyError("postgresql.user_name", "must be specified"))
+	}
+	if postgresqlConfig.DbName == "" {
+		errs = append(errs, invalidKeyError("postgresql.db_name", "must be specified"))
+	}
+	if postgresqlConfig.MaxConnections < 1 {
+		errs = append(errs, invalidKeyError("postgresql.max_connections", "must be greater than 0"))
+	}
+	if postgresqlConfig.MaxConnectionIdleTime < 0 {
+		errs = append(errs, invalidKeyError("postgresql.max_connection_idle_time", "must not be negative"))
+	}
+	if postgresqlConfig.QueryTimeouts.Read <= 0 {
+		errs = append(errs, invalidKeyError("postgresql.read_query_timeout", "must be greater than 0"))
+	}
+	if postgresqlConfig.QueryTimeouts.Write <= 0 {
+		errs = append(errs, invalidKeyError("postgresql.write_query_timeout", "must be greater than 0"))
+	}
+	if postgresqlConfig.QueryTimeouts.Export <= 0 {
+		errs = append(errs, invalidKeyError("postgresql.export_query_timeout", "must be greater than 0"))
+	}
+	if !postgresql.IsValidIsolationLevel(postgresqlConfig.TransactionOptions.IsolationLevel) {
+		errs = append(errs, invalidKeyError("postgresql.isolation_level", "must be one of serializable, repeatable read, read committed or read uncommitted"))
+	}
+	if postgresqlConfig.TransactionOptions.MaxRetries < 0 {
+		errs = append(errs, invalidKeyError("postgresql.serialization_retries", "must not be negative"))
+	}
+	if postgresqlConfig.ConnectRetry.Timeout < 0 {
+		errs = append(errs, invalidKeyError("postgresql.connect_retry_timeout", "must not be negative"))
+	}
+	if postgresqlConfig.ConnectRetry.InitialBackoff <= 0 {
+		errs = append(errs, invalidKeyError("postgresql.connect_retry_initial_backoff", "must be greater than 0"))
+	}
+	if postgresqlConfig.ConnectRetry.MaxBackoff < postgresqlConfig.ConnectRetry.InitialBackoff {
+		errs = append(errs, invalidKeyError("postgresql.connect_retry_max_backoff", "must not be less than postgresql.connect_retry_initial_backoff"))
+	}
+
+	serverConfig := configurationManager.ServerConfig
+	if serverConfig.Port < 1 || serverConfig.Port > 65535 {
+		errs = append(errs, invalidKeyError("server.port", "must be between 1 and 65535"))
+	}
+	if serverConfig.ReadTimeout < 0 {
+		errs = append(errs, invalidKeyError("server.read_timeout", "must not be negative"))
+	}
+	if serverConfig.WriteTimeout < 0 {
+		errs = append(errs, invalidKeyError("server.write_timeout", "must not be negative"))
 	}
+	if serverConfig.ShutdownTimeout <= 0 {
+		errs = append(errs, invalidKeyError("server.shutdown_timeout", "must be greater than 0"))
+	}
+
+	purgeConfig := configurationManager.PurgeConfig
+	if purgeConfig.Retention <= 0 {
+		errs = append(errs, invalidKeyError("purge.retention", "must be greater than 0"))
+	}
+	if purgeConfig.Interval <= 0 {
+		errs = append(errs, invalidKeyError("purge.interval", "must be greater than 0"))
+	}
+
+	loggingConfig := configurationManager.LoggingConfig
+	if !logging.IsValidFormat(loggingConfig.Format) {
+		errs = append(errs, invalidKeyError("logging.format", "must be text or json"))
+	}
+	if _, levelErr := logging.ParseLevel(loggingConfig.Level); levelErr != nil {
+		errs = append(errs, invalidKeyError("logging.level", "must be one of debug, info, warn or error"))
+	}
+
+	tracingConfig := configurationManager.TracingConfig
+	if !tracing.IsValidExporter(tracingConfig.Exporter) {
+		errs = append(errs, invalidKeyError("tracing.exporter", "must be none, stdout or otlp"))
+	}
+	if endpoint, parseErr := url.Parse(tracingConfig.OTLPEndpoint); tracingConfig.Exporter == tracing.OTLPExporter && (parseErr != nil || endpoint.Host == "") {
+		errs = append(errs, invalidKeyError("tracing.otlp_endpoint", "must be an absolute URL such as http://localhost:4318"))
+	}
+	if tracingConfig.ServiceName == "" {
+		errs = append(errs, invalidKeyError("tracing.service_name", "must be specified"))
+	}
+
+	authConfig := configurationManager.AuthConfig
+	if authConfig.AdminTokenSHA256 != "" && !auth.IsValidTokenDigest(authConfig.AdminTokenSHA256) {
+		errs = append(errs, invalidKeyError("auth.admin_token_sha256", "must be 64 hexadecimal characters"))
+	}
+
+	healthConfig := configurationManager.HealthConfig
+	if healthConfig.CheckTimeout <= 0 {
+		errs = append(errs, invalidKeyError("health.check_timeout", "must be greater than 0"))
+	}
+	if healthConfig.CacheTTL < 0 {
+		errs = append(errs, invalidKeyError("health.cache_ttl", "must not be negative"))
+	}
+	if healthConfig.PoolSaturationPercent < 1 || healthConfig.PoolSaturationPercent > 100 {
+		errs = append(errs, invalidKeyError("health.pool_saturation_percent", "must be between 1 and 100"))
+	}
+
+	return errs
+}
+
+func invalidKeyError(key string, reason string) error {
+	return fmt.Errorf("invalid configuration %s: %s", key, reason)
 }
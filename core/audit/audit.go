@@ -0,0 +1,28 @@
+package audit
+
+import "context"
+
+const (
+	AnonymousActor = "anonymous"
+	AdminActor     = "admin"
+)
+
+// Change describes who made a change and why, so that audited writes can record it next to the new values.
+type Change struct {
+	Actor  string
+	Reason string
+}
+
+type changeKey struct{}
+
+func WithChange(ctx context.Context, change Change) context.Context {
+	return context.WithValue(ctx, changeKey{}, change)
+}
+
+func FromContext(ctx context.Context) Change {
+	change, _ := ctx.Value(changeKey{}).(Change)
+	if change.Actor == "" {
+		change.Actor = AnonymousActor
+	}
+	return change
+}
@@ -0,0 +1,49 @@
+// Package auth recognizes the administrator by bearer token.
+//
+// Trust model: the deployment configures the SHA-256 of a single admin token. A caller presenting that token is the
+// administrator and may list deleted products; every other caller is anonymous. Headers such as X-Actor are never
+// trusted to name the caller.
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+)
+
+type adminKey struct{}
+
+func WithAdmin(ctx context.Context) context.Context {
+	return context.WithValue(ctx, adminKey{}, true)
+}
+
+// IsAdmin reports whether the request presented the admin token.
+func IsAdmin(ctx context.Context) bool {
+	admin, _ := ctx.Value(adminKey{}).(bool)
+	return admin
+}
+
+func IsValidTokenDigest(tokenSHA256 string) bool {
+	digest, decodeErr := hex.DecodeString(tokenSHA256)
+	return decodeErr == nil && len(digest) == sha256.Size
+}
+
+type AdminToken struct {
+	digest []byte
+}
+
+// NewAdminToken expects a digest accepted by IsValidTokenDigest; an empty digest matches no token.
+func NewAdminToken(tokenSHA256 string) *AdminToken {
+	digest, _ := hex.DecodeString(tokenSHA256)
+	return &AdminToken{digest: digest}
+}
+
+// Matches compares digests in constant time, so the comparison does not depend on how much of the token matches.
+func (adminToken *AdminToken) Matches(token string) bool {
+	if len(adminToken.digest) == 0 {
+		return false
+	}
+	digest := sha256.Sum256([]byte(token))
+	return subtle.ConstantTimeCompare(digest[:], adminToken.digest) == 1
+}
@@ -0,0 +1,8 @@
+package auth
+
+type Config struct {
+	AdminTokenSHA256 string
+	// RequireTokenForPriceChanges refuses price changes without the admin token; off by default, when they are
+	// recorded under the anonymous actor.
+	RequireTokenForPriceChanges bool
+}
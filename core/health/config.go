@@ -0,0 +1,9 @@
+package health
+
+import "time"
+
+type Config struct {
+	CheckTimeout          time.Duration
+	CacheTTL              time.Duration
+	PoolSaturationPercent int
+}
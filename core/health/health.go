@@ -0,0 +1,138 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+type IChecker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc lets a plain function be registered as an IChecker.
+type CheckerFunc func(ctx context.Context) error
+
+func (checkerFunc CheckerFunc) Check(ctx context.Context) error {
+	return checkerFunc(ctx)
+}
+
+type CheckResult struct {
+	Status    string
+	Error     string
+	Duration  time.Duration
+	CheckedAt time.Time
+}
+
+type Report struct {
+	Status string
+	Checks map[string]CheckResult
+}
+
+type IRegistry interface {
+	Register(name string, checker IChecker)
+	Check(ctx context.Context) Report
+}
+
+type registeredChecker struct {
+	name    string
+	checker IChecker
+	mutex   sync.Mutex
+	cached  *CheckResult
+}
+
+type Registry struct {
+	config   Config
+	mutex    sync.RWMutex
+	checkers []*registeredChecker
+}
+
+func NewRegistry(config Config) IRegistry {
+	return &Registry{config: config}
+}
+
+func (registry *Registry) Register(name string, checker IChecker) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	registry.checkers = append(registry.checkers, &registeredChecker{name: name, checker: checker})
+}
+
+// Check runs every registered checker concurrently, reusing results younger than the cache TTL so that frequent
+// probes do not turn into load on the dependencies. The report is up only when every check is up.
+func (registry *Registry) Check(ctx context.Context) Report {
+	registry.mutex.RLock()
+	checkers := registry.checkers
+	registry.mutex.RUnlock()
+
+	results := make([]CheckResult, len(checkers))
+	var waitGroup sync.WaitGroup
+	for index, checker := range checkers {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			results[index] = checker.result(ctx, registry.config)
+		}()
+	}
+	waitGroup.Wait()
+
+	report := Report{Status: StatusUp, Checks: make(map[string]CheckResult, len(checkers))}
+	for index, checker := range checkers {
+		report.Checks[checker.name] = results[index]
+		if results[index].Status != StatusUp {
+			report.Status = StatusDown
+		}
+	}
+	return report
+}
+
+func (checker *registeredChecker) result(ctx context.Context, config Config) CheckResult {
+	checker.mutex.Lock()
+	defer checker.mutex.Unlock()
+
+	if checker.cached != nil && time.Since(checker.cached.CheckedAt) < config.CacheTTL {
+		return *checker.cached
+	}
+
+	result := run(ctx, checker.checker, config.CheckTimeout)
+	// A probe that went away mid-check says nothing about the dependency, so its result is not reused.
+	if ctx.Err() == nil {
+		checker.cached = &result
+	}
+	return result
+}
+
+// run bounds the check by timeout even when the checker ignores its context.
+func run(ctx context.Context, checker IChecker, timeout time.Duration) CheckResult {
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	startedAt := time.Now()
+	errs := make(chan error, 1)
+	go func() {
+		errs <- checker.Check(checkCtx)
+	}()
+
+	var err error
+	select {
+	case err = <-errs:
+	case <-checkCtx.Done():
+		err = checkCtx.Err()
+	}
+	if errors.Is(err, context.DeadlineExceeded) && checkCtx.Err() != nil {
+		err = fmt.Errorf("timed out after %s", timeout)
+	}
+
+	result := CheckResult{Status: StatusUp, Duration: time.Since(startedAt), CheckedAt: startedAt}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
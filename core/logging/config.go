@@ -0,0 +1,26 @@
+package logging
+
+import (
+	"log/slog"
+	"slices"
+)
+
+const (
+	TextFormat = "text"
+	JSONFormat = "json"
+)
+
+type Config struct {
+	Format string
+	Level  string
+}
+
+func IsValidFormat(format string) bool {
+	return slices.Contains([]string{TextFormat, JSONFormat}, format)
+}
+
+// ParseLevel accepts the slog level names debug, info, warn and error, in any case.
+func ParseLevel(level string) (slog.Level, error) {
+	var parsed slog.Level
+	return parsed, parsed.UnmarshalText([]byte(level))
+}
@@ -0,0 +1,64 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+)
+
+const (
+	RequestIdKey        = "request_id"
+	RouteKey            = "route"
+	ProductIdKey        = "product_id"
+	StoreIdKey          = "store_id"
+	CategoryIdKey       = "category_id"
+	PromotionIdKey      = "promotion_id"
+	MigrationVersionKey = "migration_version"
+	MigrationNameKey    = "migration_name"
+	CountKey            = "count"
+	AttemptKey          = "attempt"
+	ErrorKey            = "error"
+)
+
+type attrsKey struct{}
+
+// NewLogger writes records in the configured format, each carrying the attributes stored in its context by
+// WithAttrs. An invalid level falls back to info, as the configuration is validated before a logger is built.
+func NewLogger(config Config, writer io.Writer) *slog.Logger {
+	level, _ := ParseLevel(config.Level)
+	options := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler = slog.NewTextHandler(writer, options)
+	if config.Format == JSONFormat {
+		handler = slog.NewJSONHandler(writer, options)
+	}
+	return slog.New(contextHandler{Handler: handler})
+}
+
+// WithAttrs returns a context whose log records carry attrs in addition to those already stored in ctx.
+func WithAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	existingAttrs, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+	return context.WithValue(ctx, attrsKey{}, append(existingAttrs[:len(existingAttrs):len(existingAttrs)], attrs...))
+}
+
+func AttrsFromContext(ctx context.Context) []slog.Attr {
+	attrs, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+	return attrs
+}
+
+type contextHandler struct {
+	slog.Handler
+}
+
+func (handler contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	record.AddAttrs(AttrsFromContext(ctx)...)
+	return handler.Handler.Handle(ctx, record)
+}
+
+func (handler contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{Handler: handler.Handler.WithAttrs(attrs)}
+}
+
+func (handler contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{Handler: handler.Handler.WithGroup(name)}
+}
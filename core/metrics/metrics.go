@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"Service-schema/domain/domainerror"
+	"context"
+	"errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+)
+
+const (
+	OutcomeOK                 = "ok"
+	OutcomeNotFound           = "not_found"
+	OutcomeValidation         = "validation"
+	OutcomeConflict           = "conflict"
+	OutcomePreconditionFailed = "precondition_failed"
+	OutcomeCanceled           = "canceled"
+	OutcomeError              = "error"
+)
+
+// NewRegistry returns a registry holding the Go runtime and process collectors, to which the service adds its
+// own metrics.
+func NewRegistry() *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	return registry
+}
+
+func Handler(gatherer prometheus.Gatherer) http.Handler {
+	return promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{})
+}
+
+// Outcome labels the result of a call by the kind of error it returned, keeping label values to a fixed set.
+func Outcome(err error) string {
+	switch {
+	case err == nil:
+		return OutcomeOK
+	case errors.Is(err, domainerror.ErrNotFound):
+		return OutcomeNotFound
+	case errors.Is(err, domainerror.ErrValidation):
+		return OutcomeValidation
+	case errors.Is(err, domainerror.ErrConflict):
+		return OutcomeConflict
+	case errors.Is(err, domainerror.ErrPreconditionFailed):
+		return OutcomePreconditionFailed
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return OutcomeCanceled
+	default:
+		return OutcomeError
+	}
+}
@@ -1,11 +1,16 @@
 package postgresql
 
+import "time"
+
 type Config struct {
 	Host                  string
-	Port                  string
+	Port                  int
 	UserName              string
 	Password              string
 	DbName                string
-	MaxConnections        string
-	MaxConnectionIdleTime string
+	MaxConnections        int
+	MaxConnectionIdleTime time.Duration
+	QueryTimeouts         QueryTimeouts
+	TransactionOptions    TransactionOptions
+	ConnectRetry          ConnectRetry
 }
@@ -0,0 +1,15 @@
+package postgresql
+
+import "time"
+
+// ConnectRetry controls how long startup keeps retrying an unreachable database; a zero Timeout tries once.
+type ConnectRetry struct {
+	Timeout        time.Duration
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// nextBackoff doubles the backoff up to MaxBackoff.
+func (connectRetry ConnectRetry) nextBackoff(backoff time.Duration) time.Duration {
+	return min(backoff*2, connectRetry.MaxBackoff)
+}
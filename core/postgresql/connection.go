@@ -1,15 +1,20 @@
 package postgresql
 
 import (
+	"Service-schema/core/logging"
 	"context"
 	"fmt"
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
-	"github.com/labstack/gommon/log"
+	"log/slog"
+	"time"
 )
 
-func GetConnectionPool(context context.Context, config Config) *pgxpool.Pool {
+// GetConnectionPool connects the pool, retrying with exponential backoff while the database is unreachable for up
+// to config.ConnectRetry.Timeout; queryLogger, when not nil, receives every statement pgx logs at info level.
+func GetConnectionPool(ctx context.Context, config Config, queryLogger pgx.Logger, logger *slog.Logger) (*pgxpool.Pool, error) {
 	connString := fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=disable statement_cache_mode=describe pool_max_conns=%s pool_max_conn_idle_time=%s",
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=disable statement_cache_mode=describe pool_max_conns=%d pool_max_conn_idle_time=%s",
 		config.Host,
 		config.Port,
 		config.UserName,
@@ -21,13 +26,30 @@ func GetConnectionPool(context context.Context, config Config) *pgxpool.Pool {
 
 	connConfig, parseConfigErr := pgxpool.ParseConfig(connString)
 	if parseConfigErr != nil {
-		panic(parseConfigErr)
+		return nil, parseConfigErr
 	}
-	conn, err := pgxpool.ConnectConfig(context, connConfig)
-	if err != nil {
-		log.Error("Unable to connect to database: %v\n", err)
-		panic(err)
+	if queryLogger != nil {
+		connConfig.ConnConfig.Logger = queryLogger
+		connConfig.ConnConfig.LogLevel = pgx.LogLevelInfo
 	}
 
-	return conn
+	deadline := time.Now().Add(config.ConnectRetry.Timeout)
+	backoff := config.ConnectRetry.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		dbPool, connectErr := pgxpool.ConnectConfig(ctx, connConfig.Copy())
+		if connectErr == nil {
+			return dbPool, nil
+		}
+		if ctx.Err() != nil || time.Now().Add(backoff).After(deadline) {
+			return nil, fmt.Errorf("unable to connect to database after %d attempt(s): %w", attempt, connectErr)
+		}
+
+		logger.WarnContext(ctx, "Unable to connect to database, retrying", logging.AttemptKey, attempt, "backoff", backoff, logging.ErrorKey, connectErr)
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("unable to connect to database after %d attempt(s): %w", attempt, ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff = config.ConnectRetry.nextBackoff(backoff)
+	}
 }
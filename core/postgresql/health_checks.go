@@ -0,0 +1,25 @@
+package postgresql
+
+import (
+	"Service-schema/core/health"
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// NewPingChecker reports whether a pooled connection can reach the database.
+func NewPingChecker(dbPool *pgxpool.Pool) health.IChecker {
+	return health.CheckerFunc(dbPool.Ping)
+}
+
+// NewPoolSaturationChecker reports the pool as down once at least maxPercent of its connections are checked out,
+// since further requests would queue waiting for a connection.
+func NewPoolSaturationChecker(dbPool *pgxpool.Pool, maxPercent int) health.IChecker {
+	return health.CheckerFunc(func(ctx context.Context) error {
+		stat := dbPool.Stat()
+		if int(stat.AcquiredConns())*100 >= int(stat.MaxConns())*maxPercent {
+			return fmt.Errorf("%d of %d connections in use", stat.AcquiredConns(), stat.MaxConns())
+		}
+		return nil
+	})
+}
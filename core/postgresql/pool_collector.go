@@ -0,0 +1,57 @@
+package postgresql
+
+import (
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PoolCollector exposes pgxpool.Pool.Stat() at scrape time, so the gauges are never staler than the scrape.
+type PoolCollector struct {
+	dbPool *pgxpool.Pool
+
+	acquiredConnections *prometheus.Desc
+	idleConnections     *prometheus.Desc
+	totalConnections    *prometheus.Desc
+	maxConnections      *prometheus.Desc
+	acquireCount        *prometheus.Desc
+	acquireWaitSeconds  *prometheus.Desc
+	emptyAcquireCount   *prometheus.Desc
+	canceledAcquires    *prometheus.Desc
+}
+
+func NewPoolCollector(dbPool *pgxpool.Pool) prometheus.Collector {
+	return &PoolCollector{
+		dbPool:              dbPool,
+		acquiredConnections: prometheus.NewDesc("pgxpool_acquired_connections", "Number of connections currently acquired from the pool.", nil, nil),
+		idleConnections:     prometheus.NewDesc("pgxpool_idle_connections", "Number of idle connections in the pool.", nil, nil),
+		totalConnections:    prometheus.NewDesc("pgxpool_total_connections", "Number of connections in the pool, including those being established.", nil, nil),
+		maxConnections:      prometheus.NewDesc("pgxpool_max_connections", "Maximum size of the pool.", nil, nil),
+		acquireCount:        prometheus.NewDesc("pgxpool_acquires_total", "Number of successful connection acquisitions from the pool.", nil, nil),
+		acquireWaitSeconds:  prometheus.NewDesc("pgxpool_acquire_wait_seconds_total", "Total time spent acquiring connections from the pool.", nil, nil),
+		emptyAcquireCount:   prometheus.NewDesc("pgxpool_empty_acquires_total", "Number of acquisitions that had to wait because the pool was empty.", nil, nil),
+		canceledAcquires:    prometheus.NewDesc("pgxpool_canceled_acquires_total", "Number of acquisitions canceled by their context.", nil, nil),
+	}
+}
+
+func (poolCollector *PoolCollector) Describe(descs chan<- *prometheus.Desc) {
+	descs <- poolCollector.acquiredConnections
+	descs <- poolCollector.idleConnections
+	descs <- poolCollector.totalConnections
+	descs <- poolCollector.maxConnections
+	descs <- poolCollector.acquireCount
+	descs <- poolCollector.acquireWaitSeconds
+	descs <- poolCollector.emptyAcquireCount
+	descs <- poolCollector.canceledAcquires
+}
+
+func (poolCollector *PoolCollector) Collect(metrics chan<- prometheus.Metric) {
+	stat := poolCollector.dbPool.Stat()
+	metrics <- prometheus.MustNewConstMetric(poolCollector.acquiredConnections, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	metrics <- prometheus.MustNewConstMetric(poolCollector.idleConnections, prometheus.GaugeValue, float64(stat.IdleConns()))
+	metrics <- prometheus.MustNewConstMetric(poolCollector.totalConnections, prometheus.GaugeValue, float64(stat.TotalConns()))
+	metrics <- prometheus.MustNewConstMetric(poolCollector.maxConnections, prometheus.GaugeValue, float64(stat.MaxConns()))
+	metrics <- prometheus.MustNewConstMetric(poolCollector.acquireCount, prometheus.CounterValue, float64(stat.AcquireCount()))
+	metrics <- prometheus.MustNewConstMetric(poolCollector.acquireWaitSeconds, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+	metrics <- prometheus.MustNewConstMetric(poolCollector.emptyAcquireCount, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	metrics <- prometheus.MustNewConstMetric(poolCollector.canceledAcquires, prometheus.CounterValue, float64(stat.CanceledAcquireCount()))
+}
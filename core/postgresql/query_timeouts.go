@@ -0,0 +1,25 @@
+package postgresql
+
+import (
+	"context"
+	"time"
+)
+
+type QueryTimeouts struct {
+	Read   time.Duration
+	Write  time.Duration
+	Export time.Duration
+}
+
+func (queryTimeouts QueryTimeouts) ForRead(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, queryTimeouts.Read)
+}
+
+func (queryTimeouts QueryTimeouts) ForWrite(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, queryTimeouts.Write)
+}
+
+// ForExport bounds queries whose rows are streamed to a client, which take as long as the client needs to read them.
+func (queryTimeouts QueryTimeouts) ForExport(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, queryTimeouts.Export)
+}
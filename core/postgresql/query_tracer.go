@@ -0,0 +1,70 @@
+package postgresql
+
+import (
+	"context"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"strings"
+	"time"
+)
+
+const tracerName = "Service-schema/core/postgresql"
+
+// rowsAttributeKey holds the rows a query returned or a command affected.
+const rowsAttributeKey = attribute.Key("db.response.rows")
+
+// QueryTracer turns the records pgx v4 logs after each statement into spans. pgx v4 has no tracing hooks, but
+// it logs every Exec, Query, CopyFrom and SendBatch with the statement's context and duration, so the span is
+// recorded after the fact as a child of the span in that context. Query arguments are never recorded.
+type QueryTracer struct {
+	tracer trace.Tracer
+}
+
+func NewQueryTracer(tracerProvider trace.TracerProvider) pgx.Logger {
+	return &QueryTracer{tracer: tracerProvider.Tracer(tracerName)}
+}
+
+func (queryTracer *QueryTracer) Log(ctx context.Context, level pgx.LogLevel, msg string, data map[string]interface{}) {
+	duration, timed := data["time"].(time.Duration)
+	if !timed || !trace.SpanFromContext(ctx).SpanContext().IsValid() {
+		return
+	}
+
+	endTime := time.Now()
+	attributes := []attribute.KeyValue{semconv.DBSystemPostgreSQL}
+	operation := msg
+	if sql, isSQL := data["sql"].(string); isSQL {
+		attributes = append(attributes, semconv.DBQueryText(sql))
+		if fields := strings.Fields(sql); len(fields) > 0 {
+			operation = strings.ToUpper(fields[0])
+		}
+	}
+	if tableName, isTable := data["tableName"].(pgx.Identifier); isTable {
+		operation = "COPY"
+		attributes = append(attributes, semconv.DBCollectionName(tableName.Sanitize()))
+	}
+	attributes = append(attributes, semconv.DBOperationName(operation))
+	switch rowCount := data["rowCount"].(type) {
+	case int:
+		attributes = append(attributes, rowsAttributeKey.Int(rowCount))
+	case int64:
+		attributes = append(attributes, rowsAttributeKey.Int64(rowCount))
+	}
+	if commandTag, tagged := data["commandTag"].(pgconn.CommandTag); tagged {
+		attributes = append(attributes, rowsAttributeKey.Int64(commandTag.RowsAffected()))
+	}
+
+	_, span := queryTracer.tracer.Start(ctx, operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithTimestamp(endTime.Add(-duration)),
+		trace.WithAttributes(attributes...))
+	if err, failed := data["err"].(error); failed && level <= pgx.LogLevelError {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End(trace.WithTimestamp(endTime))
+}
@@ -0,0 +1,17 @@
+package postgresql
+
+import "slices"
+
+// TransactionOptions configures transactions started by the persistence transaction manager. IsolationLevel is
+// a PostgreSQL level such as "read committed" or "serializable"; MaxRetries bounds how often a transaction that
+// failed to serialize is run again.
+type TransactionOptions struct {
+	IsolationLevel string
+	MaxRetries     int
+}
+
+var isolationLevels = []string{"serializable", "repeatable read", "read committed", "read uncommitted"}
+
+func IsValidIsolationLevel(isolationLevel string) bool {
+	return slices.Contains(isolationLevels, isolationLevel)
+}
@@ -0,0 +1,8 @@
+package purge
+
+import "time"
+
+type Config struct {
+	Retention time.Duration
+	Interval  time.Duration
+}
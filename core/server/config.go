@@ -0,0 +1,18 @@
+package server
+
+import (
+	"fmt"
+	"time"
+)
+
+type Config struct {
+	Host            string
+	Port            int
+	ReadTimeout     time.Duration
+	WriteTimeout    time.Duration
+	ShutdownTimeout time.Duration
+}
+
+func (config Config) Address() string {
+	return fmt.Sprintf("%s:%d", config.Host, config.Port)
+}
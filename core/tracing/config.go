@@ -0,0 +1,19 @@
+package tracing
+
+import "slices"
+
+const (
+	NoExporter     = "none"
+	StdoutExporter = "stdout"
+	OTLPExporter   = "otlp"
+)
+
+type Config struct {
+	Exporter     string
+	OTLPEndpoint string
+	ServiceName  string
+}
+
+func IsValidExporter(exporter string) bool {
+	return slices.Contains([]string{NoExporter, StdoutExporter, OTLPExporter}, exporter)
+}
@@ -0,0 +1,64 @@
+package tracing
+
+import (
+	"Service-schema/core/metrics"
+	"context"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"io"
+)
+
+const (
+	ProductIdKey = attribute.Key("product.id")
+	OutcomeKey   = attribute.Key("outcome")
+)
+
+// NewTracerProvider batches spans to the configured exporter, writing to stdout as pretty printed JSON or
+// sending them over OTLP/HTTP. Without an exporter spans are still created, so trace context keeps being
+// propagated, but they are dropped when they end.
+func NewTracerProvider(ctx context.Context, config Config, stdout io.Writer) (*sdktrace.TracerProvider, error) {
+	options := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(config.ServiceName))),
+	}
+
+	switch config.Exporter {
+	case StdoutExporter:
+		exporter, exporterErr := stdouttrace.New(stdouttrace.WithWriter(stdout), stdouttrace.WithPrettyPrint())
+		if exporterErr != nil {
+			return nil, exporterErr
+		}
+		options = append(options, sdktrace.WithBatcher(exporter))
+	case OTLPExporter:
+		exporter, exporterErr := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(config.OTLPEndpoint))
+		if exporterErr != nil {
+			return nil, exporterErr
+		}
+		options = append(options, sdktrace.WithBatcher(exporter))
+	}
+
+	return sdktrace.NewTracerProvider(options...), nil
+}
+
+// NewPropagator reads and writes W3C traceparent, tracestate and baggage headers.
+func NewPropagator() propagation.TextMapPropagator {
+	return propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+}
+
+// End ends span, recording err when the call failed. Only unexpected errors mark the span as failed; domain
+// outcomes such as not found or validation are kept as the outcome attribute.
+func End(span trace.Span, err error) {
+	outcome := metrics.Outcome(err)
+	span.SetAttributes(OutcomeKey.String(outcome))
+	if outcome == metrics.OutcomeError {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,50 @@
+package domain
+
+const (
+	CategoryNotFoundCode = "category_not_found"
+	InvalidCategoryCode  = "invalid_category"
+	CategoryConflictCode = "category_conflict"
+	CategoryStorageCode  = "category_storage_error"
+)
+
+type Category struct {
+	Id       int64
+	Name     string
+	ParentId *int64
+}
+
+type CategoryTree struct {
+	Category
+	Children []CategoryTree
+}
+
+// NewCategoryTree nests categories under their parents; categories whose parent is not in the list become roots.
+func NewCategoryTree(categories []Category) []CategoryTree {
+	present := make(map[int64]bool, len(categories))
+	for _, category := range categories {
+		present[category.Id] = true
+	}
+
+	childrenByParent := make(map[int64][]Category)
+	var roots []Category
+	for _, category := range categories {
+		if category.ParentId == nil || !present[*category.ParentId] {
+			roots = append(roots, category)
+			continue
+		}
+		childrenByParent[*category.ParentId] = append(childrenByParent[*category.ParentId], category)
+	}
+
+	return buildCategoryTrees(roots, childrenByParent)
+}
+
+func buildCategoryTrees(categories []Category, childrenByParent map[int64][]Category) []CategoryTree {
+	trees := []CategoryTree{}
+	for _, category := range categories {
+		trees = append(trees, CategoryTree{
+			Category: category,
+			Children: buildCategoryTrees(childrenByParent[category.Id], childrenByParent),
+		})
+	}
+	return trees
+}
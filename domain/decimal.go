@@ -0,0 +1,174 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// DecimalScale is the number of fractional digits a Decimal keeps, matching the NUMERIC(19, 4) columns.
+const DecimalScale = 4
+
+var decimalFactor = pow10(DecimalScale)
+
+// Decimal is an exact base-10 number stored as an integer count of 10^-DecimalScale.
+type Decimal int64
+
+// NewDecimal converts a whole number, panicking when it is beyond the range of a Decimal.
+func NewDecimal(value int64) Decimal {
+	return toDecimal(new(big.Int).Mul(big.NewInt(value), big.NewInt(decimalFactor)))
+}
+
+// ParseDecimal reads a plain decimal such as "-12.5" with at most one leading sign and, when a decimal point is
+// present, at least one digit on both sides of it; fractional digits beyond DecimalScale must be zeros.
+func ParseDecimal(value string) (Decimal, error) {
+	malformedErr := errors.New("must be a decimal number such as 12.50")
+
+	negative := strings.HasPrefix(value, "-")
+	if negative || strings.HasPrefix(value, "+") {
+		value = value[1:]
+	}
+	integerPart, fractionPart, hasPoint := strings.Cut(value, ".")
+	if integerPart == "" || (hasPoint && fractionPart == "") || !isDigits(integerPart) || !isDigits(fractionPart) {
+		return 0, malformedErr
+	}
+
+	trimmedFraction := strings.TrimRight(fractionPart, "0")
+	if len(trimmedFraction) > DecimalScale {
+		return 0, errors.New("must not have more than 4 decimal places")
+	}
+	fractionDigits := trimmedFraction + strings.Repeat("0", DecimalScale-len(trimmedFraction))
+
+	units, parseErr := strconv.ParseInt(integerPart+fractionDigits, 10, 64)
+	if parseErr != nil {
+		return 0, errors.New("is out of range")
+	}
+	if negative {
+		units = -units
+	}
+
+	return Decimal(units), nil
+}
+
+func MustDecimal(value string) Decimal {
+	decimal, parseErr := ParseDecimal(value)
+	if parseErr != nil {
+		panic(parseErr)
+	}
+	return decimal
+}
+
+// String formats the decimal without trailing fractional zeros.
+func (decimal Decimal) String() string {
+	formatted := decimal.StringFixed(DecimalScale)
+	if strings.Contains(formatted, ".") {
+		formatted = strings.TrimRight(strings.TrimRight(formatted, "0"), ".")
+	}
+	return formatted
+}
+
+// StringFixed formats the decimal with exactly places fractional digits, padding with zeros beyond DecimalScale;
+// it never rounds, so places must cover every significant digit of the value.
+func (decimal Decimal) StringFixed(places int) string {
+	digits, negative := strings.CutPrefix(strconv.FormatInt(int64(decimal), 10), "-")
+	sign := ""
+	if negative {
+		sign = "-"
+	}
+
+	if len(digits) <= DecimalScale {
+		digits = strings.Repeat("0", DecimalScale-len(digits)+1) + digits
+	}
+	integerPart := digits[:len(digits)-DecimalScale]
+	fractionPart := digits[len(digits)-DecimalScale:] + strings.Repeat("0", max(places-DecimalScale, 0))
+	if places <= 0 {
+		return sign + integerPart
+	}
+	return sign + integerPart + "." + fractionPart[:places]
+}
+
+// RoundTo rounds half away from zero to the given number of fractional digits, panicking when the rounded value
+// is beyond the range of a Decimal.
+func (decimal Decimal) RoundTo(places int) Decimal {
+	quantum := big.NewInt(pow10(DecimalScale - places))
+	rounded := divideRoundingHalfAwayFromZero(big.NewInt(int64(decimal)), quantum)
+	return toDecimal(rounded.Mul(rounded, quantum))
+}
+
+// PercentOf returns percent of decimal rounded half away from zero to the given number of fractional digits,
+// panicking when the result is beyond the range of a Decimal.
+func (decimal Decimal) PercentOf(percent Decimal, places int) Decimal {
+	numerator := new(big.Int).Mul(big.NewInt(int64(decimal)), big.NewInt(int64(percent)))
+	quantum := big.NewInt(pow10(DecimalScale - places))
+	denominator := new(big.Int).Mul(big.NewInt(100*decimalFactor), quantum)
+	rounded := divideRoundingHalfAwayFromZero(numerator, denominator)
+	return toDecimal(rounded.Mul(rounded, quantum))
+}
+
+func (decimal Decimal) Places() int {
+	places := DecimalScale
+	for units := int64(decimal); places > 0 && units%10 == 0; units /= 10 {
+		places--
+	}
+	return places
+}
+
+// MarshalJSON encodes the decimal as a JSON string so that no precision is lost to float parsing by clients.
+func (decimal Decimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(decimal.String())
+}
+
+// UnmarshalJSON accepts both a JSON string and a JSON number literal, reading either exactly.
+func (decimal *Decimal) UnmarshalJSON(content []byte) error {
+	if string(content) == "null" {
+		return nil
+	}
+	value := string(content)
+	if strings.HasPrefix(value, `"`) {
+		if unquoteErr := json.Unmarshal(content, &value); unquoteErr != nil {
+			return fmt.Errorf("%s must be a JSON string or number", content)
+		}
+	}
+	parsed, parseErr := ParseDecimal(value)
+	if parseErr != nil {
+		return fmt.Errorf("%s %w", value, parseErr)
+	}
+	*decimal = parsed
+	return nil
+}
+
+func divideRoundingHalfAwayFromZero(numerator *big.Int, denominator *big.Int) *big.Int {
+	quotient, remainder := new(big.Int).QuoRem(numerator, denominator, new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(remainder), big.NewInt(2)).Cmp(denominator) >= 0 {
+		quotient.Add(quotient, big.NewInt(int64(numerator.Sign())))
+	}
+	return quotient
+}
+
+// toDecimal converts a count of 10^-DecimalScale, panicking rather than wrapping around when it does not fit.
+func toDecimal(units *big.Int) Decimal {
+	if !units.IsInt64() {
+		panic(fmt.Sprintf("decimal of %s units is out of range", units))
+	}
+	return Decimal(units.Int64())
+}
+
+func isDigits(value string) bool {
+	for _, character := range value {
+		if character < '0' || character > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func pow10(exponent int) int64 {
+	result := int64(1)
+	for ; exponent > 0; exponent-- {
+		result *= 10
+	}
+	return result
+}
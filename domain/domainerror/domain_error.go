@@ -0,0 +1,64 @@
+package domainerror
+
+import "errors"
+
+var (
+	ErrNotFound   = errors.New("not found")
+	ErrValidation = errors.New("validation failed")
+	ErrConflict   = errors.New("conflict")
+	ErrInternal   = errors.New("internal error")
+
+	ErrPreconditionFailed = errors.New("precondition failed")
+	ErrUnauthorized       = errors.New("unauthorized")
+)
+
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// Error carries one of the sentinel kinds above, so callers can match it with errors.Is
+// and read the code, message and field details with errors.As.
+type Error struct {
+	Kind    error
+	Code    string
+	Message string
+	Fields  []FieldError
+	Cause   error
+}
+
+func (domainError *Error) Error() string {
+	return domainError.Message
+}
+
+func (domainError *Error) Is(target error) bool {
+	return domainError.Kind == target
+}
+
+func (domainError *Error) Unwrap() error {
+	return domainError.Cause
+}
+
+func NotFound(code string, message string) *Error {
+	return &Error{Kind: ErrNotFound, Code: code, Message: message}
+}
+
+func Validation(code string, message string, fields ...FieldError) *Error {
+	return &Error{Kind: ErrValidation, Code: code, Message: message, Fields: fields}
+}
+
+func Conflict(code string, message string) *Error {
+	return &Error{Kind: ErrConflict, Code: code, Message: message}
+}
+
+func PreconditionFailed(code string, message string) *Error {
+	return &Error{Kind: ErrPreconditionFailed, Code: code, Message: message}
+}
+
+func Unauthorized(code string, message string) *Error {
+	return &Error{Kind: ErrUnauthorized, Code: code, Message: message}
+}
+
+func Internal(code string, message string, cause error) *Error {
+	return &Error{Kind: ErrInternal, Code: code, Message: message, Cause: cause}
+}
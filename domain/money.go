@@ -0,0 +1,70 @@
+package domain
+
+import "fmt"
+
+const DefaultCurrency = "USD"
+
+// currencyExponents lists the supported ISO 4217 currencies and the number of minor unit digits of each.
+var currencyExponents = map[string]int{
+	"AUD": 2,
+	"BHD": 3,
+	"CAD": 2,
+	"CHF": 2,
+	"CNY": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"INR": 2,
+	"JPY": 0,
+	"KRW": 0,
+	"KWD": 3,
+	"TRY": 2,
+	"USD": 2,
+}
+
+type Money struct {
+	Amount   Decimal
+	Currency string
+}
+
+func IsSupportedCurrency(currency string) bool {
+	_, supported := currencyExponents[currency]
+	return supported
+}
+
+// NewMoney rejects unknown currencies and amounts more precise than the currency's minor unit.
+func NewMoney(amount Decimal, currency string) (Money, error) {
+	exponent, supported := currencyExponents[currency]
+	if !supported {
+		return Money{}, fmt.Errorf("must use a supported ISO 4217 currency, got %q", currency)
+	}
+	if amount.Places() > exponent {
+		return Money{}, fmt.Errorf("must not have more than %d decimal places for %s", exponent, currency)
+	}
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+func MustMoney(amount string, currency string) Money {
+	money, moneyErr := NewMoney(MustDecimal(amount), currency)
+	if moneyErr != nil {
+		panic(moneyErr)
+	}
+	return money
+}
+
+func (money Money) exponent() int {
+	return currencyExponents[money.Currency]
+}
+
+// String formats the amount with the currency's minor unit digits, e.g. "12.50".
+func (money Money) String() string {
+	return money.Amount.StringFixed(money.exponent())
+}
+
+func (money Money) LessThan(amount Decimal) bool {
+	return money.Amount < amount
+}
+
+// Percent returns percent of the amount rounded half away from zero to the currency's minor unit.
+func (money Money) Percent(percent Decimal) Money {
+	return Money{Amount: money.Amount.PercentOf(percent, money.exponent()), Currency: money.Currency}
+}
@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+const (
+	InvalidPriceHistoryQueryCode = "invalid_price_history_query"
+	PriceHistoryStorageCode      = "price_history_storage_error"
+)
+
+// PriceChange keeps the product name it was made under, so the change stays meaningful after the product is purged.
+type PriceChange struct {
+	Id          int64
+	ProductId   int64
+	ProductName string
+	OldPrice    Money
+	NewPrice    Money
+	OldDiscount Decimal
+	NewDiscount Decimal
+	Actor       string
+	Reason      string
+	ChangedAt   time.Time
+}
+
+// PriceHistoryQuery selects the changes of a product made at or after From and before To; nil bounds are open.
+// The history of a deleted or purged product is only selected with IncludeDeleted.
+type PriceHistoryQuery struct {
+	ProductId      int64
+	From           *time.Time
+	To             *time.Time
+	IncludeDeleted bool
+}
@@ -1,9 +1,102 @@
 package domain
 
+import (
+	"Service-schema/domain/domainerror"
+	"fmt"
+	"time"
+)
+
+const (
+	ProductNotFoundCode     = "product_not_found"
+	InvalidProductCode      = "invalid_product"
+	InvalidProductQueryCode = "invalid_product_query"
+	ProductStorageCode      = "product_storage_error"
+
+	ProductVersionConflictCode = "product_version_conflict"
+)
+
 type Product struct {
 	Id       int64
 	Name     string
-	Price    float32
-	Discount float32
+	Price    Money
+	Discount Decimal
+	StoreId  int64
 	Store    string
+
+	// Version starts at 1 and is incremented by every write, so a writer can tell whether the row changed
+	// since it was read.
+	Version int64
+
+	// DeletedAt is set while the product is soft deleted and waiting to be restored or purged.
+	DeletedAt *time.Time
+
+	AppliedPromotions []Promotion
+}
+
+func ProductVersionConflict(productId int64, expectedVersion int64) *domainerror.Error {
+	return domainerror.Conflict(ProductVersionConflictCode, fmt.Sprintf("Product with id %d was modified since version %d", productId, expectedVersion))
+}
+
+// DiscountAmount is Discount percent of Price followed by each applied promotion on the remaining price, every
+// step rounded half away from zero to the currency's minor unit. A non-stackable promotion replaces Discount.
+func (product Product) DiscountAmount() Money {
+	remaining := product.Price
+	exclusive := len(product.AppliedPromotions) == 1 && !product.AppliedPromotions[0].Stackable
+	if !exclusive {
+		remaining.Amount -= product.Price.Percent(product.Discount).Amount
+	}
+	for _, promotion := range product.AppliedPromotions {
+		remaining.Amount -= promotion.discountOn(remaining).Amount
+	}
+	return Money{Amount: product.Price.Amount - remaining.Amount, Currency: product.Price.Currency}
+}
+
+// DiscountedPrice is derived from the rounded DiscountAmount so that the two always add up to Price.
+func (product Product) DiscountedPrice() Money {
+	return Money{Amount: product.Price.Amount - product.DiscountAmount().Amount, Currency: product.Price.Currency}
+}
+
+// ProductChanges holds the columns of a product that differ from the stored row; nil fields are left untouched.
+type ProductChanges struct {
+	Name     *string
+	Price    *Money
+	Discount *Decimal
+	StoreId  *int64
+}
+
+func (changes ProductChanges) IsEmpty() bool {
+	return changes.Name == nil && changes.Price == nil && changes.Discount == nil && changes.StoreId == nil
+}
+
+func (product Product) ChangesTo(updated Product) ProductChanges {
+	var changes ProductChanges
+	if updated.Name != product.Name {
+		changes.Name = &updated.Name
+	}
+	if updated.Price != product.Price {
+		changes.Price = &updated.Price
+	}
+	if updated.Discount != product.Discount {
+		changes.Discount = &updated.Discount
+	}
+	if updated.StoreId != product.StoreId {
+		changes.StoreId = &updated.StoreId
+	}
+	return changes
+}
+
+func (product Product) Apply(changes ProductChanges) Product {
+	if changes.Name != nil {
+		product.Name = *changes.Name
+	}
+	if changes.Price != nil {
+		product.Price = *changes.Price
+	}
+	if changes.Discount != nil {
+		product.Discount = *changes.Discount
+	}
+	if changes.StoreId != nil {
+		product.StoreId = *changes.StoreId
+	}
+	return product
 }
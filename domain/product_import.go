@@ -0,0 +1,58 @@
+package domain
+
+import "Service-schema/domain/domainerror"
+
+const (
+	InvalidProductImportCode  = "invalid_product_import"
+	ProductImportConflictCode = "product_import_conflict"
+)
+
+type ImportMode string
+
+const (
+	// AllOrNothingImport creates no product unless every row is valid and stored.
+	AllOrNothingImport ImportMode = "all_or_nothing"
+	// BestEffortImport creates every valid row and reports the others.
+	BestEffortImport ImportMode = "best_effort"
+)
+
+func (importMode ImportMode) IsValid() bool {
+	return importMode == AllOrNothingImport || importMode == BestEffortImport
+}
+
+type ImportRowStatus string
+
+const (
+	ImportRowCreated     ImportRowStatus = "created"
+	ImportRowInvalid     ImportRowStatus = "invalid"
+	ImportRowFailed      ImportRowStatus = "failed"
+	ImportRowNotImported ImportRowStatus = "not_imported"
+)
+
+// ProductImportRowResult is the outcome of one row of an import; Row counts data rows from 1, so a CSV header
+// is not counted.
+type ProductImportRowResult struct {
+	Row       int
+	Status    ImportRowStatus
+	ProductId int64
+	Message   string
+	Fields    []domainerror.FieldError
+}
+
+type ProductImportReport struct {
+	Mode    ImportMode
+	Created int
+	Failed  int
+	Rows    []ProductImportRowResult
+}
+
+// RollBack marks every created row as not imported, for an all-or-nothing import that was abandoned.
+func (report *ProductImportReport) RollBack() {
+	for index, row := range report.Rows {
+		if row.Status == ImportRowCreated {
+			report.Rows[index].Status = ImportRowNotImported
+			report.Rows[index].ProductId = 0
+		}
+	}
+	report.Created = 0
+}
@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+)
+
+type ProductSortField string
+
+const (
+	SortById       ProductSortField = "id"
+	SortByName     ProductSortField = "name"
+	SortByPrice    ProductSortField = "price"
+	SortByDiscount ProductSortField = "discount"
+)
+
+type SortDirection string
+
+const (
+	Ascending  SortDirection = "asc"
+	Descending SortDirection = "desc"
+)
+
+const (
+	DefaultProductPageSize = 20
+	MaxProductPageSize     = 100
+)
+
+type ProductQuery struct {
+	StoreId       int64
+	CategoryId    int64
+	NameContains  string
+	MinPrice      *Decimal
+	MaxPrice      *Decimal
+	MinDiscount   *Decimal
+	MaxDiscount   *Decimal
+	SortField     ProductSortField
+	SortDirection SortDirection
+	Limit         int
+	Offset        int
+	Cursor        *ProductCursor
+	At            time.Time
+
+	IncludeDeleted bool
+}
+
+type ProductPage struct {
+	Items      []Product
+	NextCursor string
+	TotalCount int64
+}
+
+// ProductCursor is the keyset position after which the next page starts: the sort column value of the
+// last returned product and its id as a tie breaker.
+type ProductCursor struct {
+	SortField     ProductSortField `json:"f"`
+	SortDirection SortDirection    `json:"d"`
+	SortValue     string           `json:"v"`
+	Id            int64            `json:"id"`
+}
+
+func (sortField ProductSortField) IsValid() bool {
+	switch sortField {
+	case SortById, SortByName, SortByPrice, SortByDiscount:
+		return true
+	}
+	return false
+}
+
+func (sortDirection SortDirection) IsValid() bool {
+	return sortDirection == Ascending || sortDirection == Descending
+}
+
+func NewProductCursor(query ProductQuery, product Product) ProductCursor {
+	cursor := ProductCursor{SortField: query.SortField, SortDirection: query.SortDirection, Id: product.Id}
+	switch query.SortField {
+	case SortByName:
+		cursor.SortValue = product.Name
+	case SortByPrice:
+		cursor.SortValue = product.Price.Amount.String()
+	case SortByDiscount:
+		cursor.SortValue = product.Discount.String()
+	default:
+		cursor.SortValue = strconv.FormatInt(product.Id, 10)
+	}
+	return cursor
+}
+
+func (cursor ProductCursor) Encode() string {
+	content, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(content)
+}
+
+func DecodeProductCursor(encodedCursor string) (ProductCursor, error) {
+	content, decodeErr := base64.RawURLEncoding.DecodeString(encodedCursor)
+	if decodeErr != nil {
+		return ProductCursor{}, errors.New("cursor is malformed")
+	}
+
+	var cursor ProductCursor
+	if unmarshalErr := json.Unmarshal(content, &cursor); unmarshalErr != nil {
+		return ProductCursor{}, errors.New("cursor is malformed")
+	}
+	if !cursor.SortField.IsValid() || !cursor.SortDirection.IsValid() {
+		return ProductCursor{}, errors.New("cursor is malformed")
+	}
+
+	return cursor, nil
+}
+
+// NewProductPage builds a page from items fetched with one row beyond query.Limit; that extra row only
+// signals that another page exists and is not returned.
+func NewProductPage(query ProductQuery, items []Product, totalCount int64) ProductPage {
+	page := ProductPage{Items: items, TotalCount: totalCount}
+	if page.Items == nil {
+		page.Items = []Product{}
+	}
+	if len(page.Items) > query.Limit {
+		page.Items = page.Items[:query.Limit]
+		page.NextCursor = NewProductCursor(query, page.Items[len(page.Items)-1]).Encode()
+	}
+	return page
+}
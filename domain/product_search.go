@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+const InvalidProductSearchCode = "invalid_product_search"
+
+// ProductSearchQuery matches Text against product and store names, both as words and, to tolerate typos, by
+// trigram similarity. StoreId narrows the hits but not the store facets, so a client can still offer the
+// other stores that have matches.
+type ProductSearchQuery struct {
+	Text    string
+	StoreId int64
+	Limit   int
+	Offset  int
+	At      time.Time
+}
+
+// ProductSearchHit is a matching product with its relevance, higher ranking first, and a snippet of the
+// product and store names in which matched words are wrapped in <mark> tags.
+type ProductSearchHit struct {
+	Product Product
+	Rank    float64
+	Snippet string
+}
+
+type StoreFacet struct {
+	StoreId int64
+	Store   string
+	Count   int64
+}
+
+type ProductSearchResult struct {
+	Hits        []ProductSearchHit
+	StoreFacets []StoreFacet
+	TotalCount  int64
+}
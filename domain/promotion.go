@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+const (
+	PromotionNotFoundCode = "promotion_not_found"
+	InvalidPromotionCode  = "invalid_promotion"
+	PromotionStorageCode  = "promotion_storage_error"
+)
+
+type PromotionType string
+
+const (
+	PercentagePromotion  PromotionType = "percentage"
+	FixedAmountPromotion PromotionType = "fixed_amount"
+)
+
+func (promotionType PromotionType) IsValid() bool {
+	return promotionType == PercentagePromotion || promotionType == FixedAmountPromotion
+}
+
+// Promotion lowers the price of the products it applies to between StartsAt (inclusive) and EndsAt (exclusive).
+// A nil StoreId or ProductId widens the scope to every store or every product. Stackable promotions combine
+// with each other and with the product's own discount; a non-stackable one replaces all of them when it is
+// the better deal on its own.
+type Promotion struct {
+	Id        int64
+	Name      string
+	Type      PromotionType
+	Value     Decimal
+	Currency  string
+	StoreId   *int64
+	ProductId *int64
+	Stackable bool
+	StartsAt  time.Time
+	EndsAt    time.Time
+}
+
+func (promotion Promotion) IsActiveAt(at time.Time) bool {
+	return !at.Before(promotion.StartsAt) && at.Before(promotion.EndsAt)
+}
+
+func (promotion Promotion) AppliesTo(product Product) bool {
+	return (promotion.StoreId == nil || *promotion.StoreId == product.StoreId) &&
+		(promotion.ProductId == nil || *promotion.ProductId == product.Id) &&
+		(promotion.Type != FixedAmountPromotion || promotion.Currency == product.Price.Currency)
+}
+
+// discountOn returns the amount the promotion takes off price, never more than price itself.
+func (promotion Promotion) discountOn(price Money) Money {
+	discount := price.Percent(promotion.Value)
+	if promotion.Type == FixedAmountPromotion {
+		discount = Money{Amount: promotion.Value, Currency: price.Currency}
+	}
+	if discount.Amount > price.Amount {
+		return price
+	}
+	return discount
+}
+
+// WithPromotionsAt returns the product with AppliedPromotions set to the promotions that give the lowest price
+// at the given time.
+func (product Product) WithPromotionsAt(promotions []Promotion, at time.Time) Product {
+	var stackable []Promotion
+	var exclusive []Promotion
+	for _, promotion := range promotions {
+		if !promotion.IsActiveAt(at) || !promotion.AppliesTo(product) {
+			continue
+		}
+		if promotion.Stackable {
+			stackable = append(stackable, promotion)
+		} else {
+			exclusive = append(exclusive, promotion)
+		}
+	}
+	sort.SliceStable(stackable, func(i, j int) bool {
+		return stackable[i].StartsAt.Before(stackable[j].StartsAt) ||
+			stackable[i].StartsAt.Equal(stackable[j].StartsAt) && stackable[i].Id < stackable[j].Id
+	})
+
+	product.AppliedPromotions = stackable
+	bestDiscount := product.DiscountAmount()
+	for _, promotion := range exclusive {
+		candidate := product
+		candidate.AppliedPromotions = []Promotion{promotion}
+		if candidateDiscount := candidate.DiscountAmount(); candidateDiscount.Amount > bestDiscount.Amount {
+			product.AppliedPromotions = candidate.AppliedPromotions
+			bestDiscount = candidateDiscount
+		}
+	}
+
+	return product
+}
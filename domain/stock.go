@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"Service-schema/domain/domainerror"
+	"fmt"
+	"math"
+)
+
+const (
+	InvalidStockOperationCode = "invalid_stock_operation"
+	InsufficientStockCode     = "insufficient_stock"
+	StockStorageCode          = "stock_storage_error"
+)
+
+type StockOperation string
+
+const (
+	AdjustStock  StockOperation = "adjust"
+	ReserveStock StockOperation = "reserve"
+	ReleaseStock StockOperation = "release"
+	CommitStock  StockOperation = "commit"
+)
+
+// StockLevel counts the units of a product held by its store; Reserved units are part of OnHand but promised
+// to pending orders.
+type StockLevel struct {
+	ProductId int64
+	StoreId   int64
+	OnHand    int64
+	Reserved  int64
+}
+
+func (stockLevel StockLevel) Available() int64 {
+	return stockLevel.OnHand - stockLevel.Reserved
+}
+
+// Apply returns the stock level after the operation. Adjust adds a signed delta to OnHand, the other operations
+// take a positive quantity; none of them may leave OnHand below Reserved or either count negative.
+func (stockLevel StockLevel) Apply(operation StockOperation, quantity int64) (StockLevel, error) {
+	if operation == AdjustStock && quantity == 0 {
+		return StockLevel{}, invalidStockOperationError("Quantity must not be zero")
+	}
+	if operation != AdjustStock && quantity <= 0 {
+		return StockLevel{}, invalidStockOperationError("Quantity must be greater than zero")
+	}
+
+	switch operation {
+	case AdjustStock:
+		if quantity == math.MinInt64 {
+			return StockLevel{}, invalidStockOperationError(fmt.Sprintf("Quantity must not be less than %d", -math.MaxInt64))
+		}
+		if quantity > math.MaxInt64-stockLevel.OnHand {
+			return StockLevel{}, invalidStockOperationError(fmt.Sprintf("Cannot add %d units, stock on hand would exceed %d", quantity, math.MaxInt64))
+		}
+		if quantity < -stockLevel.Available() {
+			return StockLevel{}, insufficientStockError(fmt.Sprintf("Cannot remove %d units, only %d are available", -quantity, stockLevel.Available()))
+		}
+		stockLevel.OnHand += quantity
+	case ReserveStock:
+		if quantity > stockLevel.Available() {
+			return StockLevel{}, insufficientStockError(fmt.Sprintf("Cannot reserve %d units, only %d are available", quantity, stockLevel.Available()))
+		}
+		stockLevel.Reserved += quantity
+	case ReleaseStock:
+		if quantity > stockLevel.Reserved {
+			return StockLevel{}, insufficientStockError(fmt.Sprintf("Cannot release %d units, only %d are reserved", quantity, stockLevel.Reserved))
+		}
+		stockLevel.Reserved -= quantity
+	case CommitStock:
+		if quantity > stockLevel.Reserved {
+			return StockLevel{}, insufficientStockError(fmt.Sprintf("Cannot commit %d units, only %d are reserved", quantity, stockLevel.Reserved))
+		}
+		stockLevel.Reserved -= quantity
+		stockLevel.OnHand -= quantity
+	default:
+		message := fmt.Sprintf("Unknown stock operation %s", operation)
+		return StockLevel{}, domainerror.Validation(InvalidStockOperationCode, message, domainerror.FieldError{Field: "operation", Message: message})
+	}
+
+	return stockLevel, nil
+}
+
+func invalidStockOperationError(message string) error {
+	return domainerror.Validation(InvalidStockOperationCode, message, domainerror.FieldError{Field: "quantity", Message: message})
+}
+
+func insufficientStockError(message string) error {
+	return domainerror.Conflict(InsufficientStockCode, message)
+}
@@ -0,0 +1,13 @@
+package domain
+
+const (
+	StoreNotFoundCode = "store_not_found"
+	InvalidStoreCode  = "invalid_store"
+	StoreConflictCode = "store_conflict"
+	StoreStorageCode  = "store_storage_error"
+)
+
+type Store struct {
+	Id   int64
+	Name string
+}
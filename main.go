@@ -3,28 +3,143 @@ package main
 import (
 	"Service-schema/controller"
 	"Service-schema/core/app"
+	"Service-schema/core/auth"
+	"Service-schema/core/health"
+	"Service-schema/core/logging"
+	"Service-schema/core/metrics"
 	"Service-schema/core/postgresql"
+	"Service-schema/core/tracing"
 	"Service-schema/persistence"
+	"Service-schema/persistence/migrations"
 	"Service-schema/service"
 	"context"
+	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+	"log/slog"
+	"os"
 )
 
 func main() {
 	ctx := context.Background()
+	configurationManager, configurationErr := app.NewConfigurationManager(os.Args[1:])
+	if configurationErr != nil {
+		slog.Error("Invalid configuration", logging.ErrorKey, configurationErr)
+		os.Exit(1)
+	}
+
+	logger := logging.NewLogger(configurationManager.LoggingConfig, os.Stdout)
+
+	if migrations.IsCommand(configurationManager.Args) {
+		command, parseErr := migrations.ParseCommand(configurationManager.Args)
+		if parseErr != nil {
+			fatal(logger, parseErr)
+		}
+
+		var dbPool *pgxpool.Pool
+		if command.NeedsDatabase() {
+			var connectErr error
+			dbPool, connectErr = postgresql.GetConnectionPool(ctx, configurationManager.PostgresqlConfig, nil, logger)
+			if connectErr != nil {
+				fatal(logger, connectErr)
+			}
+		}
+
+		migrateErr := runMigrateCommand(ctx, command, dbPool, logger)
+		if dbPool != nil {
+			dbPool.Close()
+		}
+		if migrateErr != nil {
+			fatal(logger, migrateErr)
+		}
+		return
+	}
+
+	registry := metrics.NewRegistry()
+	tracerProvider, tracingErr := tracing.NewTracerProvider(ctx, configurationManager.TracingConfig, os.Stdout)
+	if tracingErr != nil {
+		fatal(logger, tracingErr)
+	}
+	propagator := tracing.NewPropagator()
+	otel.SetTracerProvider(tracerProvider)
+	otel.SetTextMapPropagator(propagator)
+
 	e := echo.New()
+	e.HTTPErrorHandler = controller.NewHTTPErrorHandler(logger)
+	e.Use(controller.TracingMiddleware(tracerProvider, propagator, controller.SkipOperationalRoutes))
+	e.Use(controller.RequestLoggingMiddleware(logger, controller.SkipOperationalRoutes))
+	e.Use(controller.MetricsMiddleware(registry, controller.SkipOperationalRoutes))
+	e.Use(controller.AdminTokenMiddleware(auth.NewAdminToken(configurationManager.AuthConfig.AdminTokenSHA256)))
+	e.Use(controller.AuditMiddleware)
 
-	configurationManager := app.NewConfigurationManager()
+	dbPool, connectErr := postgresql.GetConnectionPool(ctx, configurationManager.PostgresqlConfig, postgresql.NewQueryTracer(tracerProvider), logger)
+	if connectErr != nil {
+		fatal(logger, connectErr)
+	}
 
-	dbPool := postgresql.GetConnectionPool(ctx, configurationManager.PostgresqlConfig)
+	migrator, migratorErr := migrations.NewMigrator(dbPool, logger)
+	if migratorErr != nil {
+		fatal(logger, migratorErr)
+	}
+	healthRegistry := health.NewRegistry(configurationManager.HealthConfig)
+	healthRegistry.Register("database", postgresql.NewPingChecker(dbPool))
+	healthRegistry.Register("migrations", migrations.NewHealthChecker(migrator))
+	healthRegistry.Register("connection_pool", postgresql.NewPoolSaturationChecker(dbPool, configurationManager.HealthConfig.PoolSaturationPercent))
 
-	productRepository := persistence.NewProductRepository(dbPool)
+	registry.MustRegister(postgresql.NewPoolCollector(dbPool))
 
-	productService := service.NewProductService(productRepository)
+	productRepository := persistence.NewInstrumentedProductRepository(persistence.NewTracedProductRepository(persistence.NewProductRepository(dbPool, configurationManager.PostgresqlConfig.QueryTimeouts, logger), tracerProvider), registry)
+	storeRepository := persistence.NewStoreRepository(dbPool, configurationManager.PostgresqlConfig.QueryTimeouts, logger)
+	categoryRepository := persistence.NewCategoryRepository(dbPool, configurationManager.PostgresqlConfig.QueryTimeouts, logger)
+	stockRepository := persistence.NewStockRepository(dbPool, configurationManager.PostgresqlConfig.QueryTimeouts, logger)
+	priceHistoryRepository := persistence.NewPriceHistoryRepository(dbPool, configurationManager.PostgresqlConfig.QueryTimeouts, logger)
+	promotionRepository := persistence.NewPromotionRepository(dbPool, configurationManager.PostgresqlConfig.QueryTimeouts, logger)
+	transactionManager := persistence.NewTransactionManager(dbPool, configurationManager.PostgresqlConfig.TransactionOptions, logger)
 
-	productController := controller.NewProductController(productService)
+	productService := service.NewInstrumentedProductService(service.NewTracedProductService(service.NewProductService(productRepository, storeRepository, promotionRepository, transactionManager, logger), tracerProvider), registry)
+	storeService := service.NewStoreService(storeRepository)
+	categoryService := service.NewCategoryService(categoryRepository)
+	stockService := service.NewStockService(stockRepository, storeRepository)
+	priceHistoryService := service.NewPriceHistoryService(priceHistoryRepository, productRepository)
+	promotionService := service.NewPromotionService(promotionRepository)
+	productImportService := service.NewProductImportService(productRepository, storeRepository, transactionManager)
 
+	productController := controller.NewProductController(productService, configurationManager.AuthConfig, logger)
+	productImportController := controller.NewProductImportController(productImportService)
+	storeController := controller.NewStoreController(storeService)
+	categoryController := controller.NewCategoryController(categoryService)
+	stockController := controller.NewStockController(stockService)
+	priceHistoryController := controller.NewPriceHistoryController(priceHistoryService)
+	promotionController := controller.NewPromotionController(promotionService)
+	healthController := controller.NewHealthController(healthRegistry)
+
+	e.GET("/metrics", echo.WrapHandler(metrics.Handler(registry)))
+	healthController.RegisterRoutes(e)
 	productController.RegisterRoutes(e)
+	productImportController.RegisterRoutes(e)
+	storeController.RegisterRoutes(e)
+	categoryController.RegisterRoutes(e)
+	stockController.RegisterRoutes(e)
+	priceHistoryController.RegisterRoutes(e)
+	promotionController.RegisterRoutes(e)
+
+	productPurgeJob := service.NewProductPurgeJob(productRepository, configurationManager.PurgeConfig, logger)
+	productPurgeJob.Start(ctx)
+
+	application := app.NewApplication(e, configurationManager.ServerConfig, logger)
+	application.OnShutdown("tracer provider", tracerProvider.Shutdown)
+	application.OnShutdown("database pool", func(ctx context.Context) error {
+		dbPool.Close()
+		return nil
+	})
+	application.OnShutdown("product purge job", productPurgeJob.Stop)
+
+	if runErr := application.Run(ctx); runErr != nil {
+		fatal(logger, runErr)
+	}
+}
 
-	e.Start("localhost:8080")
+func fatal(logger *slog.Logger, err error) {
+	logger.Error("Application failed", logging.ErrorKey, err)
+	os.Exit(1)
 }
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"Service-schema/persistence/migrations"
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"log/slog"
+	"time"
+)
+
+func runMigrateCommand(ctx context.Context, command migrations.Command, dbPool *pgxpool.Pool, logger *slog.Logger) error {
+	if command.Action == migrations.CreateAction {
+		paths, createErr := migrations.Create(migrations.SourceDirectory, command.Name)
+		for _, path := range paths {
+			fmt.Println("Created", path)
+		}
+		return createErr
+	}
+
+	migrator, migratorErr := migrations.NewMigrator(dbPool, logger)
+	if migratorErr != nil {
+		return migratorErr
+	}
+
+	switch command.Action {
+	case migrations.UpAction:
+		applied, upErr := migrator.Up(ctx)
+		fmt.Printf("Applied %d migration(s)\n", len(applied))
+		return upErr
+	case migrations.DownAction:
+		rolledBack, downErr := migrator.Down(ctx, command.Steps)
+		fmt.Printf("Rolled back %d migration(s)\n", len(rolledBack))
+		return downErr
+	default:
+		statuses, statusErr := migrator.Status(ctx)
+		if statusErr != nil {
+			return statusErr
+		}
+		for _, status := range statuses {
+			state := "pending"
+			if status.AppliedAt != nil {
+				state = "applied at " + status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%04d_%s\t%s\n", status.Migration.Version, status.Migration.Name, state)
+		}
+		return nil
+	}
+}
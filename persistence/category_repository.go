@@ -0,0 +1,286 @@
+package persistence
+
+import (
+	"Service-schema/core/logging"
+	"Service-schema/core/postgresql"
+	"Service-schema/domain"
+	"Service-schema/domain/domainerror"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"log/slog"
+)
+
+const categoryColumns = "c.id, c.name, c.parent_id"
+
+type ICategoryRepository interface {
+	GetAllCategories(ctx context.Context) ([]domain.Category, error)
+	GetById(ctx context.Context, categoryId int64) (domain.Category, error)
+	Add(ctx context.Context, category domain.Category) (domain.Category, error)
+	Update(ctx context.Context, category domain.Category) (domain.Category, error)
+	DeleteById(ctx context.Context, categoryId int64) error
+	GetProductCategories(ctx context.Context, productId int64) ([]domain.Category, error)
+	SetProductCategories(ctx context.Context, productId int64, categoryIds []int64) error
+}
+
+type CategoryRepository struct {
+	dbPool        *pgxpool.Pool
+	queryTimeouts postgresql.QueryTimeouts
+	logger        *slog.Logger
+}
+
+func NewCategoryRepository(dbPool *pgxpool.Pool, queryTimeouts postgresql.QueryTimeouts, logger *slog.Logger) ICategoryRepository {
+	return &CategoryRepository{
+		dbPool:        dbPool,
+		queryTimeouts: queryTimeouts,
+		logger:        logger,
+	}
+}
+
+func (categoryRepository *CategoryRepository) GetAllCategories(ctx context.Context) ([]domain.Category, error) {
+	ctx, cancel := categoryRepository.queryTimeouts.ForRead(ctx)
+	defer cancel()
+
+	categoryRows, err := connectionFrom(ctx, categoryRepository.dbPool).Query(ctx, `SELECT `+categoryColumns+` FROM categories c ORDER BY c.id`)
+	if err != nil {
+		categoryRepository.logger.ErrorContext(ctx, "Error occurred getting all categories", logging.ErrorKey, err)
+		return nil, domainerror.Internal(domain.CategoryStorageCode, "Error occurred getting categories", err)
+	}
+
+	return extractAllCategories(categoryRows)
+}
+
+func (categoryRepository *CategoryRepository) GetById(ctx context.Context, categoryId int64) (domain.Category, error) {
+	ctx, cancel := categoryRepository.queryTimeouts.ForRead(ctx)
+	defer cancel()
+
+	categoryRow := connectionFrom(ctx, categoryRepository.dbPool).QueryRow(ctx, `SELECT `+categoryColumns+` FROM categories c WHERE c.id = $1`, categoryId)
+	return extractCategory(categoryId, categoryRow)
+}
+
+// Add inserts the category and its closure rows: one per ancestor of the parent plus the zero-depth self row.
+func (categoryRepository *CategoryRepository) Add(ctx context.Context, category domain.Category) (domain.Category, error) {
+	ctx, cancel := categoryRepository.queryTimeouts.ForWrite(ctx)
+	defer cancel()
+
+	var newCategory domain.Category
+	txErr := connectionFrom(ctx, categoryRepository.dbPool).BeginFunc(ctx, func(tx pgx.Tx) error {
+		insertSQL := `INSERT INTO categories (name, parent_id) VALUES ($1, $2) RETURNING id, name, parent_id`
+		scanErr := tx.QueryRow(ctx, insertSQL, category.Name, category.ParentId).Scan(&newCategory.Id, &newCategory.Name, &newCategory.ParentId)
+		if scanErr != nil {
+			return scanErr
+		}
+
+		closureSQL := `INSERT INTO category_closure (ancestor_id, descendant_id, depth)
+			SELECT ancestor_id, $1::bigint, depth + 1 FROM category_closure WHERE descendant_id = $2::bigint
+			UNION ALL SELECT $1::bigint, $1::bigint, 0`
+		_, closureErr := tx.Exec(ctx, closureSQL, newCategory.Id, category.ParentId)
+		return closureErr
+	})
+	if txErr != nil {
+		return domain.Category{}, categoryRepository.writeError(ctx, category, "inserting", txErr)
+	}
+
+	categoryRepository.logger.InfoContext(ctx, "Added category", logging.CategoryIdKey, newCategory.Id)
+	return newCategory, nil
+}
+
+// Update renames the category and, when its parent changes, detaches the whole subtree from its old
+// ancestors and attaches it below the new parent. The category and every ancestor of the new parent are
+// locked in id order first: a concurrent move of one of those ancestors below the category then waits for
+// this one and its cycle check sees the result, which it would not at read committed otherwise.
+func (categoryRepository *CategoryRepository) Update(ctx context.Context, category domain.Category) (domain.Category, error) {
+	ctx, cancel := categoryRepository.queryTimeouts.ForWrite(ctx)
+	defer cancel()
+
+	var updatedCategory domain.Category
+	txErr := connectionFrom(ctx, categoryRepository.dbPool).BeginFunc(ctx, func(tx pgx.Tx) error {
+		lockSQL := `SELECT id FROM categories
+			WHERE id = $1 OR id IN (SELECT ancestor_id FROM category_closure WHERE descendant_id = $2)
+			ORDER BY id FOR UPDATE`
+		if _, lockErr := tx.Exec(ctx, lockSQL, category.Id, category.ParentId); lockErr != nil {
+			return lockErr
+		}
+
+		currentCategory, getErr := extractCategory(category.Id, tx.QueryRow(ctx, `SELECT `+categoryColumns+` FROM categories c WHERE c.id = $1`, category.Id))
+		if getErr != nil {
+			return getErr
+		}
+
+		if category.ParentId != nil {
+			var createsCycle bool
+			cycleSQL := `SELECT EXISTS (SELECT 1 FROM category_closure WHERE ancestor_id = $1 AND descendant_id = $2)`
+			if cycleErr := tx.QueryRow(ctx, cycleSQL, category.Id, *category.ParentId).Scan(&createsCycle); cycleErr != nil {
+				return cycleErr
+			}
+			if createsCycle {
+				message := "Category cannot be moved below itself or one of its descendants"
+				return domainerror.Validation(domain.InvalidCategoryCode, message, domainerror.FieldError{Field: "parent_id", Message: message})
+			}
+		}
+
+		updateSQL := `UPDATE categories SET name = $2, parent_id = $3 WHERE id = $1 RETURNING id, name, parent_id`
+		scanErr := tx.QueryRow(ctx, updateSQL, category.Id, category.Name, category.ParentId).Scan(&updatedCategory.Id, &updatedCategory.Name, &updatedCategory.ParentId)
+		if scanErr != nil {
+			return scanErr
+		}
+
+		if equalParentIds(currentCategory.ParentId, category.ParentId) {
+			return nil
+		}
+
+		detachSQL := `DELETE FROM category_closure
+			WHERE descendant_id IN (SELECT descendant_id FROM category_closure WHERE ancestor_id = $1)
+			AND ancestor_id NOT IN (SELECT descendant_id FROM category_closure WHERE ancestor_id = $1)`
+		if _, detachErr := tx.Exec(ctx, detachSQL, category.Id); detachErr != nil {
+			return detachErr
+		}
+
+		attachSQL := `INSERT INTO category_closure (ancestor_id, descendant_id, depth)
+			SELECT parent.ancestor_id, subtree.descendant_id, parent.depth + subtree.depth + 1
+			FROM category_closure parent CROSS JOIN category_closure subtree
+			WHERE parent.descendant_id = $2 AND subtree.ancestor_id = $1`
+		_, attachErr := tx.Exec(ctx, attachSQL, category.Id, category.ParentId)
+		return attachErr
+	})
+	if txErr != nil {
+		return domain.Category{}, categoryRepository.writeError(ctx, category, "updating", txErr)
+	}
+
+	categoryRepository.logger.InfoContext(ctx, "Updated category", logging.CategoryIdKey, category.Id)
+	return updatedCategory, nil
+}
+
+func (categoryRepository *CategoryRepository) DeleteById(ctx context.Context, categoryId int64) error {
+	ctx, cancel := categoryRepository.queryTimeouts.ForWrite(ctx)
+	defer cancel()
+
+	commandTag, err := connectionFrom(ctx, categoryRepository.dbPool).Exec(ctx, `DELETE FROM categories WHERE id = $1`, categoryId)
+	if isForeignKeyViolation(err) {
+		return domainerror.Conflict(domain.CategoryConflictCode, fmt.Sprintf("Category %d still has subcategories", categoryId))
+	}
+	if err != nil {
+		categoryRepository.logger.ErrorContext(ctx, "Error occurred deleting category", logging.ErrorKey, err)
+		return domainerror.Internal(domain.CategoryStorageCode, fmt.Sprintf("Error occurred deleting category with id %d", categoryId), err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		return domainerror.NotFound(domain.CategoryNotFoundCode, fmt.Sprintf("Category not found with id %d", categoryId))
+	}
+
+	categoryRepository.logger.InfoContext(ctx, "Deleted category", logging.CategoryIdKey, categoryId)
+	return nil
+}
+
+func (categoryRepository *CategoryRepository) GetProductCategories(ctx context.Context, productId int64) ([]domain.Category, error) {
+	ctx, cancel := categoryRepository.queryTimeouts.ForRead(ctx)
+	defer cancel()
+
+	selectSQL := `SELECT ` + categoryColumns + ` FROM categories c
+		JOIN product_categories pc ON pc.category_id = c.id
+		JOIN products p ON p.id = pc.product_id
+		WHERE pc.product_id = $1 AND p.deleted_at IS NULL ORDER BY c.id`
+	categoryRows, err := connectionFrom(ctx, categoryRepository.dbPool).Query(ctx, selectSQL, productId)
+	if err != nil {
+		categoryRepository.logger.ErrorContext(ctx, "Error occurred getting product categories", logging.ErrorKey, err)
+		return nil, domainerror.Internal(domain.CategoryStorageCode, fmt.Sprintf("Error occurred getting categories of product %d", productId), err)
+	}
+
+	return extractAllCategories(categoryRows)
+}
+
+// SetProductCategories replaces every category assignment of the product with categoryIds.
+func (categoryRepository *CategoryRepository) SetProductCategories(ctx context.Context, productId int64, categoryIds []int64) error {
+	ctx, cancel := categoryRepository.queryTimeouts.ForWrite(ctx)
+	defer cancel()
+
+	txErr := connectionFrom(ctx, categoryRepository.dbPool).BeginFunc(ctx, func(tx pgx.Tx) error {
+		var lockedProductId int64
+		lockErr := tx.QueryRow(ctx, `SELECT id FROM products WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, productId).Scan(&lockedProductId)
+		if errors.Is(lockErr, pgx.ErrNoRows) {
+			return domainerror.NotFound(domain.ProductNotFoundCode, fmt.Sprintf("Product not found with id %d", productId))
+		}
+		if lockErr != nil {
+			return lockErr
+		}
+
+		if _, deleteErr := tx.Exec(ctx, `DELETE FROM product_categories WHERE product_id = $1`, productId); deleteErr != nil {
+			return deleteErr
+		}
+
+		insertSQL := `INSERT INTO product_categories (product_id, category_id) SELECT $1, unnest($2::bigint[])`
+		_, insertErr := tx.Exec(ctx, insertSQL, productId, categoryIds)
+		if isForeignKeyViolation(insertErr) {
+			message := "Categories must exist"
+			return domainerror.Validation(domain.InvalidCategoryCode, message, domainerror.FieldError{Field: "category_ids", Message: message})
+		}
+		return insertErr
+	})
+
+	var domainError *domainerror.Error
+	if errors.As(txErr, &domainError) {
+		return domainError
+	}
+	if txErr != nil {
+		categoryRepository.logger.ErrorContext(ctx, "Error occurred setting product categories", logging.ErrorKey, txErr)
+		return domainerror.Internal(domain.CategoryStorageCode, fmt.Sprintf("Error occurred setting categories of product %d", productId), txErr)
+	}
+
+	categoryRepository.logger.InfoContext(ctx, "Set product categories", logging.ProductIdKey, productId)
+	return nil
+}
+
+func (categoryRepository *CategoryRepository) writeError(ctx context.Context, category domain.Category, action string, err error) error {
+	var domainError *domainerror.Error
+	if errors.As(err, &domainError) {
+		return domainError
+	}
+	if isUniqueViolation(err) {
+		return domainerror.Conflict(domain.CategoryConflictCode, fmt.Sprintf("Category %s already exists under the same parent", category.Name))
+	}
+	if isForeignKeyViolation(err) && category.ParentId != nil {
+		message := fmt.Sprintf("Parent category not found with id %d", *category.ParentId)
+		return domainerror.Validation(domain.InvalidCategoryCode, message, domainerror.FieldError{Field: "parent_id", Message: message})
+	}
+	categoryRepository.logger.ErrorContext(ctx, "Error occurred "+action+" category", logging.ErrorKey, err)
+	return domainerror.Internal(domain.CategoryStorageCode, fmt.Sprintf("Error occurred %s category", action), err)
+}
+
+func equalParentIds(left *int64, right *int64) bool {
+	if left == nil || right == nil {
+		return left == right
+	}
+	return *left == *right
+}
+
+func extractAllCategories(categoryRows pgx.Rows) ([]domain.Category, error) {
+	defer categoryRows.Close()
+
+	categories := []domain.Category{}
+	for categoryRows.Next() {
+		var category domain.Category
+		if scanErr := categoryRows.Scan(&category.Id, &category.Name, &category.ParentId); scanErr != nil {
+			return nil, domainerror.Internal(domain.CategoryStorageCode, "Error occurred scanning categories", scanErr)
+		}
+		categories = append(categories, category)
+	}
+	if rowsErr := categoryRows.Err(); rowsErr != nil {
+		return nil, domainerror.Internal(domain.CategoryStorageCode, "Error occurred reading categories", rowsErr)
+	}
+
+	return categories, nil
+}
+
+func extractCategory(categoryId int64, categoryRow pgx.Row) (domain.Category, error) {
+	var category domain.Category
+	scanErr := categoryRow.Scan(&category.Id, &category.Name, &category.ParentId)
+	if errors.Is(scanErr, pgx.ErrNoRows) {
+		return domain.Category{}, domainerror.NotFound(domain.CategoryNotFoundCode, fmt.Sprintf("Category not found with id %d", categoryId))
+	}
+	if scanErr != nil {
+		return domain.Category{}, domainerror.Internal(domain.CategoryStorageCode, fmt.Sprintf("Error occurred when scanned category with id %d", categoryId), scanErr)
+	}
+
+	return category, nil
+}
@@ -0,0 +1,118 @@
+package persistence
+
+import (
+	"Service-schema/core/metrics"
+	"Service-schema/domain"
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"time"
+)
+
+// InstrumentedProductRepository records the latency of every IProductRepository call per method, and counts
+// the calls that failed by method and outcome. ExportProducts is timed until its last product is written.
+type InstrumentedProductRepository struct {
+	next          IProductRepository
+	queryDuration *prometheus.HistogramVec
+	queryErrors   *prometheus.CounterVec
+}
+
+func NewInstrumentedProductRepository(next IProductRepository, registerer prometheus.Registerer) IProductRepository {
+	instrumentedRepository := &InstrumentedProductRepository{
+		next: next,
+		queryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "product_repository_query_duration_seconds",
+			Help:    "Latency of product repository calls.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method"}),
+		queryErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "product_repository_errors_total",
+			Help: "Number of product repository calls that returned an error.",
+		}, []string{"method", "outcome"}),
+	}
+	registerer.MustRegister(instrumentedRepository.queryDuration, instrumentedRepository.queryErrors)
+	return instrumentedRepository
+}
+
+func (instrumentedRepository *InstrumentedProductRepository) GetProducts(ctx context.Context, query domain.ProductQuery) (domain.ProductPage, error) {
+	start := time.Now()
+	result, err := instrumentedRepository.next.GetProducts(ctx, query)
+	instrumentedRepository.observe("GetProducts", start, err)
+	return result, err
+}
+
+func (instrumentedRepository *InstrumentedProductRepository) ExportProducts(ctx context.Context, query domain.ProductQuery, write func(domain.Product) error) error {
+	start := time.Now()
+	err := instrumentedRepository.next.ExportProducts(ctx, query, write)
+	instrumentedRepository.observe("ExportProducts", start, err)
+	return err
+}
+
+func (instrumentedRepository *InstrumentedProductRepository) SearchProducts(ctx context.Context, query domain.ProductSearchQuery) (domain.ProductSearchResult, error) {
+	start := time.Now()
+	result, err := instrumentedRepository.next.SearchProducts(ctx, query)
+	instrumentedRepository.observe("SearchProducts", start, err)
+	return result, err
+}
+
+func (instrumentedRepository *InstrumentedProductRepository) GetById(ctx context.Context, productId int64) (domain.Product, error) {
+	start := time.Now()
+	result, err := instrumentedRepository.next.GetById(ctx, productId)
+	instrumentedRepository.observe("GetById", start, err)
+	return result, err
+}
+
+func (instrumentedRepository *InstrumentedProductRepository) Add(ctx context.Context, product domain.Product) (domain.Product, error) {
+	start := time.Now()
+	result, err := instrumentedRepository.next.Add(ctx, product)
+	instrumentedRepository.observe("Add", start, err)
+	return result, err
+}
+
+func (instrumentedRepository *InstrumentedProductRepository) AddAll(ctx context.Context, products []domain.Product) ([]domain.Product, error) {
+	start := time.Now()
+	result, err := instrumentedRepository.next.AddAll(ctx, products)
+	instrumentedRepository.observe("AddAll", start, err)
+	return result, err
+}
+
+func (instrumentedRepository *InstrumentedProductRepository) DeleteById(ctx context.Context, productId int64, expectedVersion int64) error {
+	start := time.Now()
+	err := instrumentedRepository.next.DeleteById(ctx, productId, expectedVersion)
+	instrumentedRepository.observe("DeleteById", start, err)
+	return err
+}
+
+func (instrumentedRepository *InstrumentedProductRepository) UpdatePrice(ctx context.Context, productId int64, price domain.Money) error {
+	start := time.Now()
+	err := instrumentedRepository.next.UpdatePrice(ctx, productId, price)
+	instrumentedRepository.observe("UpdatePrice", start, err)
+	return err
+}
+
+func (instrumentedRepository *InstrumentedProductRepository) Update(ctx context.Context, productId int64, expectedVersion int64, changes domain.ProductChanges) error {
+	start := time.Now()
+	err := instrumentedRepository.next.Update(ctx, productId, expectedVersion, changes)
+	instrumentedRepository.observe("Update", start, err)
+	return err
+}
+
+func (instrumentedRepository *InstrumentedProductRepository) Restore(ctx context.Context, productId int64) (domain.Product, error) {
+	start := time.Now()
+	result, err := instrumentedRepository.next.Restore(ctx, productId)
+	instrumentedRepository.observe("Restore", start, err)
+	return result, err
+}
+
+func (instrumentedRepository *InstrumentedProductRepository) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	start := time.Now()
+	result, err := instrumentedRepository.next.PurgeDeleted(ctx, deletedBefore)
+	instrumentedRepository.observe("PurgeDeleted", start, err)
+	return result, err
+}
+
+func (instrumentedRepository *InstrumentedProductRepository) observe(method string, start time.Time, err error) {
+	instrumentedRepository.queryDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+	if err != nil {
+		instrumentedRepository.queryErrors.WithLabelValues(method, metrics.Outcome(err)).Inc()
+	}
+}
@@ -0,0 +1,57 @@
+package migrations
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+const Usage = "usage: migrate up | down [steps] | status | create <name>"
+
+const (
+	UpAction     = "up"
+	DownAction   = "down"
+	StatusAction = "status"
+	CreateAction = "create"
+)
+
+// Command is a parsed migrate subcommand; Steps applies to down and Name to create.
+type Command struct {
+	Action string
+	Steps  int
+	Name   string
+}
+
+func IsCommand(args []string) bool {
+	return len(args) > 0 && args[0] == "migrate"
+}
+
+// ParseCommand parses the positional arguments left after configuration flags, starting with "migrate".
+func ParseCommand(args []string) (Command, error) {
+	if len(args) < 2 {
+		return Command{}, errors.New(Usage)
+	}
+
+	command := Command{Action: args[1], Steps: 1}
+	switch {
+	case command.Action == UpAction && len(args) == 2:
+	case command.Action == StatusAction && len(args) == 2:
+	case command.Action == DownAction && len(args) == 2:
+	case command.Action == DownAction && len(args) == 3:
+		steps, parseErr := strconv.Atoi(args[2])
+		if parseErr != nil || steps < 1 {
+			return Command{}, fmt.Errorf("steps must be a positive integer, got %q", args[2])
+		}
+		command.Steps = steps
+	case command.Action == CreateAction && len(args) == 3:
+		command.Name = args[2]
+	default:
+		return Command{}, errors.New(Usage)
+	}
+
+	return command, nil
+}
+
+func (command Command) NeedsDatabase() bool {
+	return command.Action != CreateAction
+}
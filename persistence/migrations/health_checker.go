@@ -0,0 +1,21 @@
+package migrations
+
+import (
+	"Service-schema/core/health"
+	"context"
+	"fmt"
+)
+
+// NewHealthChecker reports the schema as down while any embedded migration is still pending.
+func NewHealthChecker(migrator IMigrator) health.IChecker {
+	return health.CheckerFunc(func(ctx context.Context) error {
+		pending, pendingErr := migrator.Pending(ctx)
+		if pendingErr != nil {
+			return pendingErr
+		}
+		if len(pending) > 0 {
+			return fmt.Errorf("%d pending migration(s), starting at %d_%s", len(pending), pending[0].Version, pending[0].Name)
+		}
+		return nil
+	})
+}
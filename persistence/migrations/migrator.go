@@ -0,0 +1,355 @@
+package migrations
+
+import (
+	"Service-schema/core/logging"
+	"context"
+	"embed"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed sql/*.sql
+var migrationFiles embed.FS
+
+// SourceDirectory is where Create writes new migration files, relative to the repository root.
+const SourceDirectory = "persistence/migrations/sql"
+
+// advisoryLockKey serializes migration runs across every instance sharing the database.
+const advisoryLockKey = int64(7140419552)
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int64
+	Name    string
+	UpSQL   string
+	DownSQL string
+}
+
+type MigrationStatus struct {
+	Migration Migration
+	AppliedAt *time.Time
+}
+
+type IMigrator interface {
+	Up(ctx context.Context) ([]Migration, error)
+	Down(ctx context.Context, steps int) ([]Migration, error)
+	Status(ctx context.Context) ([]MigrationStatus, error)
+	Pending(ctx context.Context) ([]Migration, error)
+}
+
+type Migrator struct {
+	dbPool     *pgxpool.Pool
+	migrations []Migration
+	logger     *slog.Logger
+}
+
+func NewMigrator(dbPool *pgxpool.Pool, logger *slog.Logger) (IMigrator, error) {
+	migrations, loadErr := LoadMigrations()
+	if loadErr != nil {
+		return nil, loadErr
+	}
+
+	return &Migrator{
+		dbPool:     dbPool,
+		migrations: migrations,
+		logger:     logger,
+	}, nil
+}
+
+// LoadMigrations reads the embedded SQL files ordered by version.
+func LoadMigrations() ([]Migration, error) {
+	entries, readErr := fs.ReadDir(migrationFiles, "sql")
+	if readErr != nil {
+		return nil, readErr
+	}
+
+	migrationsByVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		matches := migrationFileName.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			return nil, fmt.Errorf("invalid migration file name %s", entry.Name())
+		}
+
+		version, _ := strconv.ParseInt(matches[1], 10, 64)
+		content, contentErr := migrationFiles.ReadFile("sql/" + entry.Name())
+		if contentErr != nil {
+			return nil, contentErr
+		}
+
+		migration, found := migrationsByVersion[version]
+		if !found {
+			migration = &Migration{Version: version, Name: matches[2]}
+			migrationsByVersion[version] = migration
+		}
+		if migration.Name != matches[2] {
+			return nil, fmt.Errorf("migration version %d is used by both %s and %s", version, migration.Name, matches[2])
+		}
+
+		if matches[3] == "up" {
+			migration.UpSQL = string(content)
+		} else {
+			migration.DownSQL = string(content)
+		}
+	}
+
+	var migrations []Migration
+	for _, migration := range migrationsByVersion {
+		if migration.UpSQL == "" || migration.DownSQL == "" {
+			return nil, fmt.Errorf("migration %d_%s must have both up and down files", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+func (migrator *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+
+	err := migrator.withLock(ctx, func(conn *pgxpool.Conn) error {
+		appliedVersions, appliedErr := getAppliedVersions(ctx, conn)
+		if appliedErr != nil {
+			return appliedErr
+		}
+
+		for _, migration := range migrator.migrations {
+			if _, found := appliedVersions[migration.Version]; found {
+				continue
+			}
+
+			migrationErr := runInTransaction(ctx, conn, func(tx pgx.Tx) error {
+				if _, execErr := tx.Exec(ctx, migration.UpSQL); execErr != nil {
+					return execErr
+				}
+				_, insertErr := tx.Exec(ctx, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, migration.Version, migration.Name)
+				return insertErr
+			})
+			if migrationErr != nil {
+				return fmt.Errorf("applying migration %d_%s: %w", migration.Version, migration.Name, migrationErr)
+			}
+
+			migrator.logger.InfoContext(ctx, "Applied migration", logging.MigrationVersionKey, migration.Version, logging.MigrationNameKey, migration.Name)
+			applied = append(applied, migration)
+		}
+
+		return nil
+	})
+
+	return applied, err
+}
+
+func (migrator *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var rolledBack []Migration
+
+	err := migrator.withLock(ctx, func(conn *pgxpool.Conn) error {
+		appliedVersions, appliedErr := getAppliedVersions(ctx, conn)
+		if appliedErr != nil {
+			return appliedErr
+		}
+
+		for index := len(migrator.migrations) - 1; index >= 0 && len(rolledBack) < steps; index-- {
+			migration := migrator.migrations[index]
+			if _, found := appliedVersions[migration.Version]; !found {
+				continue
+			}
+
+			migrationErr := runInTransaction(ctx, conn, func(tx pgx.Tx) error {
+				if _, execErr := tx.Exec(ctx, migration.DownSQL); execErr != nil {
+					return execErr
+				}
+				_, deleteErr := tx.Exec(ctx, `DELETE FROM schema_migrations WHERE version = $1`, migration.Version)
+				return deleteErr
+			})
+			if migrationErr != nil {
+				return fmt.Errorf("rolling back migration %d_%s: %w", migration.Version, migration.Name, migrationErr)
+			}
+
+			migrator.logger.InfoContext(ctx, "Rolled back migration", logging.MigrationVersionKey, migration.Version, logging.MigrationNameKey, migration.Name)
+			rolledBack = append(rolledBack, migration)
+		}
+
+		return nil
+	})
+
+	return rolledBack, err
+}
+
+// Status reports when each embedded migration was applied. It only reads, so it neither waits for a running
+// migration nor creates the schema_migrations table.
+func (migrator *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	appliedVersions, appliedErr := migrator.readAppliedVersions(ctx)
+	if appliedErr != nil {
+		return nil, appliedErr
+	}
+
+	var statuses []MigrationStatus
+	for _, migration := range migrator.migrations {
+		status := MigrationStatus{Migration: migration}
+		if appliedAt, found := appliedVersions[migration.Version]; found {
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// Pending lists the embedded migrations not applied yet. Like Status it only reads, so it is cheap enough to poll.
+func (migrator *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	appliedVersions, appliedErr := migrator.readAppliedVersions(ctx)
+	if appliedErr != nil {
+		return nil, appliedErr
+	}
+
+	var pending []Migration
+	for _, migration := range migrator.migrations {
+		if _, found := appliedVersions[migration.Version]; !found {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, nil
+}
+
+// Create writes an empty up/down pair into directory, numbered after the newest migration either embedded in the
+// binary or already written to directory. It never overwrites a file; if the pair cannot be written in full,
+// the file it did write is removed again.
+func Create(directory string, name string) ([]string, error) {
+	if !regexp.MustCompile(`^\w+$`).MatchString(name) {
+		return nil, fmt.Errorf("migration name %q may only contain letters, digits and underscores", name)
+	}
+
+	migrations, loadErr := LoadMigrations()
+	if loadErr != nil {
+		return nil, loadErr
+	}
+	latestVersion := int64(0)
+	if len(migrations) > 0 {
+		latestVersion = migrations[len(migrations)-1].Version
+	}
+
+	entries, readErr := os.ReadDir(directory)
+	if readErr != nil {
+		return nil, readErr
+	}
+	for _, entry := range entries {
+		if matches := migrationFileName.FindStringSubmatch(entry.Name()); matches != nil {
+			version, _ := strconv.ParseInt(matches[1], 10, 64)
+			latestVersion = max(latestVersion, version)
+		}
+	}
+
+	var paths []string
+	for _, direction := range []string{"up", "down"} {
+		path := filepath.Join(directory, fmt.Sprintf("%04d_%s.%s.sql", latestVersion+1, strings.ToLower(name), direction))
+		file, createErr := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if createErr == nil {
+			createErr = file.Close()
+		}
+		if createErr != nil {
+			for _, createdPath := range paths {
+				_ = os.Remove(createdPath)
+			}
+			return nil, createErr
+		}
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
+
+func (migrator *Migrator) withLock(ctx context.Context, action func(conn *pgxpool.Conn) error) error {
+	conn, acquireErr := migrator.dbPool.Acquire(ctx)
+	if acquireErr != nil {
+		return acquireErr
+	}
+	defer conn.Release()
+
+	if _, lockErr := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, advisoryLockKey); lockErr != nil {
+		return lockErr
+	}
+	defer func() {
+		if _, unlockErr := conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, advisoryLockKey); unlockErr != nil {
+			migrator.logger.ErrorContext(ctx, "Error occurred releasing migration lock", logging.ErrorKey, unlockErr)
+		}
+	}()
+
+	createTableSQL := `CREATE TABLE IF NOT EXISTS schema_migrations
+(
+    version    bigint      NOT NULL PRIMARY KEY,
+    name       text        NOT NULL,
+    applied_at timestamptz NOT NULL DEFAULT now()
+)`
+	if _, createErr := conn.Exec(ctx, createTableSQL); createErr != nil {
+		return createErr
+	}
+
+	return action(conn)
+}
+
+// readAppliedVersions reads the applied versions without the migration lock; a database that was never migrated
+// has none.
+func (migrator *Migrator) readAppliedVersions(ctx context.Context) (map[int64]time.Time, error) {
+	conn, acquireErr := migrator.dbPool.Acquire(ctx)
+	if acquireErr != nil {
+		return nil, acquireErr
+	}
+	defer conn.Release()
+
+	var tableName *string
+	if tableErr := conn.QueryRow(ctx, `SELECT to_regclass('schema_migrations')::text`).Scan(&tableName); tableErr != nil {
+		return nil, tableErr
+	}
+	if tableName == nil {
+		return map[int64]time.Time{}, nil
+	}
+
+	return getAppliedVersions(ctx, conn)
+}
+
+func getAppliedVersions(ctx context.Context, conn *pgxpool.Conn) (map[int64]time.Time, error) {
+	rows, queryErr := conn.Query(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	defer rows.Close()
+
+	appliedVersions := map[int64]time.Time{}
+	for rows.Next() {
+		var version int64
+		var appliedAt time.Time
+		if scanErr := rows.Scan(&version, &appliedAt); scanErr != nil {
+			return nil, scanErr
+		}
+		appliedVersions[version] = appliedAt
+	}
+
+	return appliedVersions, rows.Err()
+}
+
+func runInTransaction(ctx context.Context, conn *pgxpool.Conn, action func(tx pgx.Tx) error) error {
+	tx, beginErr := conn.Begin(ctx)
+	if beginErr != nil {
+		return beginErr
+	}
+
+	if actionErr := action(tx); actionErr != nil {
+		_ = tx.Rollback(ctx)
+		return actionErr
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS products
+(
+    id       bigserial    NOT NULL PRIMARY KEY,
+    name     varchar(255) NOT NULL,
+    price    double precision NOT NULL,
+    discount double precision,
+    store    varchar(255) NOT NULL
+);
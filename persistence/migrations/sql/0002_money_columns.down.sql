@@ -0,0 +1,6 @@
+ALTER TABLE products
+    DROP COLUMN currency,
+    ALTER COLUMN discount DROP NOT NULL,
+    ALTER COLUMN discount DROP DEFAULT,
+    ALTER COLUMN discount TYPE double precision,
+    ALTER COLUMN price TYPE double precision;
@@ -0,0 +1,9 @@
+-- Every existing product becomes a USD amount, so prices are rounded half away from zero to whole cents: a price
+-- stored with more precision, such as 9.999, changes to 10.00 and its extra digits are lost. Discounts are
+-- percentages rather than amounts and keep the four fractional digits of the new column.
+ALTER TABLE products
+    ALTER COLUMN price TYPE numeric(19, 4) USING round(price::numeric, 2),
+    ALTER COLUMN discount TYPE numeric(7, 4) USING round(coalesce(discount, 0)::numeric, 4),
+    ALTER COLUMN discount SET DEFAULT 0,
+    ALTER COLUMN discount SET NOT NULL,
+    ADD COLUMN currency char(3) NOT NULL DEFAULT 'USD';
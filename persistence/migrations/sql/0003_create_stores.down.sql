@@ -0,0 +1,12 @@
+ALTER TABLE products ADD COLUMN store varchar(255);
+
+UPDATE products
+SET store = stores.name
+FROM stores
+WHERE products.store_id = stores.id;
+
+ALTER TABLE products
+    ALTER COLUMN store SET NOT NULL,
+    DROP COLUMN store_id;
+
+DROP TABLE stores;
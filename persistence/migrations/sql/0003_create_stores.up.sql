@@ -0,0 +1,25 @@
+CREATE TABLE stores
+(
+    id   bigserial    NOT NULL PRIMARY KEY,
+    name varchar(255) NOT NULL
+);
+
+CREATE UNIQUE INDEX stores_lower_name_key ON stores (lower(name));
+
+INSERT INTO stores (name)
+SELECT DISTINCT ON (lower(store)) store
+FROM products
+ORDER BY lower(store), store;
+
+ALTER TABLE products ADD COLUMN store_id bigint REFERENCES stores (id);
+
+UPDATE products
+SET store_id = stores.id
+FROM stores
+WHERE lower(products.store) = lower(stores.name);
+
+ALTER TABLE products
+    ALTER COLUMN store_id SET NOT NULL,
+    DROP COLUMN store;
+
+CREATE INDEX products_store_id_idx ON products (store_id);
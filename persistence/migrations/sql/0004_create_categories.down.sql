@@ -0,0 +1,5 @@
+DROP TABLE product_categories;
+
+DROP TABLE category_closure;
+
+DROP TABLE categories;
@@ -0,0 +1,27 @@
+CREATE TABLE categories
+(
+    id        bigserial    NOT NULL PRIMARY KEY,
+    name      varchar(255) NOT NULL,
+    parent_id bigint REFERENCES categories (id)
+);
+
+CREATE UNIQUE INDEX categories_parent_id_lower_name_key ON categories (coalesce(parent_id, 0), lower(name));
+
+CREATE TABLE category_closure
+(
+    ancestor_id   bigint  NOT NULL REFERENCES categories (id) ON DELETE CASCADE,
+    descendant_id bigint  NOT NULL REFERENCES categories (id) ON DELETE CASCADE,
+    depth         integer NOT NULL,
+    PRIMARY KEY (ancestor_id, descendant_id)
+);
+
+CREATE INDEX category_closure_descendant_id_idx ON category_closure (descendant_id);
+
+CREATE TABLE product_categories
+(
+    product_id  bigint NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    category_id bigint NOT NULL REFERENCES categories (id) ON DELETE CASCADE,
+    PRIMARY KEY (product_id, category_id)
+);
+
+CREATE INDEX product_categories_category_id_idx ON product_categories (category_id);
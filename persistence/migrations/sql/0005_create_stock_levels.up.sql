@@ -0,0 +1,9 @@
+CREATE TABLE stock_levels
+(
+    product_id bigint      NOT NULL PRIMARY KEY REFERENCES products (id) ON DELETE CASCADE,
+    on_hand    bigint      NOT NULL DEFAULT 0,
+    reserved   bigint      NOT NULL DEFAULT 0,
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT stock_levels_on_hand_check CHECK (on_hand >= 0),
+    CONSTRAINT stock_levels_reserved_check CHECK (reserved >= 0 AND reserved <= on_hand)
+);
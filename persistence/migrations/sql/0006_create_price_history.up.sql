@@ -0,0 +1,16 @@
+CREATE TABLE price_history
+(
+    id           bigserial      NOT NULL PRIMARY KEY,
+    product_id   bigint         NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    old_price    numeric(19, 4) NOT NULL,
+    new_price    numeric(19, 4) NOT NULL,
+    old_currency char(3)        NOT NULL,
+    new_currency char(3)        NOT NULL,
+    old_discount numeric(7, 4)  NOT NULL,
+    new_discount numeric(7, 4)  NOT NULL,
+    actor        varchar(255)   NOT NULL,
+    reason       text           NOT NULL DEFAULT '',
+    changed_at   timestamptz    NOT NULL DEFAULT now()
+);
+
+CREATE INDEX price_history_product_id_changed_at_idx ON price_history (product_id, changed_at);
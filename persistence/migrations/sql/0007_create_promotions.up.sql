@@ -0,0 +1,19 @@
+CREATE TABLE promotions
+(
+    id         bigserial      NOT NULL PRIMARY KEY,
+    name       varchar(255)   NOT NULL,
+    type       varchar(16)    NOT NULL,
+    value      numeric(19, 4) NOT NULL,
+    currency   char(3),
+    store_id   bigint REFERENCES stores (id) ON DELETE CASCADE,
+    product_id bigint REFERENCES products (id) ON DELETE CASCADE,
+    stackable  boolean        NOT NULL DEFAULT false,
+    starts_at  timestamptz    NOT NULL,
+    ends_at    timestamptz    NOT NULL,
+    CONSTRAINT promotions_type_check CHECK (type IN ('percentage', 'fixed_amount')),
+    CONSTRAINT promotions_value_check CHECK (value > 0),
+    CONSTRAINT promotions_currency_check CHECK (type <> 'fixed_amount' OR currency IS NOT NULL),
+    CONSTRAINT promotions_period_check CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX promotions_period_idx ON promotions (starts_at, ends_at);
@@ -0,0 +1,10 @@
+DO
+$$
+    BEGIN
+        IF EXISTS (SELECT 1 FROM products WHERE deleted_at IS NOT NULL) THEN
+            RAISE EXCEPTION 'products holds soft deleted rows; restore or purge them before rolling back, since dropping deleted_at would make them live again';
+        END IF;
+    END
+$$;
+
+ALTER TABLE products DROP COLUMN deleted_at;
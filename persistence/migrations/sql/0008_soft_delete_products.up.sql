@@ -0,0 +1,3 @@
+ALTER TABLE products ADD COLUMN deleted_at timestamptz;
+
+CREATE INDEX products_deleted_at_idx ON products (deleted_at) WHERE deleted_at IS NOT NULL;
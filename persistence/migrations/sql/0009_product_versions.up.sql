@@ -0,0 +1 @@
+ALTER TABLE products ADD COLUMN version bigint NOT NULL DEFAULT 1;
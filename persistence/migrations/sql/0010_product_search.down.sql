@@ -0,0 +1,10 @@
+DROP INDEX products_name_trgm_idx;
+DROP INDEX products_search_vector_idx;
+
+DROP TRIGGER stores_refresh_product_search_vectors ON stores;
+DROP FUNCTION stores_refresh_product_search_vectors();
+DROP TRIGGER products_refresh_search_vector ON products;
+DROP FUNCTION products_refresh_search_vector();
+DROP FUNCTION product_search_vector(text, text);
+
+ALTER TABLE products DROP COLUMN search_vector;
@@ -0,0 +1,50 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE products ADD COLUMN search_vector tsvector;
+
+CREATE FUNCTION product_search_vector(product_name text, store_name text) RETURNS tsvector
+    LANGUAGE sql IMMUTABLE AS
+$$
+SELECT setweight(to_tsvector('simple', coalesce(product_name, '')), 'A') ||
+       setweight(to_tsvector('simple', coalesce(store_name, '')), 'B')
+$$;
+
+CREATE FUNCTION products_refresh_search_vector() RETURNS trigger
+    LANGUAGE plpgsql AS
+$$
+BEGIN
+    NEW.search_vector := product_search_vector(NEW.name, (SELECT name FROM stores WHERE id = NEW.store_id));
+    RETURN NEW;
+END
+$$;
+
+CREATE TRIGGER products_refresh_search_vector
+    BEFORE INSERT OR UPDATE OF name, store_id
+    ON products
+    FOR EACH ROW
+EXECUTE FUNCTION products_refresh_search_vector();
+
+CREATE FUNCTION stores_refresh_product_search_vectors() RETURNS trigger
+    LANGUAGE plpgsql AS
+$$
+BEGIN
+    UPDATE products SET search_vector = product_search_vector(name, NEW.name) WHERE store_id = NEW.id;
+    RETURN NULL;
+END
+$$;
+
+CREATE TRIGGER stores_refresh_product_search_vectors
+    AFTER UPDATE OF name
+    ON stores
+    FOR EACH ROW
+    WHEN (OLD.name IS DISTINCT FROM NEW.name)
+EXECUTE FUNCTION stores_refresh_product_search_vectors();
+
+UPDATE products p
+SET search_vector = product_search_vector(p.name, s.name)
+FROM stores s
+WHERE s.id = p.store_id;
+
+CREATE INDEX products_search_vector_idx ON products USING gin (search_vector);
+
+CREATE INDEX products_name_trgm_idx ON products USING gin (name gin_trgm_ops);
@@ -0,0 +1,13 @@
+DO
+$$
+    BEGIN
+        IF EXISTS (SELECT 1 FROM price_history ph WHERE NOT EXISTS (SELECT 1 FROM products p WHERE p.id = ph.product_id)) THEN
+            RAISE EXCEPTION 'price_history holds changes of purged products, which the cascading foreign key cannot reference';
+        END IF;
+    END
+$$;
+
+ALTER TABLE price_history DROP COLUMN product_name;
+
+ALTER TABLE price_history
+    ADD CONSTRAINT price_history_product_id_fkey FOREIGN KEY (product_id) REFERENCES products (id) ON DELETE CASCADE;
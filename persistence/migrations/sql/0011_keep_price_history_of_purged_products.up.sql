@@ -0,0 +1,10 @@
+ALTER TABLE price_history DROP CONSTRAINT price_history_product_id_fkey;
+
+ALTER TABLE price_history ADD COLUMN product_name varchar(255);
+
+UPDATE price_history
+SET product_name = p.name
+FROM products p
+WHERE p.id = price_history.product_id;
+
+ALTER TABLE price_history ALTER COLUMN product_name SET NOT NULL;
@@ -0,0 +1,38 @@
+package persistence
+
+import (
+	"errors"
+	"github.com/jackc/pgconn"
+	"strings"
+)
+
+const uniqueViolationCode = "23505"
+const foreignKeyViolationCode = "23503"
+
+func isUniqueViolation(err error) bool {
+	return hasPostgresErrorCode(err, uniqueViolationCode)
+}
+
+func isForeignKeyViolation(err error) bool {
+	return hasPostgresErrorCode(err, foreignKeyViolationCode)
+}
+
+// isRowDataViolation reports errors caused by the values of a row rather than by the database, namely integrity
+// constraint violations (class 23) and data exceptions such as a numeric overflow (class 22).
+func isRowDataViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && (strings.HasPrefix(pgErr.Code, "23") || strings.HasPrefix(pgErr.Code, "22"))
+}
+
+func postgresErrorMessage(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Message
+	}
+	return err.Error()
+}
+
+func hasPostgresErrorCode(err error, code string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == code
+}
@@ -0,0 +1,130 @@
+package persistence
+
+import (
+	"Service-schema/core/audit"
+	"Service-schema/core/logging"
+	"Service-schema/core/postgresql"
+	"Service-schema/domain"
+	"Service-schema/domain/domainerror"
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"log/slog"
+)
+
+const priceChangeColumns = "id, product_id, product_name, old_price::text, new_price::text, old_currency, new_currency, old_discount::text, new_discount::text, actor, reason, changed_at"
+
+type IPriceHistoryRepository interface {
+	GetPriceHistory(ctx context.Context, query domain.PriceHistoryQuery) ([]domain.PriceChange, error)
+}
+
+type PriceHistoryRepository struct {
+	dbPool        *pgxpool.Pool
+	queryTimeouts postgresql.QueryTimeouts
+	logger        *slog.Logger
+}
+
+func NewPriceHistoryRepository(dbPool *pgxpool.Pool, queryTimeouts postgresql.QueryTimeouts, logger *slog.Logger) IPriceHistoryRepository {
+	return &PriceHistoryRepository{
+		dbPool:        dbPool,
+		queryTimeouts: queryTimeouts,
+		logger:        logger,
+	}
+}
+
+func (priceHistoryRepository *PriceHistoryRepository) GetPriceHistory(ctx context.Context, query domain.PriceHistoryQuery) ([]domain.PriceChange, error) {
+	ctx, cancel := priceHistoryRepository.queryTimeouts.ForRead(ctx)
+	defer cancel()
+
+	builder := &productQueryBuilder{}
+	builder.addCondition("product_id = %s", query.ProductId)
+	if !query.IncludeDeleted {
+		builder.conditions = append(builder.conditions, "EXISTS (SELECT 1 FROM products p WHERE p.id = price_history.product_id AND p.deleted_at IS NULL)")
+	}
+	if query.From != nil {
+		builder.addCondition("changed_at >= %s", *query.From)
+	}
+	if query.To != nil {
+		builder.addCondition("changed_at < %s", *query.To)
+	}
+
+	selectSQL := `SELECT ` + priceChangeColumns + ` FROM price_history` + builder.where() + ` ORDER BY changed_at, id`
+	priceChangeRows, err := connectionFrom(ctx, priceHistoryRepository.dbPool).Query(ctx, selectSQL, builder.args...)
+	if err != nil {
+		priceHistoryRepository.logger.ErrorContext(ctx, "Error occurred getting price history", logging.ErrorKey, err)
+		return nil, domainerror.Internal(domain.PriceHistoryStorageCode, fmt.Sprintf("Error occurred getting price history of product %d", query.ProductId), err)
+	}
+	defer priceChangeRows.Close()
+
+	priceChanges := []domain.PriceChange{}
+	for priceChangeRows.Next() {
+		priceChange, scanErr := scanPriceChange(priceChangeRows)
+		if scanErr != nil {
+			return nil, domainerror.Internal(domain.PriceHistoryStorageCode, fmt.Sprintf("Error occurred getting price history of product %d", query.ProductId), scanErr)
+		}
+		priceChanges = append(priceChanges, priceChange)
+	}
+	if rowsErr := priceChangeRows.Err(); rowsErr != nil {
+		priceHistoryRepository.logger.ErrorContext(ctx, "Error occurred reading price history", logging.ErrorKey, rowsErr)
+		return nil, domainerror.Internal(domain.PriceHistoryStorageCode, fmt.Sprintf("Error occurred getting price history of product %d", query.ProductId), rowsErr)
+	}
+
+	return priceChanges, nil
+}
+
+func newPriceChange(ctx context.Context, currentProduct domain.Product, updatedProduct domain.Product) domain.PriceChange {
+	change := audit.FromContext(ctx)
+	return domain.PriceChange{
+		ProductId:   currentProduct.Id,
+		ProductName: updatedProduct.Name,
+		OldPrice:    currentProduct.Price,
+		NewPrice:    updatedProduct.Price,
+		OldDiscount: currentProduct.Discount,
+		NewDiscount: updatedProduct.Discount,
+		Actor:       change.Actor,
+		Reason:      change.Reason,
+	}
+}
+
+func insertPriceChange(ctx context.Context, tx pgx.Tx, priceChange domain.PriceChange) error {
+	insertSQL := `INSERT INTO price_history (product_id, product_name, old_price, new_price, old_currency, new_currency, old_discount, new_discount, actor, reason)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+	_, err := tx.Exec(ctx, insertSQL, priceChange.ProductId, priceChange.ProductName,
+		priceChange.OldPrice.Amount.String(), priceChange.NewPrice.Amount.String(),
+		priceChange.OldPrice.Currency, priceChange.NewPrice.Currency,
+		priceChange.OldDiscount.String(), priceChange.NewDiscount.String(),
+		priceChange.Actor, priceChange.Reason)
+	return err
+}
+
+func scanPriceChange(priceChangeRow pgx.Row) (domain.PriceChange, error) {
+	var priceChange domain.PriceChange
+	var oldPrice, newPrice, oldDiscount, newDiscount string
+	scanErr := priceChangeRow.Scan(&priceChange.Id, &priceChange.ProductId, &priceChange.ProductName, &oldPrice, &newPrice,
+		&priceChange.OldPrice.Currency, &priceChange.NewPrice.Currency, &oldDiscount, &newDiscount,
+		&priceChange.Actor, &priceChange.Reason, &priceChange.ChangedAt)
+	if scanErr != nil {
+		return domain.PriceChange{}, scanErr
+	}
+
+	decimals := []struct {
+		name   string
+		value  string
+		target *domain.Decimal
+	}{
+		{"old_price", oldPrice, &priceChange.OldPrice.Amount},
+		{"new_price", newPrice, &priceChange.NewPrice.Amount},
+		{"old_discount", oldDiscount, &priceChange.OldDiscount},
+		{"new_discount", newDiscount, &priceChange.NewDiscount},
+	}
+	for _, decimal := range decimals {
+		parsed, parseErr := domain.ParseDecimal(decimal.value)
+		if parseErr != nil {
+			return domain.PriceChange{}, fmt.Errorf("%s %s %w", decimal.name, decimal.value, parseErr)
+		}
+		*decimal.target = parsed
+	}
+
+	return priceChange, nil
+}
@@ -0,0 +1,154 @@
+package persistence
+
+import (
+	"Service-schema/domain"
+	"fmt"
+	"strings"
+)
+
+var productSortColumns = map[domain.ProductSortField]string{
+	domain.SortById:       "p.id",
+	domain.SortByName:     "p.name",
+	domain.SortByPrice:    "p.price",
+	domain.SortByDiscount: "p.discount",
+}
+
+var productSortColumnTypes = map[domain.ProductSortField]string{
+	domain.SortById:       "bigint",
+	domain.SortByName:     "text",
+	domain.SortByPrice:    "numeric",
+	domain.SortByDiscount: "numeric",
+}
+
+var likePatternEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+type productQueryBuilder struct {
+	conditions []string
+	args       []interface{}
+}
+
+func (builder *productQueryBuilder) addArg(arg interface{}) string {
+	builder.args = append(builder.args, arg)
+	return fmt.Sprintf("$%d", len(builder.args))
+}
+
+func (builder *productQueryBuilder) addCondition(format string, args ...interface{}) {
+	placeholders := make([]interface{}, len(args))
+	for index, arg := range args {
+		placeholders[index] = builder.addArg(arg)
+	}
+	builder.conditions = append(builder.conditions, fmt.Sprintf(format, placeholders...))
+}
+
+func (builder *productQueryBuilder) where() string {
+	if len(builder.conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(builder.conditions, " AND ")
+}
+
+func newProductQueryBuilder(query domain.ProductQuery) *productQueryBuilder {
+	builder := &productQueryBuilder{}
+
+	if !query.IncludeDeleted {
+		builder.conditions = append(builder.conditions, "p.deleted_at IS NULL")
+	}
+	if query.StoreId != 0 {
+		builder.addCondition("p.store_id = %s", query.StoreId)
+	}
+	if query.CategoryId != 0 {
+		builder.addCondition(`EXISTS (SELECT 1 FROM product_categories pc
+			JOIN category_closure cc ON cc.descendant_id = pc.category_id
+			WHERE pc.product_id = p.id AND cc.ancestor_id = %s)`, query.CategoryId)
+	}
+	if query.NameContains != "" {
+		builder.addCondition("p.name ILIKE '%%' || %s || '%%'", likePatternEscaper.Replace(query.NameContains))
+	}
+	if query.MinPrice != nil {
+		builder.addCondition("p.price >= %s", query.MinPrice.String())
+	}
+	if query.MaxPrice != nil {
+		builder.addCondition("p.price <= %s", query.MaxPrice.String())
+	}
+	if query.MinDiscount != nil {
+		builder.addCondition("p.discount >= %s", query.MinDiscount.String())
+	}
+	if query.MaxDiscount != nil {
+		builder.addCondition("p.discount <= %s", query.MaxDiscount.String())
+	}
+
+	return builder
+}
+
+func buildCountProductsQuery(query domain.ProductQuery) (string, []interface{}) {
+	builder := newProductQueryBuilder(query)
+	return "SELECT count(*) FROM products p" + builder.where(), builder.args
+}
+
+// buildSelectProductsQuery selects one row beyond query.Limit so the caller can tell whether a next page exists.
+func buildSelectProductsQuery(query domain.ProductQuery) (string, []interface{}) {
+	builder := newProductQueryBuilder(query)
+	column := productSortColumns[query.SortField]
+	comparison := ">"
+	if query.SortDirection == domain.Descending {
+		comparison = "<"
+	}
+
+	if query.Cursor != nil {
+		if query.SortField == domain.SortById {
+			builder.addCondition("p.id "+comparison+" %s", query.Cursor.Id)
+		} else {
+			builder.addCondition(fmt.Sprintf("(%s, p.id) %s (%%s::%s, %%s)", column, comparison, productSortColumnTypes[query.SortField]), query.Cursor.SortValue, query.Cursor.Id)
+		}
+	}
+
+	sql := "SELECT " + productColumns + productsFromClause + builder.where() + orderProductsBy(query)
+	sql += fmt.Sprintf(" LIMIT %s OFFSET %s", builder.addArg(query.Limit+1), builder.addArg(query.Offset))
+
+	return sql, builder.args
+}
+
+// buildExportProductsQuery selects every product matching the filters of query in its sort order, ignoring paging.
+func buildExportProductsQuery(query domain.ProductQuery) (string, []interface{}) {
+	builder := newProductQueryBuilder(query)
+	return "SELECT " + productColumns + productsFromClause + builder.where() + orderProductsBy(query), builder.args
+}
+
+func orderProductsBy(query domain.ProductQuery) string {
+	direction := "ASC"
+	if query.SortDirection == domain.Descending {
+		direction = "DESC"
+	}
+
+	orderBy := fmt.Sprintf(" ORDER BY %s %s", productSortColumns[query.SortField], direction)
+	if query.SortField != domain.SortById {
+		orderBy += fmt.Sprintf(", p.id %s", direction)
+	}
+	return orderBy
+}
+
+// buildUpdateProductQuery sets only the changed columns and bumps the version, matching the row only while it
+// still has currentVersion.
+func buildUpdateProductQuery(productId int64, currentVersion int64, changes domain.ProductChanges) (string, []interface{}) {
+	builder := &productQueryBuilder{}
+	id := builder.addArg(productId)
+	version := builder.addArg(currentVersion)
+
+	var assignments []string
+	if changes.Name != nil {
+		assignments = append(assignments, "name = "+builder.addArg(*changes.Name))
+	}
+	if changes.Price != nil {
+		assignments = append(assignments, "price = "+builder.addArg(changes.Price.Amount.String()))
+		assignments = append(assignments, "currency = "+builder.addArg(changes.Price.Currency))
+	}
+	if changes.Discount != nil {
+		assignments = append(assignments, "discount = "+builder.addArg(changes.Discount.String()))
+	}
+	if changes.StoreId != nil {
+		assignments = append(assignments, "store_id = "+builder.addArg(*changes.StoreId))
+	}
+	assignments = append(assignments, "version = version + 1")
+
+	return fmt.Sprintf("UPDATE products SET %s WHERE id = %s AND version = %s", strings.Join(assignments, ", "), id, version), builder.args
+}
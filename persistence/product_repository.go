@@ -1,151 +1,462 @@
 package persistence
 
 import (
+	"Service-schema/core/logging"
+	"Service-schema/core/postgresql"
 	"Service-schema/domain"
-	"Service-schema/persistence/common"
+	"Service-schema/domain/domainerror"
 	"context"
 	"errors"
 	"fmt"
 	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
-	"github.com/labstack/gommon/log"
+	"log/slog"
+	"time"
 )
 
+const productColumns = "p.id, p.name, p.price::text, p.discount::text, p.currency, p.store_id, s.name, p.version, p.deleted_at"
+
+const productsFromClause = " FROM products p JOIN stores s ON s.id = p.store_id"
+
 type IProductRepository interface {
-	GetAllProducts() []domain.Product
-	GetAllProductsByStoreName(storeName string) []domain.Product
-	GetById(productId int64) (domain.Product, error)
-	Add(product domain.Product) error
-	DeleteById(productId int64) error
-	UpdatePrice(productId int64, price float32) error
+	GetProducts(ctx context.Context, query domain.ProductQuery) (domain.ProductPage, error)
+	ExportProducts(ctx context.Context, query domain.ProductQuery, write func(domain.Product) error) error
+	SearchProducts(ctx context.Context, query domain.ProductSearchQuery) (domain.ProductSearchResult, error)
+	GetById(ctx context.Context, productId int64) (domain.Product, error)
+	Add(ctx context.Context, product domain.Product) (domain.Product, error)
+	AddAll(ctx context.Context, products []domain.Product) ([]domain.Product, error)
+	DeleteById(ctx context.Context, productId int64, expectedVersion int64) error
+	UpdatePrice(ctx context.Context, productId int64, price domain.Money) error
+	Update(ctx context.Context, productId int64, expectedVersion int64, changes domain.ProductChanges) error
+	Restore(ctx context.Context, productId int64) (domain.Product, error)
+	PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error)
 }
 
 type ProductRepository struct {
-	dbPool *pgxpool.Pool
+	dbPool        *pgxpool.Pool
+	queryTimeouts postgresql.QueryTimeouts
+	logger        *slog.Logger
 }
 
-func NewProductRepository(dbPool *pgxpool.Pool) IProductRepository {
+func NewProductRepository(dbPool *pgxpool.Pool, queryTimeouts postgresql.QueryTimeouts, logger *slog.Logger) IProductRepository {
 	return &ProductRepository{
-		dbPool: dbPool,
+		dbPool:        dbPool,
+		queryTimeouts: queryTimeouts,
+		logger:        logger,
 	}
 }
 
-func (productRepository *ProductRepository) GetAllProducts() []domain.Product {
-	ctx := context.Background()
-	selectQuery := "SELECT * FROM products"
-	productRows, err := productRepository.dbPool.Query(ctx, selectQuery)
+func (productRepository *ProductRepository) GetProducts(ctx context.Context, query domain.ProductQuery) (domain.ProductPage, error) {
+	ctx, cancel := productRepository.queryTimeouts.ForRead(ctx)
+	defer cancel()
 
+	countQuery, countArgs := buildCountProductsQuery(query)
+	var totalCount int64
+	countErr := connectionFrom(ctx, productRepository.dbPool).QueryRow(ctx, countQuery, countArgs...).Scan(&totalCount)
+	if countErr != nil {
+		productRepository.logger.ErrorContext(ctx, "Error occurred counting products", logging.ErrorKey, countErr)
+		return domain.ProductPage{}, domainerror.Internal(domain.ProductStorageCode, "Error occurred getting products", countErr)
+	}
+
+	selectQuery, selectArgs := buildSelectProductsQuery(query)
+	productRows, err := connectionFrom(ctx, productRepository.dbPool).Query(ctx, selectQuery, selectArgs...)
 	if err != nil {
-		log.Error("Error occurred getting all products %v", err)
-		return []domain.Product{}
+		productRepository.logger.ErrorContext(ctx, "Error occurred getting products", logging.ErrorKey, err)
+		return domain.ProductPage{}, domainerror.Internal(domain.ProductStorageCode, "Error occurred getting products", err)
+	}
+
+	products, extractErr := productRepository.extractsAllProducts(ctx, productRows)
+	if extractErr != nil {
+		return domain.ProductPage{}, extractErr
 	}
 
-	return extractsAllProducts(productRows)
+	return domain.NewProductPage(query, products, totalCount), nil
 }
 
-func (productRepository *ProductRepository) GetAllProductsByStoreName(storeName string) []domain.Product {
-	ctx := context.Background()
-	selectProductsByStoreNameQuery := `SELECT * FROM products WHERE store=$1`
-	productRows, err := productRepository.dbPool.Query(ctx, selectProductsByStoreNameQuery, storeName)
+// ExportProducts hands every product matching the filters of query to write as soon as its row is scanned, so
+// the result set is never held in memory. Paging is ignored; an error from write stops the export and is
+// returned as is.
+func (productRepository *ProductRepository) ExportProducts(ctx context.Context, query domain.ProductQuery, write func(domain.Product) error) error {
+	ctx, cancel := productRepository.queryTimeouts.ForExport(ctx)
+	defer cancel()
 
+	exportQuery, exportArgs := buildExportProductsQuery(query)
+	productRows, err := connectionFrom(ctx, productRepository.dbPool).Query(ctx, exportQuery, exportArgs...)
 	if err != nil {
-		log.Error("Error occurred getting all products %v", err)
-		return []domain.Product{}
+		productRepository.logger.ErrorContext(ctx, "Error occurred exporting products", logging.ErrorKey, err)
+		return domainerror.Internal(domain.ProductStorageCode, "Error occurred exporting products", err)
+	}
+	defer productRows.Close()
+
+	for productRows.Next() {
+		product, scanErr := scanProduct(productRows)
+		if scanErr != nil {
+			productRepository.logger.ErrorContext(ctx, "Error occurred scanning exported product", logging.ErrorKey, scanErr)
+			return domainerror.Internal(domain.ProductStorageCode, "Error occurred exporting products", scanErr)
+		}
+		if writeErr := write(product); writeErr != nil {
+			return writeErr
+		}
+	}
+	if rowsErr := productRows.Err(); rowsErr != nil {
+		productRepository.logger.ErrorContext(ctx, "Error occurred exporting products", logging.ErrorKey, rowsErr)
+		return domainerror.Internal(domain.ProductStorageCode, "Error occurred exporting products", rowsErr)
 	}
 
-	return extractsAllProducts(productRows)
+	return nil
 }
 
-func (productRepository *ProductRepository) Add(product domain.Product) error {
-	ctx := context.Background()
-	insertSQL := `insert into products (name,price,discount,store) values ($1,$2,$3,$4)`
-	newProduct, err := productRepository.dbPool.Exec(ctx, insertSQL, product.Name, product.Price, product.Discount, product.Store)
+// SearchProducts ranks word matches of the search vector by cover density and adds the trigram word
+// similarity of the query to the product name, which alone is enough to match a misspelled name.
+func (productRepository *ProductRepository) SearchProducts(ctx context.Context, query domain.ProductSearchQuery) (domain.ProductSearchResult, error) {
+	ctx, cancel := productRepository.queryTimeouts.ForRead(ctx)
+	defer cancel()
+
+	matchesClause := productsFromClause + `, websearch_to_tsquery('simple', $1::text) AS q` +
+		` WHERE p.deleted_at IS NULL AND (p.search_vector @@ q OR $1::text <% p.name)`
+
+	facetsSQL := `SELECT p.store_id, s.name, count(*)` + matchesClause + ` GROUP BY p.store_id, s.name ORDER BY count(*) DESC, p.store_id`
+	facetRows, facetsErr := connectionFrom(ctx, productRepository.dbPool).Query(ctx, facetsSQL, query.Text)
+	if facetsErr != nil {
+		productRepository.logger.ErrorContext(ctx, "Error occurred searching products", logging.ErrorKey, facetsErr)
+		return domain.ProductSearchResult{}, domainerror.Internal(domain.ProductStorageCode, "Error occurred searching products", facetsErr)
+	}
+	result := domain.ProductSearchResult{StoreFacets: []domain.StoreFacet{}, Hits: []domain.ProductSearchHit{}}
+	for facetRows.Next() {
+		var facet domain.StoreFacet
+		if scanErr := facetRows.Scan(&facet.StoreId, &facet.Store, &facet.Count); scanErr != nil {
+			facetRows.Close()
+			productRepository.logger.ErrorContext(ctx, "Error occurred scanning store facet", logging.ErrorKey, scanErr)
+			return domain.ProductSearchResult{}, domainerror.Internal(domain.ProductStorageCode, "Error occurred searching products", scanErr)
+		}
+		if query.StoreId == 0 || facet.StoreId == query.StoreId {
+			result.TotalCount += facet.Count
+		}
+		result.StoreFacets = append(result.StoreFacets, facet)
+	}
+	facetRows.Close()
+	if rowsErr := facetRows.Err(); rowsErr != nil {
+		productRepository.logger.ErrorContext(ctx, "Error occurred searching products", logging.ErrorKey, rowsErr)
+		return domain.ProductSearchResult{}, domainerror.Internal(domain.ProductStorageCode, "Error occurred searching products", rowsErr)
+	}
+
+	hitsSQL := `SELECT ` + productColumns + `, (ts_rank_cd(p.search_vector, q) + word_similarity($1::text, p.name))::float8 AS rank,` +
+		` ts_headline('simple', p.name || ' - ' || s.name, q, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true')` +
+		matchesClause + ` AND ($2::bigint = 0 OR p.store_id = $2) ORDER BY rank DESC, p.id LIMIT $3 OFFSET $4`
+	hitRows, hitsErr := connectionFrom(ctx, productRepository.dbPool).Query(ctx, hitsSQL, query.Text, query.StoreId, query.Limit, query.Offset)
+	if hitsErr != nil {
+		productRepository.logger.ErrorContext(ctx, "Error occurred searching products", logging.ErrorKey, hitsErr)
+		return domain.ProductSearchResult{}, domainerror.Internal(domain.ProductStorageCode, "Error occurred searching products", hitsErr)
+	}
+	defer hitRows.Close()
+	for hitRows.Next() {
+		var hit domain.ProductSearchHit
+		var scanErr error
+		hit.Product, scanErr = scanProduct(hitRows, &hit.Rank, &hit.Snippet)
+		if scanErr != nil {
+			productRepository.logger.ErrorContext(ctx, "Error occurred scanning product search hit", logging.ErrorKey, scanErr)
+			return domain.ProductSearchResult{}, domainerror.Internal(domain.ProductStorageCode, "Error occurred searching products", scanErr)
+		}
+		result.Hits = append(result.Hits, hit)
+	}
+	if rowsErr := hitRows.Err(); rowsErr != nil {
+		productRepository.logger.ErrorContext(ctx, "Error occurred searching products", logging.ErrorKey, rowsErr)
+		return domain.ProductSearchResult{}, domainerror.Internal(domain.ProductStorageCode, "Error occurred searching products", rowsErr)
+	}
+
+	return result, nil
+}
+
+func (productRepository *ProductRepository) Add(ctx context.Context, product domain.Product) (domain.Product, error) {
+	ctx, cancel := productRepository.queryTimeouts.ForWrite(ctx)
+	defer cancel()
+	insertSQL := `WITH p AS (insert into products (name,price,discount,currency,store_id) values ($1,$2,$3,$4,$5) returning *) SELECT ` +
+		productColumns + ` FROM p JOIN stores s ON s.id = p.store_id`
+	productRow := connectionFrom(ctx, productRepository.dbPool).QueryRow(ctx, insertSQL, product.Name, product.Price.Amount.String(), product.Discount.String(), product.Price.Currency, product.StoreId)
+	newProduct, err := scanProduct(productRow)
+	if isForeignKeyViolation(err) {
+		return domain.Product{}, storeNotFoundForProductError(product.StoreId)
+	}
 	if err != nil {
-		log.Error("Error occurred inserting product %v", err)
-		return err
+		productRepository.logger.ErrorContext(ctx, "Error occurred inserting product", logging.ErrorKey, err)
+		return domain.Product{}, domainerror.Internal(domain.ProductStorageCode, "Error occurred inserting product", err)
 	}
 
-	log.Info(fmt.Sprintf("Added product %v", newProduct))
-	return nil
+	productRepository.logger.InfoContext(ctx, "Added product", logging.ProductIdKey, newProduct.Id)
+	return newProduct, nil
 }
 
-func (productRepository *ProductRepository) GetById(productId int64) (domain.Product, error) {
-	ctx := context.Background()
-	selectQuery := `SELECT * FROM products WHERE id = $1`
-	productRow := productRepository.dbPool.QueryRow(ctx, selectQuery, productId)
+// AddAll inserts the products with COPY. Their ids are drawn from the products sequence beforehand, since COPY
+// cannot return them. The insert runs in its own transaction, or savepoint when ctx carries one, so a batch
+// rejected for a constraint violation leaves an enclosing transaction usable.
+func (productRepository *ProductRepository) AddAll(ctx context.Context, products []domain.Product) ([]domain.Product, error) {
+	ctx, cancel := productRepository.queryTimeouts.ForWrite(ctx)
+	defer cancel()
+
+	if len(products) == 0 {
+		return []domain.Product{}, nil
+	}
+
+	var newProducts []domain.Product
+	txErr := connectionFrom(ctx, productRepository.dbPool).BeginFunc(ctx, func(tx pgx.Tx) error {
+		idRows, idErr := tx.Query(ctx, `SELECT nextval(pg_get_serial_sequence('products', 'id')) FROM generate_series(1, $1)`, len(products))
+		if idErr != nil {
+			return idErr
+		}
+
+		newProducts = make([]domain.Product, 0, len(products))
+		for index := 0; idRows.Next(); index++ {
+			product := products[index]
+			if scanErr := idRows.Scan(&product.Id); scanErr != nil {
+				idRows.Close()
+				return scanErr
+			}
+			product.Version = 1
+			newProducts = append(newProducts, product)
+		}
+		idRows.Close()
+		if rowsErr := idRows.Err(); rowsErr != nil {
+			return rowsErr
+		}
+
+		copyRows := make([][]interface{}, len(newProducts))
+		for index, product := range newProducts {
+			copyRows[index] = []interface{}{product.Id, product.Name, product.Price.Amount.String(), product.Discount.String(), product.Price.Currency, product.StoreId}
+		}
+		columns := []string{"id", "name", "price", "discount", "currency", "store_id"}
+		_, copyErr := tx.CopyFrom(ctx, pgx.Identifier{"products"}, columns, pgx.CopyFromRows(copyRows))
+		return copyErr
+	})
+	if isForeignKeyViolation(txErr) {
+		message := "Store not found for one of the products"
+		return nil, domainerror.Validation(domain.InvalidProductCode, message, domainerror.FieldError{Field: "store_id", Message: message})
+	}
+	if isRowDataViolation(txErr) {
+		return nil, domainerror.Validation(domain.InvalidProductCode, "One of the products violates a storage constraint: "+postgresErrorMessage(txErr))
+	}
+	if txErr != nil {
+		productRepository.logger.ErrorContext(ctx, "Error occurred copying products", logging.ErrorKey, txErr)
+		return nil, domainerror.Internal(domain.ProductStorageCode, "Error occurred inserting products", txErr)
+	}
+
+	productRepository.logger.InfoContext(ctx, "Added products", logging.CountKey, len(newProducts))
+	return newProducts, nil
+}
+
+func (productRepository *ProductRepository) GetById(ctx context.Context, productId int64) (domain.Product, error) {
+	ctx, cancel := productRepository.queryTimeouts.ForRead(ctx)
+	defer cancel()
+	selectQuery := `SELECT ` + productColumns + productsFromClause + ` WHERE p.id = $1 AND p.deleted_at IS NULL`
+	productRow := connectionFrom(ctx, productRepository.dbPool).QueryRow(ctx, selectQuery, productId)
 	return extractProduct(productId, productRow)
 }
 
-func (productRepository *ProductRepository) DeleteById(productId int64) error {
-	ctx := context.Background()
-	_, productGetErr := productRepository.GetById(productId)
+// DeleteById soft deletes the product; it stays restorable until PurgeDeleted removes it. An expectedVersion
+// of 0 deletes whatever version is current.
+func (productRepository *ProductRepository) DeleteById(ctx context.Context, productId int64, expectedVersion int64) error {
+	ctx, cancel := productRepository.queryTimeouts.ForWrite(ctx)
+	defer cancel()
 
-	if productGetErr != nil {
-		return errors.New(fmt.Sprintf("Product not found"))
-	}
+	txErr := connectionFrom(ctx, productRepository.dbPool).BeginFunc(ctx, func(tx pgx.Tx) error {
+		currentVersion, lockErr := lockProductVersion(ctx, tx, productId)
+		if lockErr != nil {
+			return lockErr
+		}
+		if expectedVersion != 0 && currentVersion != expectedVersion {
+			return domain.ProductVersionConflict(productId, expectedVersion)
+		}
 
-	deleteSQL := `DELETE FROM products WHERE id = $1`
-	_, err := productRepository.dbPool.Exec(ctx, deleteSQL, productId)
-	if err != nil {
-		log.Error("Error occurred deleting product %v", err)
-		return errors.New(fmt.Sprintf("Error occurred deleting product with id %d", productId))
+		deleteSQL := `UPDATE products SET deleted_at = now(), version = version + 1 WHERE id = $1 AND version = $2`
+		_, deleteErr := tx.Exec(ctx, deleteSQL, productId, currentVersion)
+		return deleteErr
+	})
+
+	var domainError *domainerror.Error
+	if errors.As(txErr, &domainError) {
+		return domainError
+	}
+	if txErr != nil {
+		productRepository.logger.ErrorContext(ctx, "Error occurred deleting product", logging.ProductIdKey, productId, logging.ErrorKey, txErr)
+		return domainerror.Internal(domain.ProductStorageCode, fmt.Sprintf("Error occurred deleting product with id %d", productId), txErr)
 	}
 
-	log.Info(fmt.Sprintf("Deleted product %d", productId))
+	productRepository.logger.InfoContext(ctx, "Deleted product", logging.ProductIdKey, productId)
 	return nil
 }
 
-func (productRepository *ProductRepository) UpdatePrice(productId int64, price float32) error {
-	ctx := context.Background()
-	_, productGetErr := productRepository.GetById(productId)
-	if productGetErr != nil {
-		return errors.New(fmt.Sprintf("Product not found with id %d", productId))
+// Restore clears deleted_at; restoring a product that is not deleted returns it unchanged.
+func (productRepository *ProductRepository) Restore(ctx context.Context, productId int64) (domain.Product, error) {
+	ctx, cancel := productRepository.queryTimeouts.ForWrite(ctx)
+	defer cancel()
+
+	restoreSQL := `WITH p AS (UPDATE products SET deleted_at = NULL,
+		version = CASE WHEN deleted_at IS NULL THEN version ELSE version + 1 END WHERE id = $1 returning *) SELECT ` +
+		productColumns + ` FROM p JOIN stores s ON s.id = p.store_id`
+	restoredProduct, err := extractProduct(productId, connectionFrom(ctx, productRepository.dbPool).QueryRow(ctx, restoreSQL, productId))
+	if err != nil {
+		return domain.Product{}, err
 	}
 
-	updateSQL := `UPDATE products SET price = $2 WHERE id = $1`
-	_, err := productRepository.dbPool.Exec(ctx, updateSQL, productId, price)
+	productRepository.logger.InfoContext(ctx, "Restored product", logging.ProductIdKey, productId)
+	return restoredProduct, nil
+}
+
+// PurgeDeleted hard deletes the products soft deleted before deletedBefore, together with their stock levels,
+// category links and product promotions, and returns how many products were removed. Their price history is kept
+// for auditing.
+func (productRepository *ProductRepository) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	ctx, cancel := productRepository.queryTimeouts.ForWrite(ctx)
+	defer cancel()
+
+	purgeSQL := `DELETE FROM products WHERE deleted_at < $1`
+	commandTag, err := connectionFrom(ctx, productRepository.dbPool).Exec(ctx, purgeSQL, deletedBefore)
 	if err != nil {
-		log.Error("Error occurred updating product %v", err)
-		return err
+		productRepository.logger.ErrorContext(ctx, "Error occurred purging deleted products", logging.ErrorKey, err)
+		return 0, domainerror.Internal(domain.ProductStorageCode, "Error occurred purging deleted products", err)
+	}
+
+	if commandTag.RowsAffected() > 0 {
+		productRepository.logger.InfoContext(ctx, "Purged deleted products", logging.CountKey, commandTag.RowsAffected())
+	}
+	return commandTag.RowsAffected(), nil
+}
+
+func (productRepository *ProductRepository) UpdatePrice(ctx context.Context, productId int64, price domain.Money) error {
+	return productRepository.Update(ctx, productId, 0, domain.ProductChanges{Price: &price})
+}
+
+// Update locks the product row and, when the price or discount changes, appends the old and new values to
+// price_history in the same transaction. An expectedVersion of 0 updates whatever version is current.
+func (productRepository *ProductRepository) Update(ctx context.Context, productId int64, expectedVersion int64, changes domain.ProductChanges) error {
+	ctx, cancel := productRepository.queryTimeouts.ForWrite(ctx)
+	defer cancel()
+
+	txErr := connectionFrom(ctx, productRepository.dbPool).BeginFunc(ctx, func(tx pgx.Tx) error {
+		lockSQL := `SELECT ` + productColumns + productsFromClause + ` WHERE p.id = $1 AND p.deleted_at IS NULL FOR UPDATE OF p`
+		currentProduct, getErr := extractProduct(productId, tx.QueryRow(ctx, lockSQL, productId))
+		if getErr != nil {
+			return getErr
+		}
+		if expectedVersion != 0 && currentProduct.Version != expectedVersion {
+			return domain.ProductVersionConflict(productId, expectedVersion)
+		}
+
+		updateSQL, args := buildUpdateProductQuery(productId, currentProduct.Version, changes)
+		commandTag, updateErr := tx.Exec(ctx, updateSQL, args...)
+		if updateErr != nil {
+			return updateErr
+		}
+		if commandTag.RowsAffected() == 0 {
+			return domain.ProductVersionConflict(productId, currentProduct.Version)
+		}
+
+		updatedProduct := currentProduct.Apply(changes)
+		if updatedProduct.Price == currentProduct.Price && updatedProduct.Discount == currentProduct.Discount {
+			return nil
+		}
+		return insertPriceChange(ctx, tx, newPriceChange(ctx, currentProduct, updatedProduct))
+	})
+
+	var domainError *domainerror.Error
+	if errors.As(txErr, &domainError) {
+		return domainError
+	}
+	if isForeignKeyViolation(txErr) && changes.StoreId != nil {
+		return storeNotFoundForProductError(*changes.StoreId)
+	}
+	if txErr != nil {
+		productRepository.logger.ErrorContext(ctx, "Error occurred updating product", logging.ProductIdKey, productId, logging.ErrorKey, txErr)
+		return domainerror.Internal(domain.ProductStorageCode, fmt.Sprintf("Error occurred updating product with id %d", productId), txErr)
 	}
 
-	log.Info(fmt.Sprintf("Updated product %d", productId))
+	productRepository.logger.InfoContext(ctx, "Updated product", logging.ProductIdKey, productId)
 	return nil
 }
 
-func extractsAllProducts(productRows pgx.Rows) []domain.Product {
+// extractsAllProducts fails on the first row that cannot be read, so a page is never silently shorter than its
+// total count suggests.
+func (productRepository *ProductRepository) extractsAllProducts(ctx context.Context, productRows pgx.Rows) ([]domain.Product, error) {
 	var products []domain.Product
-	var id int64
-	var name string
-	var price float32
-	var discount float32
-	var store string
 
+	defer productRows.Close()
 	for productRows.Next() {
-		productRows.Scan(&id, &name, &price, &discount, &store)
-		product := domain.Product{Id: id, Name: name, Price: price, Discount: discount, Store: store}
+		product, scanErr := scanProduct(productRows)
+		if scanErr != nil {
+			productRepository.logger.ErrorContext(ctx, "Error occurred scanning product", logging.ErrorKey, scanErr)
+			return nil, domainerror.Internal(domain.ProductStorageCode, "Error occurred getting products", scanErr)
+		}
 		products = append(products, product)
 	}
+	if rowsErr := productRows.Err(); rowsErr != nil {
+		productRepository.logger.ErrorContext(ctx, "Error occurred getting products", logging.ErrorKey, rowsErr)
+		return nil, domainerror.Internal(domain.ProductStorageCode, "Error occurred getting products", rowsErr)
+	}
 
-	return products
+	return products, nil
 }
 
-func extractProduct(productId int64, productRow pgx.Row) (domain.Product, error) {
-
+// scanProduct reads the productColumns of a row, followed by any extra columns into extraDestinations.
+func scanProduct(productRow pgx.Row, extraDestinations ...interface{}) (domain.Product, error) {
 	var id int64
 	var name string
-	var price float32
-	var discount float32
+	var price string
+	var discount string
+	var currency string
+	var storeId int64
 	var store string
-	scanErr := productRow.Scan(&id, &name, &price, &discount, &store)
+	var version int64
+	var deletedAt *time.Time
+	destinations := append([]interface{}{&id, &name, &price, &discount, &currency, &storeId, &store, &version, &deletedAt}, extraDestinations...)
+	scanErr := productRow.Scan(destinations...)
+	if scanErr != nil {
+		return domain.Product{}, scanErr
+	}
 
-	if scanErr != nil && scanErr.Error() == common.NOT_FOUND {
-		return domain.Product{}, errors.New(fmt.Sprintf("Product not found with id %d", productId))
+	priceAmount, priceErr := domain.ParseDecimal(price)
+	if priceErr != nil {
+		return domain.Product{}, fmt.Errorf("price %s %w", price, priceErr)
+	}
+	discountPercent, discountErr := domain.ParseDecimal(discount)
+	if discountErr != nil {
+		return domain.Product{}, fmt.Errorf("discount %s %w", discount, discountErr)
+	}
+
+	return domain.Product{
+		Id:        id,
+		Name:      name,
+		Price:     domain.Money{Amount: priceAmount, Currency: currency},
+		Discount:  discountPercent,
+		StoreId:   storeId,
+		Store:     store,
+		Version:   version,
+		DeletedAt: deletedAt,
+	}, nil
+}
+
+func extractProduct(productId int64, productRow pgx.Row) (domain.Product, error) {
+	product, scanErr := scanProduct(productRow)
+
+	if errors.Is(scanErr, pgx.ErrNoRows) {
+		return domain.Product{}, domainerror.NotFound(domain.ProductNotFoundCode, fmt.Sprintf("Product not found with id %d", productId))
 	}
 	if scanErr != nil {
-		return domain.Product{}, errors.New(fmt.Sprintf("Error occurred when scanned product with id %d", productId))
+		return domain.Product{}, domainerror.Internal(domain.ProductStorageCode, fmt.Sprintf("Error occurred when scanned product with id %d", productId), scanErr)
 	}
 
-	product := domain.Product{Id: id, Name: name, Price: price, Discount: discount, Store: store}
 	return product, nil
 }
+
+func lockProductVersion(ctx context.Context, tx pgx.Tx, productId int64) (int64, error) {
+	var version int64
+	lockSQL := `SELECT version FROM products WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+	lockErr := tx.QueryRow(ctx, lockSQL, productId).Scan(&version)
+	if errors.Is(lockErr, pgx.ErrNoRows) {
+		return 0, domainerror.NotFound(domain.ProductNotFoundCode, fmt.Sprintf("Product not found with id %d", productId))
+	}
+	return version, lockErr
+}
+
+func storeNotFoundForProductError(storeId int64) error {
+	message := fmt.Sprintf("Store not found with id %d", storeId)
+	return domainerror.Validation(domain.InvalidProductCode, message, domainerror.FieldError{Field: "store_id", Message: message})
+}
@@ -0,0 +1,207 @@
+package persistence
+
+import (
+	"Service-schema/core/logging"
+	"Service-schema/core/postgresql"
+	"Service-schema/domain"
+	"Service-schema/domain/domainerror"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"log/slog"
+	"time"
+)
+
+const promotionColumns = "id, name, type, value::text, coalesce(currency, ''), store_id, product_id, stackable, starts_at, ends_at"
+
+type IPromotionRepository interface {
+	GetAllPromotions(ctx context.Context) ([]domain.Promotion, error)
+	GetActivePromotions(ctx context.Context, at time.Time, products []domain.Product) ([]domain.Promotion, error)
+	GetById(ctx context.Context, promotionId int64) (domain.Promotion, error)
+	Add(ctx context.Context, promotion domain.Promotion) (domain.Promotion, error)
+	Update(ctx context.Context, promotion domain.Promotion) (domain.Promotion, error)
+	DeleteById(ctx context.Context, promotionId int64) error
+}
+
+type PromotionRepository struct {
+	dbPool        *pgxpool.Pool
+	queryTimeouts postgresql.QueryTimeouts
+	logger        *slog.Logger
+}
+
+func NewPromotionRepository(dbPool *pgxpool.Pool, queryTimeouts postgresql.QueryTimeouts, logger *slog.Logger) IPromotionRepository {
+	return &PromotionRepository{
+		dbPool:        dbPool,
+		queryTimeouts: queryTimeouts,
+		logger:        logger,
+	}
+}
+
+func (promotionRepository *PromotionRepository) GetAllPromotions(ctx context.Context) ([]domain.Promotion, error) {
+	ctx, cancel := promotionRepository.queryTimeouts.ForRead(ctx)
+	defer cancel()
+
+	promotionRows, err := connectionFrom(ctx, promotionRepository.dbPool).Query(ctx, `SELECT `+promotionColumns+` FROM promotions ORDER BY id`)
+	if err != nil {
+		promotionRepository.logger.ErrorContext(ctx, "Error occurred getting all promotions", logging.ErrorKey, err)
+		return nil, domainerror.Internal(domain.PromotionStorageCode, "Error occurred getting promotions", err)
+	}
+
+	return extractAllPromotions(promotionRows)
+}
+
+// GetActivePromotions returns the promotions running at the given time whose scope may cover any of the products.
+func (promotionRepository *PromotionRepository) GetActivePromotions(ctx context.Context, at time.Time, products []domain.Product) ([]domain.Promotion, error) {
+	if len(products) == 0 {
+		return []domain.Promotion{}, nil
+	}
+
+	ctx, cancel := promotionRepository.queryTimeouts.ForRead(ctx)
+	defer cancel()
+
+	productIds := make([]int64, 0, len(products))
+	storeIds := make([]int64, 0, len(products))
+	for _, product := range products {
+		productIds = append(productIds, product.Id)
+		storeIds = append(storeIds, product.StoreId)
+	}
+
+	selectSQL := `SELECT ` + promotionColumns + ` FROM promotions
+		WHERE starts_at <= $1 AND ends_at > $1
+		AND (store_id IS NULL OR store_id = ANY($2))
+		AND (product_id IS NULL OR product_id = ANY($3))
+		ORDER BY id`
+	promotionRows, err := connectionFrom(ctx, promotionRepository.dbPool).Query(ctx, selectSQL, at, storeIds, productIds)
+	if err != nil {
+		promotionRepository.logger.ErrorContext(ctx, "Error occurred getting active promotions", logging.ErrorKey, err)
+		return nil, domainerror.Internal(domain.PromotionStorageCode, "Error occurred getting active promotions", err)
+	}
+
+	return extractAllPromotions(promotionRows)
+}
+
+func (promotionRepository *PromotionRepository) GetById(ctx context.Context, promotionId int64) (domain.Promotion, error) {
+	ctx, cancel := promotionRepository.queryTimeouts.ForRead(ctx)
+	defer cancel()
+
+	promotionRow := connectionFrom(ctx, promotionRepository.dbPool).QueryRow(ctx, `SELECT `+promotionColumns+` FROM promotions WHERE id = $1`, promotionId)
+	return extractPromotion(promotionId, promotionRow)
+}
+
+func (promotionRepository *PromotionRepository) Add(ctx context.Context, promotion domain.Promotion) (domain.Promotion, error) {
+	ctx, cancel := promotionRepository.queryTimeouts.ForWrite(ctx)
+	defer cancel()
+
+	insertSQL := `INSERT INTO promotions (name, type, value, currency, store_id, product_id, stackable, starts_at, ends_at)
+		VALUES ($1, $2, $3, nullif($4, ''), $5, $6, $7, $8, $9) RETURNING ` + promotionColumns
+	promotionRow := connectionFrom(ctx, promotionRepository.dbPool).QueryRow(ctx, insertSQL, promotionArgs(promotion)...)
+	newPromotion, err := scanPromotion(promotionRow)
+	if err != nil {
+		return domain.Promotion{}, promotionRepository.writeError(ctx, promotion, "inserting", err)
+	}
+
+	promotionRepository.logger.InfoContext(ctx, "Added promotion", logging.PromotionIdKey, newPromotion.Id)
+	return newPromotion, nil
+}
+
+func (promotionRepository *PromotionRepository) Update(ctx context.Context, promotion domain.Promotion) (domain.Promotion, error) {
+	ctx, cancel := promotionRepository.queryTimeouts.ForWrite(ctx)
+	defer cancel()
+
+	updateSQL := `UPDATE promotions SET name = $1, type = $2, value = $3, currency = nullif($4, ''), store_id = $5, product_id = $6,
+		stackable = $7, starts_at = $8, ends_at = $9 WHERE id = $10 RETURNING ` + promotionColumns
+	promotionRow := connectionFrom(ctx, promotionRepository.dbPool).QueryRow(ctx, updateSQL, append(promotionArgs(promotion), promotion.Id)...)
+	updatedPromotion, err := scanPromotion(promotionRow)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Promotion{}, domainerror.NotFound(domain.PromotionNotFoundCode, fmt.Sprintf("Promotion not found with id %d", promotion.Id))
+	}
+	if err != nil {
+		return domain.Promotion{}, promotionRepository.writeError(ctx, promotion, "updating", err)
+	}
+
+	promotionRepository.logger.InfoContext(ctx, "Updated promotion", logging.PromotionIdKey, promotion.Id)
+	return updatedPromotion, nil
+}
+
+func (promotionRepository *PromotionRepository) DeleteById(ctx context.Context, promotionId int64) error {
+	ctx, cancel := promotionRepository.queryTimeouts.ForWrite(ctx)
+	defer cancel()
+
+	commandTag, err := connectionFrom(ctx, promotionRepository.dbPool).Exec(ctx, `DELETE FROM promotions WHERE id = $1`, promotionId)
+	if err != nil {
+		promotionRepository.logger.ErrorContext(ctx, "Error occurred deleting promotion", logging.ErrorKey, err)
+		return domainerror.Internal(domain.PromotionStorageCode, fmt.Sprintf("Error occurred deleting promotion with id %d", promotionId), err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		return domainerror.NotFound(domain.PromotionNotFoundCode, fmt.Sprintf("Promotion not found with id %d", promotionId))
+	}
+
+	promotionRepository.logger.InfoContext(ctx, "Deleted promotion", logging.PromotionIdKey, promotionId)
+	return nil
+}
+
+func promotionArgs(promotion domain.Promotion) []interface{} {
+	return []interface{}{promotion.Name, string(promotion.Type), promotion.Value.String(), promotion.Currency,
+		promotion.StoreId, promotion.ProductId, promotion.Stackable, promotion.StartsAt, promotion.EndsAt}
+}
+
+func (promotionRepository *PromotionRepository) writeError(ctx context.Context, promotion domain.Promotion, action string, err error) error {
+	if isForeignKeyViolation(err) {
+		message := "Promotion store or product does not exist"
+		return domainerror.Validation(domain.InvalidPromotionCode, message, domainerror.FieldError{Field: "scope", Message: message})
+	}
+	promotionRepository.logger.ErrorContext(ctx, "Error occurred "+action+" promotion", logging.ErrorKey, err)
+	return domainerror.Internal(domain.PromotionStorageCode, fmt.Sprintf("Error occurred %s promotion %s", action, promotion.Name), err)
+}
+
+func extractAllPromotions(promotionRows pgx.Rows) ([]domain.Promotion, error) {
+	defer promotionRows.Close()
+
+	promotions := []domain.Promotion{}
+	for promotionRows.Next() {
+		promotion, scanErr := scanPromotion(promotionRows)
+		if scanErr != nil {
+			return nil, domainerror.Internal(domain.PromotionStorageCode, "Error occurred scanning promotions", scanErr)
+		}
+		promotions = append(promotions, promotion)
+	}
+	if rowsErr := promotionRows.Err(); rowsErr != nil {
+		return nil, domainerror.Internal(domain.PromotionStorageCode, "Error occurred reading promotions", rowsErr)
+	}
+
+	return promotions, nil
+}
+
+func scanPromotion(promotionRow pgx.Row) (domain.Promotion, error) {
+	var promotion domain.Promotion
+	var promotionType string
+	var value string
+	scanErr := promotionRow.Scan(&promotion.Id, &promotion.Name, &promotionType, &value, &promotion.Currency,
+		&promotion.StoreId, &promotion.ProductId, &promotion.Stackable, &promotion.StartsAt, &promotion.EndsAt)
+	if scanErr != nil {
+		return domain.Promotion{}, scanErr
+	}
+
+	promotionValue, valueErr := domain.ParseDecimal(value)
+	if valueErr != nil {
+		return domain.Promotion{}, fmt.Errorf("value %s %w", value, valueErr)
+	}
+	promotion.Type = domain.PromotionType(promotionType)
+	promotion.Value = promotionValue
+
+	return promotion, nil
+}
+
+func extractPromotion(promotionId int64, promotionRow pgx.Row) (domain.Promotion, error) {
+	promotion, scanErr := scanPromotion(promotionRow)
+	if errors.Is(scanErr, pgx.ErrNoRows) {
+		return domain.Promotion{}, domainerror.NotFound(domain.PromotionNotFoundCode, fmt.Sprintf("Promotion not found with id %d", promotionId))
+	}
+	if scanErr != nil {
+		return domain.Promotion{}, domainerror.Internal(domain.PromotionStorageCode, fmt.Sprintf("Error occurred when scanned promotion with id %d", promotionId), scanErr)
+	}
+
+	return promotion, nil
+}
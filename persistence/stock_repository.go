@@ -0,0 +1,135 @@
+package persistence
+
+import (
+	"Service-schema/core/logging"
+	"Service-schema/core/postgresql"
+	"Service-schema/domain"
+	"Service-schema/domain/domainerror"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"log/slog"
+)
+
+const stockLevelColumns = "p.id, p.store_id, coalesce(sl.on_hand, 0), coalesce(sl.reserved, 0)"
+
+type IStockRepository interface {
+	GetByProductId(ctx context.Context, productId int64) (domain.StockLevel, error)
+	GetByStoreId(ctx context.Context, storeId int64) ([]domain.StockLevel, error)
+	Update(ctx context.Context, productId int64, modify func(stockLevel domain.StockLevel) (domain.StockLevel, error)) (domain.StockLevel, error)
+}
+
+type StockRepository struct {
+	dbPool        *pgxpool.Pool
+	queryTimeouts postgresql.QueryTimeouts
+	logger        *slog.Logger
+}
+
+func NewStockRepository(dbPool *pgxpool.Pool, queryTimeouts postgresql.QueryTimeouts, logger *slog.Logger) IStockRepository {
+	return &StockRepository{
+		dbPool:        dbPool,
+		queryTimeouts: queryTimeouts,
+		logger:        logger,
+	}
+}
+
+func (stockRepository *StockRepository) GetByProductId(ctx context.Context, productId int64) (domain.StockLevel, error) {
+	ctx, cancel := stockRepository.queryTimeouts.ForRead(ctx)
+	defer cancel()
+
+	selectSQL := `SELECT ` + stockLevelColumns + ` FROM products p LEFT JOIN stock_levels sl ON sl.product_id = p.id WHERE p.id = $1 AND p.deleted_at IS NULL`
+	return extractStockLevel(productId, connectionFrom(ctx, stockRepository.dbPool).QueryRow(ctx, selectSQL, productId))
+}
+
+func (stockRepository *StockRepository) GetByStoreId(ctx context.Context, storeId int64) ([]domain.StockLevel, error) {
+	ctx, cancel := stockRepository.queryTimeouts.ForRead(ctx)
+	defer cancel()
+
+	selectSQL := `SELECT ` + stockLevelColumns + ` FROM products p LEFT JOIN stock_levels sl ON sl.product_id = p.id
+		WHERE p.store_id = $1 AND p.deleted_at IS NULL ORDER BY p.id`
+	stockRows, err := connectionFrom(ctx, stockRepository.dbPool).Query(ctx, selectSQL, storeId)
+	if err != nil {
+		stockRepository.logger.ErrorContext(ctx, "Error occurred getting stock levels", logging.ErrorKey, err)
+		return nil, domainerror.Internal(domain.StockStorageCode, fmt.Sprintf("Error occurred getting stock levels of store %d", storeId), err)
+	}
+	defer stockRows.Close()
+
+	stockLevels := []domain.StockLevel{}
+	for stockRows.Next() {
+		var stockLevel domain.StockLevel
+		if scanErr := stockRows.Scan(&stockLevel.ProductId, &stockLevel.StoreId, &stockLevel.OnHand, &stockLevel.Reserved); scanErr != nil {
+			return nil, domainerror.Internal(domain.StockStorageCode, fmt.Sprintf("Error occurred getting stock levels of store %d", storeId), scanErr)
+		}
+		stockLevels = append(stockLevels, stockLevel)
+	}
+	if rowsErr := stockRows.Err(); rowsErr != nil {
+		stockRepository.logger.ErrorContext(ctx, "Error occurred reading stock levels", logging.ErrorKey, rowsErr)
+		return nil, domainerror.Internal(domain.StockStorageCode, fmt.Sprintf("Error occurred getting stock levels of store %d", storeId), rowsErr)
+	}
+
+	return stockLevels, nil
+}
+
+// Update locks the stock row of the product, creating it on first use, so that concurrent reservations are
+// applied one after another against the latest counts. The product row is share locked as well, so it cannot be
+// soft deleted while its stock changes; a deleted product is not found.
+func (stockRepository *StockRepository) Update(ctx context.Context, productId int64, modify func(stockLevel domain.StockLevel) (domain.StockLevel, error)) (domain.StockLevel, error) {
+	ctx, cancel := stockRepository.queryTimeouts.ForWrite(ctx)
+	defer cancel()
+
+	var updatedStockLevel domain.StockLevel
+	txErr := connectionFrom(ctx, stockRepository.dbPool).BeginFunc(ctx, func(tx pgx.Tx) error {
+		insertSQL := `INSERT INTO stock_levels (product_id) SELECT id FROM products WHERE id = $1 AND deleted_at IS NULL
+			ON CONFLICT (product_id) DO NOTHING`
+		if _, insertErr := tx.Exec(ctx, insertSQL, productId); insertErr != nil {
+			return insertErr
+		}
+
+		lockSQL := `SELECT ` + stockLevelColumns + ` FROM stock_levels sl JOIN products p ON p.id = sl.product_id
+			WHERE sl.product_id = $1 AND p.deleted_at IS NULL FOR UPDATE OF sl FOR SHARE OF p`
+		currentStockLevel, getErr := extractStockLevel(productId, tx.QueryRow(ctx, lockSQL, productId))
+		if getErr != nil {
+			return getErr
+		}
+
+		stockLevel, modifyErr := modify(currentStockLevel)
+		if modifyErr != nil {
+			return modifyErr
+		}
+
+		updateSQL := `UPDATE stock_levels SET on_hand = $2, reserved = $3, updated_at = now() WHERE product_id = $1`
+		if _, updateErr := tx.Exec(ctx, updateSQL, productId, stockLevel.OnHand, stockLevel.Reserved); updateErr != nil {
+			return updateErr
+		}
+
+		updatedStockLevel = stockLevel
+		return nil
+	})
+
+	var domainError *domainerror.Error
+	if errors.As(txErr, &domainError) {
+		return domain.StockLevel{}, domainError
+	}
+	if txErr != nil {
+		stockRepository.logger.ErrorContext(ctx, "Error occurred updating stock level", logging.ErrorKey, txErr)
+		return domain.StockLevel{}, domainerror.Internal(domain.StockStorageCode, fmt.Sprintf("Error occurred updating stock of product %d", productId), txErr)
+	}
+
+	stockRepository.logger.InfoContext(ctx, "Updated stock level", logging.ProductIdKey, productId)
+	return updatedStockLevel, nil
+}
+
+func extractStockLevel(productId int64, stockRow pgx.Row) (domain.StockLevel, error) {
+	var stockLevel domain.StockLevel
+	scanErr := stockRow.Scan(&stockLevel.ProductId, &stockLevel.StoreId, &stockLevel.OnHand, &stockLevel.Reserved)
+	if errors.Is(scanErr, pgx.ErrNoRows) {
+		return domain.StockLevel{}, domainerror.NotFound(domain.ProductNotFoundCode, fmt.Sprintf("Product not found with id %d", productId))
+	}
+	if scanErr != nil {
+		return domain.StockLevel{}, domainerror.Internal(domain.StockStorageCode, fmt.Sprintf("Error occurred when scanned stock of product %d", productId), scanErr)
+	}
+
+	return stockLevel, nil
+}
@@ -0,0 +1,167 @@
+package persistence
+
+import (
+	"Service-schema/core/logging"
+	"Service-schema/core/postgresql"
+	"Service-schema/domain"
+	"Service-schema/domain/domainerror"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"log/slog"
+)
+
+type IStoreRepository interface {
+	GetAllStores(ctx context.Context) ([]domain.Store, error)
+	GetById(ctx context.Context, storeId int64) (domain.Store, error)
+	Add(ctx context.Context, store domain.Store) (domain.Store, error)
+	Update(ctx context.Context, store domain.Store) (domain.Store, error)
+	DeleteById(ctx context.Context, storeId int64) error
+}
+
+type StoreRepository struct {
+	dbPool        *pgxpool.Pool
+	queryTimeouts postgresql.QueryTimeouts
+	logger        *slog.Logger
+}
+
+func NewStoreRepository(dbPool *pgxpool.Pool, queryTimeouts postgresql.QueryTimeouts, logger *slog.Logger) IStoreRepository {
+	return &StoreRepository{
+		dbPool:        dbPool,
+		queryTimeouts: queryTimeouts,
+		logger:        logger,
+	}
+}
+
+func (storeRepository *StoreRepository) GetAllStores(ctx context.Context) ([]domain.Store, error) {
+	ctx, cancel := storeRepository.queryTimeouts.ForRead(ctx)
+	defer cancel()
+
+	storeRows, err := connectionFrom(ctx, storeRepository.dbPool).Query(ctx, `SELECT id, name FROM stores ORDER BY id`)
+	if err != nil {
+		storeRepository.logger.ErrorContext(ctx, "Error occurred getting all stores", logging.ErrorKey, err)
+		return nil, domainerror.Internal(domain.StoreStorageCode, "Error occurred getting stores", err)
+	}
+	defer storeRows.Close()
+
+	stores := []domain.Store{}
+	for storeRows.Next() {
+		var store domain.Store
+		if scanErr := storeRows.Scan(&store.Id, &store.Name); scanErr != nil {
+			storeRepository.logger.ErrorContext(ctx, "Error occurred scanning store", logging.ErrorKey, scanErr)
+			return nil, domainerror.Internal(domain.StoreStorageCode, "Error occurred getting stores", scanErr)
+		}
+		stores = append(stores, store)
+	}
+	if rowsErr := storeRows.Err(); rowsErr != nil {
+		storeRepository.logger.ErrorContext(ctx, "Error occurred getting all stores", logging.ErrorKey, rowsErr)
+		return nil, domainerror.Internal(domain.StoreStorageCode, "Error occurred getting stores", rowsErr)
+	}
+
+	return stores, nil
+}
+
+func (storeRepository *StoreRepository) GetById(ctx context.Context, storeId int64) (domain.Store, error) {
+	ctx, cancel := storeRepository.queryTimeouts.ForRead(ctx)
+	defer cancel()
+
+	var store domain.Store
+	scanErr := connectionFrom(ctx, storeRepository.dbPool).QueryRow(ctx, `SELECT id, name FROM stores WHERE id = $1`, storeId).Scan(&store.Id, &store.Name)
+	if errors.Is(scanErr, pgx.ErrNoRows) {
+		return domain.Store{}, domainerror.NotFound(domain.StoreNotFoundCode, fmt.Sprintf("Store not found with id %d", storeId))
+	}
+	if scanErr != nil {
+		return domain.Store{}, domainerror.Internal(domain.StoreStorageCode, fmt.Sprintf("Error occurred when scanned store with id %d", storeId), scanErr)
+	}
+
+	return store, nil
+}
+
+func (storeRepository *StoreRepository) Add(ctx context.Context, store domain.Store) (domain.Store, error) {
+	ctx, cancel := storeRepository.queryTimeouts.ForWrite(ctx)
+	defer cancel()
+
+	insertSQL := `INSERT INTO stores (name) VALUES ($1) RETURNING id, name`
+	var newStore domain.Store
+	err := connectionFrom(ctx, storeRepository.dbPool).QueryRow(ctx, insertSQL, store.Name).Scan(&newStore.Id, &newStore.Name)
+	if isUniqueViolation(err) {
+		return domain.Store{}, domainerror.Conflict(domain.StoreConflictCode, fmt.Sprintf("Store %s already exists", store.Name))
+	}
+	if err != nil {
+		storeRepository.logger.ErrorContext(ctx, "Error occurred inserting store", logging.ErrorKey, err)
+		return domain.Store{}, domainerror.Internal(domain.StoreStorageCode, "Error occurred inserting store", err)
+	}
+
+	storeRepository.logger.InfoContext(ctx, "Added store", logging.StoreIdKey, newStore.Id)
+	return newStore, nil
+}
+
+func (storeRepository *StoreRepository) Update(ctx context.Context, store domain.Store) (domain.Store, error) {
+	ctx, cancel := storeRepository.queryTimeouts.ForWrite(ctx)
+	defer cancel()
+
+	updateSQL := `UPDATE stores SET name = $2 WHERE id = $1 RETURNING id, name`
+	var updatedStore domain.Store
+	err := connectionFrom(ctx, storeRepository.dbPool).QueryRow(ctx, updateSQL, store.Id, store.Name).Scan(&updatedStore.Id, &updatedStore.Name)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Store{}, domainerror.NotFound(domain.StoreNotFoundCode, fmt.Sprintf("Store not found with id %d", store.Id))
+	}
+	if isUniqueViolation(err) {
+		return domain.Store{}, domainerror.Conflict(domain.StoreConflictCode, fmt.Sprintf("Store %s already exists", store.Name))
+	}
+	if err != nil {
+		storeRepository.logger.ErrorContext(ctx, "Error occurred updating store", logging.ErrorKey, err)
+		return domain.Store{}, domainerror.Internal(domain.StoreStorageCode, fmt.Sprintf("Error occurred updating store with id %d", store.Id), err)
+	}
+
+	storeRepository.logger.InfoContext(ctx, "Updated store", logging.StoreIdKey, store.Id)
+	return updatedStore, nil
+}
+
+// DeleteById refuses to delete a store that still has products. Soft-deleted products keep the store
+// referenced until the purge job removes them after the retention period, so they are reported separately.
+func (storeRepository *StoreRepository) DeleteById(ctx context.Context, storeId int64) error {
+	ctx, cancel := storeRepository.queryTimeouts.ForWrite(ctx)
+	defer cancel()
+
+	txErr := connectionFrom(ctx, storeRepository.dbPool).BeginFunc(ctx, func(tx pgx.Tx) error {
+		var lockedStoreId int64
+		lockErr := tx.QueryRow(ctx, `SELECT id FROM stores WHERE id = $1 FOR UPDATE`, storeId).Scan(&lockedStoreId)
+		if errors.Is(lockErr, pgx.ErrNoRows) {
+			return domainerror.NotFound(domain.StoreNotFoundCode, fmt.Sprintf("Store not found with id %d", storeId))
+		}
+		if lockErr != nil {
+			return lockErr
+		}
+
+		var liveProducts, deletedProducts int64
+		countSQL := `SELECT count(*) FILTER (WHERE deleted_at IS NULL), count(*) FILTER (WHERE deleted_at IS NOT NULL)
+			FROM products WHERE store_id = $1`
+		if countErr := tx.QueryRow(ctx, countSQL, storeId).Scan(&liveProducts, &deletedProducts); countErr != nil {
+			return countErr
+		}
+		if liveProducts > 0 {
+			return domainerror.Conflict(domain.StoreConflictCode, fmt.Sprintf("Store %d still has products", storeId))
+		}
+		if deletedProducts > 0 {
+			return domainerror.Conflict(domain.StoreConflictCode, fmt.Sprintf("Store %d still has deleted products awaiting purge", storeId))
+		}
+
+		_, deleteErr := tx.Exec(ctx, `DELETE FROM stores WHERE id = $1`, storeId)
+		return deleteErr
+	})
+
+	var domainError *domainerror.Error
+	if errors.As(txErr, &domainError) {
+		return domainError
+	}
+	if txErr != nil {
+		storeRepository.logger.ErrorContext(ctx, "Error occurred deleting store", logging.ErrorKey, txErr)
+		return domainerror.Internal(domain.StoreStorageCode, fmt.Sprintf("Error occurred deleting store with id %d", storeId), txErr)
+	}
+
+	storeRepository.logger.InfoContext(ctx, "Deleted store", logging.StoreIdKey, storeId)
+	return nil
+}
@@ -0,0 +1,99 @@
+package persistence
+
+import (
+	"Service-schema/core/tracing"
+	"Service-schema/domain"
+	"context"
+	"go.opentelemetry.io/otel/trace"
+	"time"
+)
+
+const productRepositoryTracerName = "Service-schema/persistence"
+
+// TracedProductRepository wraps every IProductRepository call in a span, the parent of the spans of the
+// statements it runs.
+type TracedProductRepository struct {
+	next   IProductRepository
+	tracer trace.Tracer
+}
+
+func NewTracedProductRepository(next IProductRepository, tracerProvider trace.TracerProvider) IProductRepository {
+	return &TracedProductRepository{next: next, tracer: tracerProvider.Tracer(productRepositoryTracerName)}
+}
+
+func (tracedRepository *TracedProductRepository) GetProducts(ctx context.Context, query domain.ProductQuery) (domain.ProductPage, error) {
+	ctx, span := tracedRepository.tracer.Start(ctx, "ProductRepository.GetProducts")
+	result, err := tracedRepository.next.GetProducts(ctx, query)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (tracedRepository *TracedProductRepository) ExportProducts(ctx context.Context, query domain.ProductQuery, write func(domain.Product) error) error {
+	ctx, span := tracedRepository.tracer.Start(ctx, "ProductRepository.ExportProducts")
+	err := tracedRepository.next.ExportProducts(ctx, query, write)
+	tracing.End(span, err)
+	return err
+}
+
+func (tracedRepository *TracedProductRepository) SearchProducts(ctx context.Context, query domain.ProductSearchQuery) (domain.ProductSearchResult, error) {
+	ctx, span := tracedRepository.tracer.Start(ctx, "ProductRepository.SearchProducts")
+	result, err := tracedRepository.next.SearchProducts(ctx, query)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (tracedRepository *TracedProductRepository) GetById(ctx context.Context, productId int64) (domain.Product, error) {
+	ctx, span := tracedRepository.tracer.Start(ctx, "ProductRepository.GetById", trace.WithAttributes(tracing.ProductIdKey.Int64(productId)))
+	result, err := tracedRepository.next.GetById(ctx, productId)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (tracedRepository *TracedProductRepository) Add(ctx context.Context, product domain.Product) (domain.Product, error) {
+	ctx, span := tracedRepository.tracer.Start(ctx, "ProductRepository.Add")
+	result, err := tracedRepository.next.Add(ctx, product)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (tracedRepository *TracedProductRepository) AddAll(ctx context.Context, products []domain.Product) ([]domain.Product, error) {
+	ctx, span := tracedRepository.tracer.Start(ctx, "ProductRepository.AddAll")
+	result, err := tracedRepository.next.AddAll(ctx, products)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (tracedRepository *TracedProductRepository) DeleteById(ctx context.Context, productId int64, expectedVersion int64) error {
+	ctx, span := tracedRepository.tracer.Start(ctx, "ProductRepository.DeleteById", trace.WithAttributes(tracing.ProductIdKey.Int64(productId)))
+	err := tracedRepository.next.DeleteById(ctx, productId, expectedVersion)
+	tracing.End(span, err)
+	return err
+}
+
+func (tracedRepository *TracedProductRepository) UpdatePrice(ctx context.Context, productId int64, price domain.Money) error {
+	ctx, span := tracedRepository.tracer.Start(ctx, "ProductRepository.UpdatePrice", trace.WithAttributes(tracing.ProductIdKey.Int64(productId)))
+	err := tracedRepository.next.UpdatePrice(ctx, productId, price)
+	tracing.End(span, err)
+	return err
+}
+
+func (tracedRepository *TracedProductRepository) Update(ctx context.Context, productId int64, expectedVersion int64, changes domain.ProductChanges) error {
+	ctx, span := tracedRepository.tracer.Start(ctx, "ProductRepository.Update", trace.WithAttributes(tracing.ProductIdKey.Int64(productId)))
+	err := tracedRepository.next.Update(ctx, productId, expectedVersion, changes)
+	tracing.End(span, err)
+	return err
+}
+
+func (tracedRepository *TracedProductRepository) Restore(ctx context.Context, productId int64) (domain.Product, error) {
+	ctx, span := tracedRepository.tracer.Start(ctx, "ProductRepository.Restore", trace.WithAttributes(tracing.ProductIdKey.Int64(productId)))
+	result, err := tracedRepository.next.Restore(ctx, productId)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (tracedRepository *TracedProductRepository) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	ctx, span := tracedRepository.tracer.Start(ctx, "ProductRepository.PurgeDeleted")
+	result, err := tracedRepository.next.PurgeDeleted(ctx, deletedBefore)
+	tracing.End(span, err)
+	return result, err
+}
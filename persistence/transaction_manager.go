@@ -0,0 +1,86 @@
+package persistence
+
+import (
+	"Service-schema/core/logging"
+	"Service-schema/core/postgresql"
+	"context"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"log/slog"
+	"time"
+)
+
+const serializationFailureCode = "40001"
+const deadlockDetectedCode = "40P01"
+
+const serializationRetryBackoff = 20 * time.Millisecond
+
+type transactionKey struct{}
+
+// connection is what repositories run statements on: the pool, or the transaction carried by the context.
+type connection interface {
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	BeginFunc(ctx context.Context, f func(pgx.Tx) error) error
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+}
+
+// connectionFrom returns the transaction started by WithinTransaction when ctx carries one, so that a
+// repository call joins it; BeginFunc on a transaction opens a savepoint instead of a new transaction.
+func connectionFrom(ctx context.Context, dbPool *pgxpool.Pool) connection {
+	if tx, found := ctx.Value(transactionKey{}).(pgx.Tx); found {
+		return tx
+	}
+	return dbPool
+}
+
+type ITransactionManager interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type TransactionManager struct {
+	dbPool             *pgxpool.Pool
+	transactionOptions postgresql.TransactionOptions
+	logger             *slog.Logger
+}
+
+func NewTransactionManager(dbPool *pgxpool.Pool, transactionOptions postgresql.TransactionOptions, logger *slog.Logger) ITransactionManager {
+	return &TransactionManager{
+		dbPool:             dbPool,
+		transactionOptions: transactionOptions,
+		logger:             logger,
+	}
+}
+
+// WithinTransaction runs fn in a transaction at the configured isolation level that every repository call made
+// with the ctx passed to fn joins. It commits when fn returns nil and rolls back otherwise. A transaction that
+// fails to serialize or deadlocks is retried from the start up to the configured number of times, so fn must
+// be safe to run again. Nested calls join the outer transaction and leave retrying to it.
+func (transactionManager *TransactionManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, found := ctx.Value(transactionKey{}).(pgx.Tx); found {
+		return fn(ctx)
+	}
+
+	txOptions := pgx.TxOptions{IsoLevel: pgx.TxIsoLevel(transactionManager.transactionOptions.IsolationLevel)}
+	for attempt := 0; ; attempt++ {
+		txErr := transactionManager.dbPool.BeginTxFunc(ctx, txOptions, func(tx pgx.Tx) error {
+			return fn(context.WithValue(ctx, transactionKey{}, tx))
+		})
+		if !isRetryableTransactionError(txErr) || attempt >= transactionManager.transactionOptions.MaxRetries {
+			return txErr
+		}
+
+		transactionManager.logger.WarnContext(ctx, "Retrying transaction after serialization failure", logging.AttemptKey, attempt+1, logging.ErrorKey, txErr)
+		select {
+		case <-ctx.Done():
+			return txErr
+		case <-time.After(time.Duration(attempt+1) * serializationRetryBackoff):
+		}
+	}
+}
+
+func isRetryableTransactionError(err error) bool {
+	return hasPostgresErrorCode(err, serializationFailureCode) || hasPostgresErrorCode(err, deadlockDetectedCode)
+}
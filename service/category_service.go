@@ -0,0 +1,107 @@
+package service
+
+import (
+	"Service-schema/domain"
+	"Service-schema/domain/domainerror"
+	"Service-schema/persistence"
+	"Service-schema/service/dto"
+	"context"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+type ICategoryService interface {
+	Add(ctx context.Context, createCategoryRequestDto dto.CreateCategoryRequestDto) (domain.Category, error)
+	Update(ctx context.Context, updateCategoryRequestDto dto.UpdateCategoryRequestDto) (domain.Category, error)
+	Delete(ctx context.Context, categoryId int64) error
+	GetById(ctx context.Context, categoryId int64) (domain.Category, error)
+	GetCategoryTree(ctx context.Context) ([]domain.CategoryTree, error)
+	GetProductCategories(ctx context.Context, productId int64) ([]domain.Category, error)
+	SetProductCategories(ctx context.Context, setProductCategoriesRequestDto dto.SetProductCategoriesRequestDto) ([]domain.Category, error)
+}
+
+type CategoryService struct {
+	categoryRepository persistence.ICategoryRepository
+}
+
+func NewCategoryService(categoryRepository persistence.ICategoryRepository) ICategoryService {
+	return &CategoryService{
+		categoryRepository: categoryRepository,
+	}
+}
+
+func (categoryService *CategoryService) Add(ctx context.Context, createCategoryRequestDto dto.CreateCategoryRequestDto) (domain.Category, error) {
+	name, validationErr := validateCategoryName(createCategoryRequestDto.Name)
+	if validationErr != nil {
+		return domain.Category{}, validationErr
+	}
+
+	return categoryService.categoryRepository.Add(ctx, domain.Category{Name: name, ParentId: createCategoryRequestDto.ParentId})
+}
+
+func (categoryService *CategoryService) Update(ctx context.Context, updateCategoryRequestDto dto.UpdateCategoryRequestDto) (domain.Category, error) {
+	name, validationErr := validateCategoryName(updateCategoryRequestDto.Name)
+	if validationErr != nil {
+		return domain.Category{}, validationErr
+	}
+
+	if updateCategoryRequestDto.ParentId != nil && *updateCategoryRequestDto.ParentId == updateCategoryRequestDto.Id {
+		return domain.Category{}, invalidCategoryError("parent_id", "Category cannot be its own parent")
+	}
+
+	return categoryService.categoryRepository.Update(ctx, domain.Category{
+		Id:       updateCategoryRequestDto.Id,
+		Name:     name,
+		ParentId: updateCategoryRequestDto.ParentId,
+	})
+}
+
+func (categoryService *CategoryService) Delete(ctx context.Context, categoryId int64) error {
+	return categoryService.categoryRepository.DeleteById(ctx, categoryId)
+}
+
+func (categoryService *CategoryService) GetById(ctx context.Context, categoryId int64) (domain.Category, error) {
+	return categoryService.categoryRepository.GetById(ctx, categoryId)
+}
+
+func (categoryService *CategoryService) GetCategoryTree(ctx context.Context) ([]domain.CategoryTree, error) {
+	categories, err := categoryService.categoryRepository.GetAllCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.NewCategoryTree(categories), nil
+}
+
+func (categoryService *CategoryService) GetProductCategories(ctx context.Context, productId int64) ([]domain.Category, error) {
+	return categoryService.categoryRepository.GetProductCategories(ctx, productId)
+}
+
+func (categoryService *CategoryService) SetProductCategories(ctx context.Context, setProductCategoriesRequestDto dto.SetProductCategoriesRequestDto) ([]domain.Category, error) {
+	categoryIds := slices.Clone(setProductCategoriesRequestDto.CategoryIds)
+	slices.Sort(categoryIds)
+	categoryIds = slices.Compact(categoryIds)
+
+	setErr := categoryService.categoryRepository.SetProductCategories(ctx, setProductCategoriesRequestDto.ProductId, categoryIds)
+	if setErr != nil {
+		return nil, setErr
+	}
+
+	return categoryService.categoryRepository.GetProductCategories(ctx, setProductCategoriesRequestDto.ProductId)
+}
+
+func validateCategoryName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", invalidCategoryError("name", "Name must be specified")
+	}
+	if utf8.RuneCountInString(name) > 255 {
+		return "", invalidCategoryError("name", "Name must not be longer than 255 characters")
+	}
+	return name, nil
+}
+
+func invalidCategoryError(field string, message string) error {
+	return domainerror.Validation(domain.InvalidCategoryCode, message, domainerror.FieldError{Field: field, Message: message})
+}
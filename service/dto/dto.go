@@ -1,13 +1,99 @@
 package dto
 
+import (
+	"Service-schema/domain"
+	"iter"
+	"time"
+)
+
 type CreateProductRequestDto struct {
 	Name     string
-	Price    float32
-	Discount float32
-	Store    string
+	Price    domain.Decimal
+	Currency string
+	Discount domain.Decimal
+	StoreId  int64
 }
 
 type UpdateProductRequestDto struct {
-	Id    int64
-	Price float32
+	Id              int64
+	Price           domain.Decimal
+	ExpectedVersion int64
+}
+
+type ReplaceProductRequestDto struct {
+	Id       int64
+	Name     string
+	Price    domain.Decimal
+	Currency string
+	Discount domain.Decimal
+	StoreId  int64
+
+	ExpectedVersion int64
+}
+
+type PatchProductRequestDto struct {
+	Id       int64
+	Name     *string
+	Price    *domain.Decimal
+	Currency *string
+	Discount *domain.Decimal
+	StoreId  *int64
+
+	ExpectedVersion int64
+}
+
+type CreateStoreRequestDto struct {
+	Name string
+}
+
+type UpdateStoreRequestDto struct {
+	Id   int64
+	Name string
+}
+
+type CreateCategoryRequestDto struct {
+	Name     string
+	ParentId *int64
+}
+
+type UpdateCategoryRequestDto struct {
+	Id       int64
+	Name     string
+	ParentId *int64
+}
+
+type SetProductCategoriesRequestDto struct {
+	ProductId   int64
+	CategoryIds []int64
+}
+
+type StockOperationRequestDto struct {
+	ProductId int64
+	Quantity  int64
+}
+
+type PromotionRequestDto struct {
+	Id        int64
+	Name      string
+	Type      domain.PromotionType
+	Value     domain.Decimal
+	Currency  string
+	StoreId   *int64
+	ProductId *int64
+	Stackable bool
+	StartsAt  time.Time
+	EndsAt    time.Time
+}
+
+// ProductImportRowDto is one data row of an import stream; ParseErr is set instead of Product when the row
+// could not be decoded.
+type ProductImportRowDto struct {
+	Row      int
+	Product  CreateProductRequestDto
+	ParseErr error
+}
+
+type ProductImportRequestDto struct {
+	Mode domain.ImportMode
+	Rows iter.Seq[ProductImportRowDto]
 }
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"Service-schema/core/metrics"
+	"Service-schema/domain"
+	"Service-schema/service/dto"
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"time"
+)
+
+// InstrumentedProductService counts IProductService calls by method and outcome, and records their latency per
+// method.
+type InstrumentedProductService struct {
+	next         IProductService
+	calls        *prometheus.CounterVec
+	callDuration *prometheus.HistogramVec
+}
+
+func NewInstrumentedProductService(next IProductService, registerer prometheus.Registerer) IProductService {
+	instrumentedService := &InstrumentedProductService{
+		next: next,
+		calls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "product_service_calls_total",
+			Help: "Number of product service calls.",
+		}, []string{"method", "outcome"}),
+		callDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "product_service_call_duration_seconds",
+			Help:    "Latency of product service calls.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method"}),
+	}
+	registerer.MustRegister(instrumentedService.calls, instrumentedService.callDuration)
+	return instrumentedService
+}
+
+func (instrumentedService *InstrumentedProductService) Add(ctx context.Context, createProductRequestDto dto.CreateProductRequestDto) (domain.Product, error) {
+	start := time.Now()
+	result, err := instrumentedService.next.Add(ctx, createProductRequestDto)
+	instrumentedService.observe("Add", start, err)
+	return result, err
+}
+
+func (instrumentedService *InstrumentedProductService) Delete(ctx context.Context, productId int64, expectedVersion int64) error {
+	start := time.Now()
+	err := instrumentedService.next.Delete(ctx, productId, expectedVersion)
+	instrumentedService.observe("Delete", start, err)
+	return err
+}
+
+func (instrumentedService *InstrumentedProductService) UpdatePrice(ctx context.Context, updateProductRequestDto dto.UpdateProductRequestDto) error {
+	start := time.Now()
+	err := instrumentedService.next.UpdatePrice(ctx, updateProductRequestDto)
+	instrumentedService.observe("UpdatePrice", start, err)
+	return err
+}
+
+func (instrumentedService *InstrumentedProductService) Replace(ctx context.Context, replaceProductRequestDto dto.ReplaceProductRequestDto) (domain.Product, error) {
+	start := time.Now()
+	result, err := instrumentedService.next.Replace(ctx, replaceProductRequestDto)
+	instrumentedService.observe("Replace", start, err)
+	return result, err
+}
+
+func (instrumentedService *InstrumentedProductService) Patch(ctx context.Context, patchProductRequestDto dto.PatchProductRequestDto) (domain.Product, error) {
+	start := time.Now()
+	result, err := instrumentedService.next.Patch(ctx, patchProductRequestDto)
+	instrumentedService.observe("Patch", start, err)
+	return result, err
+}
+
+func (instrumentedService *InstrumentedProductService) GetById(ctx context.Context, productId int64) (domain.Product, error) {
+	start := time.Now()
+	result, err := instrumentedService.next.GetById(ctx, productId)
+	instrumentedService.observe("GetById", start, err)
+	return result, err
+}
+
+func (instrumentedService *InstrumentedProductService) GetByIdAt(ctx context.Context, productId int64, at time.Time) (domain.Product, error) {
+	start := time.Now()
+	result, err := instrumentedService.next.GetByIdAt(ctx, productId, at)
+	instrumentedService.observe("GetByIdAt", start, err)
+	return result, err
+}
+
+func (instrumentedService *InstrumentedProductService) GetProducts(ctx context.Context, query domain.ProductQuery) (domain.ProductPage, error) {
+	start := time.Now()
+	result, err := instrumentedService.next.GetProducts(ctx, query)
+	instrumentedService.observe("GetProducts", start, err)
+	return result, err
+}
+
+func (instrumentedService *InstrumentedProductService) ExportProducts(ctx context.Context, query domain.ProductQuery, write func(domain.Product) error) error {
+	start := time.Now()
+	err := instrumentedService.next.ExportProducts(ctx, query, write)
+	instrumentedService.observe("ExportProducts", start, err)
+	return err
+}
+
+func (instrumentedService *InstrumentedProductService) SearchProducts(ctx context.Context, query domain.ProductSearchQuery) (domain.ProductSearchResult, error) {
+	start := time.Now()
+	result, err := instrumentedService.next.SearchProducts(ctx, query)
+	instrumentedService.observe("SearchProducts", start, err)
+	return result, err
+}
+
+func (instrumentedService *InstrumentedProductService) Restore(ctx context.Context, productId int64) (domain.Product, error) {
+	start := time.Now()
+	result, err := instrumentedService.next.Restore(ctx, productId)
+	instrumentedService.observe("Restore", start, err)
+	return result, err
+}
+
+func (instrumentedService *InstrumentedProductService) observe(method string, start time.Time, err error) {
+	instrumentedService.callDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+	instrumentedService.calls.WithLabelValues(method, metrics.Outcome(err)).Inc()
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"Service-schema/domain"
+	"Service-schema/domain/domainerror"
+	"Service-schema/persistence"
+	"context"
+)
+
+type IPriceHistoryService interface {
+	GetPriceHistory(ctx context.Context, query domain.PriceHistoryQuery) ([]domain.PriceChange, error)
+}
+
+type PriceHistoryService struct {
+	priceHistoryRepository persistence.IPriceHistoryRepository
+	productRepository      persistence.IProductRepository
+}
+
+func NewPriceHistoryService(priceHistoryRepository persistence.IPriceHistoryRepository, productRepository persistence.IProductRepository) IPriceHistoryService {
+	return &PriceHistoryService{
+		priceHistoryRepository: priceHistoryRepository,
+		productRepository:      productRepository,
+	}
+}
+
+func (priceHistoryService *PriceHistoryService) GetPriceHistory(ctx context.Context, query domain.PriceHistoryQuery) ([]domain.PriceChange, error) {
+	if query.From != nil && query.To != nil && !query.From.Before(*query.To) {
+		message := "From must be before to"
+		return nil, domainerror.Validation(domain.InvalidPriceHistoryQueryCode, message, domainerror.FieldError{Field: "from", Message: message})
+	}
+
+	// History outlives deleted and purged products, so only the history of live products needs the product to exist.
+	if !query.IncludeDeleted {
+		if _, getErr := priceHistoryService.productRepository.GetById(ctx, query.ProductId); getErr != nil {
+			return nil, getErr
+		}
+	}
+
+	return priceHistoryService.priceHistoryRepository.GetPriceHistory(ctx, query)
+}
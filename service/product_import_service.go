@@ -0,0 +1,215 @@
+package service
+
+import (
+	"Service-schema/domain"
+	"Service-schema/domain/domainerror"
+	"Service-schema/persistence"
+	"Service-schema/service/dto"
+	"context"
+	"errors"
+)
+
+const productImportBatchSize = 500
+
+var errProductImportAbandoned = errors.New("product import abandoned")
+
+type IProductImportService interface {
+	Import(ctx context.Context, productImportRequestDto dto.ProductImportRequestDto) (domain.ProductImportReport, error)
+}
+
+type ProductImportService struct {
+	productRepository  persistence.IProductRepository
+	storeRepository    persistence.IStoreRepository
+	transactionManager persistence.ITransactionManager
+}
+
+func NewProductImportService(productRepository persistence.IProductRepository, storeRepository persistence.IStoreRepository, transactionManager persistence.ITransactionManager) IProductImportService {
+	return &ProductImportService{
+		productRepository:  productRepository,
+		storeRepository:    storeRepository,
+		transactionManager: transactionManager,
+	}
+}
+
+// Import validates every row with the same rules as Add and inserts the valid ones in batches. An
+// all-or-nothing import runs in one transaction that is rolled back as soon as the report has a failed row; the
+// rows are streamed once, so a transaction that fails to serialize is reported as a conflict instead of retried.
+func (productImportService *ProductImportService) Import(ctx context.Context, productImportRequestDto dto.ProductImportRequestDto) (domain.ProductImportReport, error) {
+	if !productImportRequestDto.Mode.IsValid() {
+		message := "Mode must be all_or_nothing or best_effort"
+		return domain.ProductImportReport{}, domainerror.Validation(domain.InvalidProductImportCode, message, domainerror.FieldError{Field: "mode", Message: message})
+	}
+
+	if productImportRequestDto.Mode == domain.BestEffortImport {
+		return productImportService.importRows(ctx, productImportRequestDto)
+	}
+
+	var report domain.ProductImportReport
+	attempted := false
+	txErr := productImportService.transactionManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if attempted {
+			return domainerror.Conflict(domain.ProductImportConflictCode, "Import conflicted with a concurrent write and was rolled back")
+		}
+		attempted = true
+
+		var importErr error
+		report, importErr = productImportService.importRows(ctx, productImportRequestDto)
+		if importErr != nil {
+			return importErr
+		}
+		if report.Failed > 0 {
+			return errProductImportAbandoned
+		}
+		return nil
+	})
+	if errors.Is(txErr, errProductImportAbandoned) {
+		report.RollBack()
+		return report, nil
+	}
+	if txErr != nil {
+		return domain.ProductImportReport{}, txErr
+	}
+
+	return report, nil
+}
+
+func (productImportService *ProductImportService) importRows(ctx context.Context, productImportRequestDto dto.ProductImportRequestDto) (domain.ProductImportReport, error) {
+	productImport := &productImport{
+		productImportService: productImportService,
+		report:               domain.ProductImportReport{Mode: productImportRequestDto.Mode, Rows: []domain.ProductImportRowResult{}},
+		stores:               map[int64]domain.Store{},
+		storeErrs:            map[int64]error{},
+	}
+
+	for row := range productImportRequestDto.Rows {
+		if addErr := productImport.add(ctx, row); addErr != nil {
+			return domain.ProductImportReport{}, addErr
+		}
+	}
+	if flushErr := productImport.flush(ctx); flushErr != nil {
+		return domain.ProductImportReport{}, flushErr
+	}
+
+	return productImport.report, nil
+}
+
+// productImport accumulates the report of one import and the valid rows not yet inserted.
+type productImport struct {
+	productImportService *ProductImportService
+	report               domain.ProductImportReport
+	batch                []domain.Product
+	batchRows            []int
+	stores               map[int64]domain.Store
+	storeErrs            map[int64]error
+}
+
+func (productImport *productImport) add(ctx context.Context, row dto.ProductImportRowDto) error {
+	product, rowErr := productImport.validate(ctx, row)
+	if rowErr != nil {
+		var domainError *domainerror.Error
+		if errors.As(rowErr, &domainError) && !errors.Is(domainError, domainerror.ErrValidation) {
+			return rowErr
+		}
+		productImport.report.Rows = append(productImport.report.Rows, domain.ProductImportRowResult{Row: row.Row})
+		productImport.fail(len(productImport.report.Rows)-1, domain.ImportRowInvalid, rowErr)
+		return nil
+	}
+
+	productImport.report.Rows = append(productImport.report.Rows, domain.ProductImportRowResult{Row: row.Row, Status: domain.ImportRowNotImported})
+	// An abandoned all-or-nothing import keeps validating to report every invalid row but stops inserting.
+	if productImport.report.Mode == domain.AllOrNothingImport && productImport.report.Failed > 0 {
+		return nil
+	}
+	productImport.batch = append(productImport.batch, product)
+	productImport.batchRows = append(productImport.batchRows, len(productImport.report.Rows)-1)
+	if len(productImport.batch) < productImportBatchSize {
+		return nil
+	}
+	return productImport.flush(ctx)
+}
+
+func (productImport *productImport) validate(ctx context.Context, row dto.ProductImportRowDto) (domain.Product, error) {
+	if row.ParseErr != nil {
+		return domain.Product{}, row.ParseErr
+	}
+
+	product, validationErr := validateCreateProductRequestDto(row.Product)
+	if validationErr != nil {
+		return domain.Product{}, validationErr
+	}
+
+	store, storeErr := productImport.findStore(ctx, product.StoreId)
+	if storeErr != nil {
+		return domain.Product{}, storeErr
+	}
+	product.Store = store.Name
+
+	return product, nil
+}
+
+// findStore looks every store up once per import.
+func (productImport *productImport) findStore(ctx context.Context, storeId int64) (domain.Store, error) {
+	if storeErr, found := productImport.storeErrs[storeId]; found {
+		return domain.Store{}, storeErr
+	}
+	if store, found := productImport.stores[storeId]; found {
+		return store, nil
+	}
+
+	store, storeErr := findProductStore(ctx, productImport.productImportService.storeRepository, storeId)
+	if storeErr != nil {
+		productImport.storeErrs[storeId] = storeErr
+		return domain.Store{}, storeErr
+	}
+	productImport.stores[storeId] = store
+	return store, nil
+}
+
+func (productImport *productImport) flush(ctx context.Context) error {
+	if len(productImport.batch) == 0 {
+		return nil
+	}
+	batch, batchRows := productImport.batch, productImport.batchRows
+	productImport.batch, productImport.batchRows = nil, nil
+
+	return productImport.insert(ctx, batch, batchRows)
+}
+
+// insert adds the batch in one COPY. COPY rejects the whole batch for a single offending row, so a rejected
+// batch is inserted again row by row to report only the rows that actually fail.
+func (productImport *productImport) insert(ctx context.Context, batch []domain.Product, batchRows []int) error {
+	newProducts, addErr := productImport.productImportService.productRepository.AddAll(ctx, batch)
+	if errors.Is(addErr, domainerror.ErrValidation) && len(batch) > 1 {
+		for index := range batch {
+			if insertErr := productImport.insert(ctx, batch[index:index+1], batchRows[index:index+1]); insertErr != nil {
+				return insertErr
+			}
+		}
+		return nil
+	}
+	if errors.Is(addErr, domainerror.ErrValidation) {
+		productImport.fail(batchRows[0], domain.ImportRowFailed, addErr)
+		return nil
+	}
+	if addErr != nil {
+		return addErr
+	}
+
+	for index, newProduct := range newProducts {
+		productImport.report.Rows[batchRows[index]].Status = domain.ImportRowCreated
+		productImport.report.Rows[batchRows[index]].ProductId = newProduct.Id
+	}
+	productImport.report.Created += len(newProducts)
+	return nil
+}
+
+func (productImport *productImport) fail(rowIndex int, status domain.ImportRowStatus, rowErr error) {
+	result := domain.ProductImportRowResult{Row: productImport.report.Rows[rowIndex].Row, Status: status, Message: rowErr.Error()}
+	var domainError *domainerror.Error
+	if errors.As(rowErr, &domainError) {
+		result.Fields = domainError.Fields
+	}
+
+	productImport.report.Rows[rowIndex] = result
+	productImport.report.Failed++
+}
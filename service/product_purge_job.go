@@ -0,0 +1,68 @@
+package service
+
+import (
+	"Service-schema/core/logging"
+	"Service-schema/core/purge"
+	"Service-schema/persistence"
+	"context"
+	"log/slog"
+	"time"
+)
+
+// ProductPurgeJob hard deletes products that have stayed soft deleted for longer than the retention period.
+type ProductPurgeJob struct {
+	productRepository persistence.IProductRepository
+	purgeConfig       purge.Config
+	logger            *slog.Logger
+	stop              context.CancelFunc
+	done              chan struct{}
+}
+
+func NewProductPurgeJob(productRepository persistence.IProductRepository, purgeConfig purge.Config, logger *slog.Logger) *ProductPurgeJob {
+	return &ProductPurgeJob{
+		productRepository: productRepository,
+		purgeConfig:       purgeConfig,
+		logger:            logger,
+	}
+}
+
+// Start purges once immediately and then every purge interval until Stop is called or ctx is done.
+func (productPurgeJob *ProductPurgeJob) Start(ctx context.Context) {
+	ctx, productPurgeJob.stop = context.WithCancel(ctx)
+	productPurgeJob.done = make(chan struct{})
+
+	go func() {
+		defer close(productPurgeJob.done)
+		ticker := time.NewTicker(productPurgeJob.purgeConfig.Interval)
+		defer ticker.Stop()
+		for {
+			if _, purgeErr := productPurgeJob.PurgeOnce(ctx); purgeErr != nil {
+				productPurgeJob.logger.ErrorContext(ctx, "Error occurred purging deleted products", logging.ErrorKey, purgeErr)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop cancels the running purge and waits for it to return or for ctx to expire.
+func (productPurgeJob *ProductPurgeJob) Stop(ctx context.Context) error {
+	if productPurgeJob.stop == nil {
+		return nil
+	}
+	productPurgeJob.stop()
+
+	select {
+	case <-productPurgeJob.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (productPurgeJob *ProductPurgeJob) PurgeOnce(ctx context.Context) (int64, error) {
+	return productPurgeJob.productRepository.PurgeDeleted(ctx, time.Now().Add(-productPurgeJob.purgeConfig.Retention))
+}
@@ -1,51 +1,99 @@
 package service
 
 import (
+	"Service-schema/core/logging"
 	"Service-schema/domain"
+	"Service-schema/domain/domainerror"
 	"Service-schema/persistence"
 	"Service-schema/service/dto"
+	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
 )
 
+const maxProductSearchTextLength = 200
+
+// productExportBatchSize is the number of exported products whose promotions are looked up together.
+const productExportBatchSize = 500
+
+var maximumProductDiscount = domain.NewDecimal(50)
+var minimumProductPrice = domain.NewDecimal(10)
+
 type IProductService interface {
-	Add(createProductRequestDto dto.CreateProductRequestDto) error
-	Delete(productId int64) error
-	UpdatePrice(updateProductRequestDto dto.UpdateProductRequestDto) error
-	GetById(productId int64) (domain.Product, error)
-	GetAllProducts() []domain.Product
-	GetAllProductsByStoreName(storeName string) []domain.Product
+	Add(ctx context.Context, createProductRequestDto dto.CreateProductRequestDto) (domain.Product, error)
+	Delete(ctx context.Context, productId int64, expectedVersion int64) error
+	UpdatePrice(ctx context.Context, updateProductRequestDto dto.UpdateProductRequestDto) error
+	Replace(ctx context.Context, replaceProductRequestDto dto.ReplaceProductRequestDto) (domain.Product, error)
+	Patch(ctx context.Context, patchProductRequestDto dto.PatchProductRequestDto) (domain.Product, error)
+	GetById(ctx context.Context, productId int64) (domain.Product, error)
+	GetByIdAt(ctx context.Context, productId int64, at time.Time) (domain.Product, error)
+	GetProducts(ctx context.Context, query domain.ProductQuery) (domain.ProductPage, error)
+	ExportProducts(ctx context.Context, query domain.ProductQuery, write func(domain.Product) error) error
+	SearchProducts(ctx context.Context, query domain.ProductSearchQuery) (domain.ProductSearchResult, error)
+	Restore(ctx context.Context, productId int64) (domain.Product, error)
 }
 
 type ProductService struct {
-	productRepository persistence.IProductRepository
+	productRepository   persistence.IProductRepository
+	storeRepository     persistence.IStoreRepository
+	promotionRepository persistence.IPromotionRepository
+	transactionManager  persistence.ITransactionManager
+	logger              *slog.Logger
 }
 
-func NewProductService(productRepository persistence.IProductRepository) IProductService {
+func NewProductService(productRepository persistence.IProductRepository, storeRepository persistence.IStoreRepository, promotionRepository persistence.IPromotionRepository, transactionManager persistence.ITransactionManager, logger *slog.Logger) IProductService {
 	return &ProductService{
-		productRepository: productRepository,
+		productRepository:   productRepository,
+		storeRepository:     storeRepository,
+		promotionRepository: promotionRepository,
+		transactionManager:  transactionManager,
+		logger:              logger,
 	}
 }
 
-func (productService *ProductService) Add(createProductRequestDto dto.CreateProductRequestDto) error {
-	validationErr := validateCreateProductRequestDto(createProductRequestDto)
+func (productService *ProductService) Add(ctx context.Context, createProductRequestDto dto.CreateProductRequestDto) (domain.Product, error) {
+	product, validationErr := validateCreateProductRequestDto(createProductRequestDto)
 	if validationErr != nil {
-		return validationErr
+		return domain.Product{}, validationErr
 	}
 
-	return productService.productRepository.Add(domain.Product{
-		Name:     createProductRequestDto.Name,
-		Price:    createProductRequestDto.Price,
-		Discount: createProductRequestDto.Discount,
-		Store:    createProductRequestDto.Store,
+	var newProduct domain.Product
+	txErr := productService.transactionManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		store, storeErr := findProductStore(ctx, productService.storeRepository, product.StoreId)
+		if storeErr != nil {
+			return storeErr
+		}
+		product.Store = store.Name
+
+		var addErr error
+		newProduct, addErr = productService.productRepository.Add(ctx, product)
+		return addErr
 	})
+	if txErr != nil {
+		return domain.Product{}, txErr
+	}
+
+	return productService.withPromotions(ctx, newProduct, time.Now())
 }
 
-func (productService *ProductService) Delete(productId int64) error {
-	return productService.productRepository.DeleteById(productId)
+func (productService *ProductService) Delete(ctx context.Context, productId int64, expectedVersion int64) error {
+	return productService.expectingVersion(ctx, productId, productService.productRepository.DeleteById(ctx, productId, expectedVersion), expectedVersion)
 }
 
-func (productService *ProductService) UpdatePrice(updateProductRequestDto dto.UpdateProductRequestDto) error {
+func (productService *ProductService) Restore(ctx context.Context, productId int64) (domain.Product, error) {
+	restoredProduct, restoreErr := productService.productRepository.Restore(ctx, productId)
+	if restoreErr != nil {
+		return domain.Product{}, restoreErr
+	}
+
+	return productService.withPromotions(ctx, restoredProduct, time.Now())
+}
+
+func (productService *ProductService) UpdatePrice(ctx context.Context, updateProductRequestDto dto.UpdateProductRequestDto) error {
 
 	validationErr := validateUpdateProductRequestDto(updateProductRequestDto)
 
@@ -53,49 +101,440 @@ func (productService *ProductService) UpdatePrice(updateProductRequestDto dto.Up
 		return validationErr
 	}
 
-	return productService.productRepository.UpdatePrice(updateProductRequestDto.Id, updateProductRequestDto.Price)
+	return productService.transactionManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		product, getErr := productService.productRepository.GetById(ctx, updateProductRequestDto.Id)
+		if getErr != nil {
+			return getErr
+		}
+
+		versionErr := productService.checkVersion(ctx, product, updateProductRequestDto.ExpectedVersion)
+		if versionErr != nil {
+			return versionErr
+		}
+
+		price, priceErr := validatePrice(updateProductRequestDto.Price, product.Price.Currency)
+		if priceErr != nil {
+			return priceErr
+		}
+
+		updateErr := productService.productRepository.Update(ctx, product.Id, product.Version, domain.ProductChanges{Price: &price})
+		return productService.expectingVersion(ctx, product.Id, updateErr, updateProductRequestDto.ExpectedVersion)
+	})
 }
 
-func (productService *ProductService) GetById(productId int64) (domain.Product, error) {
-	return productService.productRepository.GetById(productId)
+func (productService *ProductService) Replace(ctx context.Context, replaceProductRequestDto dto.ReplaceProductRequestDto) (domain.Product, error) {
+	return productService.update(ctx, replaceProductRequestDto.Id, replaceProductRequestDto.ExpectedVersion, func(product domain.Product) dto.CreateProductRequestDto {
+		return dto.CreateProductRequestDto{
+			Name:     replaceProductRequestDto.Name,
+			Price:    replaceProductRequestDto.Price,
+			Currency: replaceProductRequestDto.Currency,
+			Discount: replaceProductRequestDto.Discount,
+			StoreId:  replaceProductRequestDto.StoreId,
+		}
+	})
 }
 
-func (productService *ProductService) GetAllProducts() []domain.Product {
-	return productService.productRepository.GetAllProducts()
+func (productService *ProductService) Patch(ctx context.Context, patchProductRequestDto dto.PatchProductRequestDto) (domain.Product, error) {
+	return productService.update(ctx, patchProductRequestDto.Id, patchProductRequestDto.ExpectedVersion, func(product domain.Product) dto.CreateProductRequestDto {
+		patchedProduct := dto.CreateProductRequestDto{
+			Name:     product.Name,
+			Price:    product.Price.Amount,
+			Currency: product.Price.Currency,
+			Discount: product.Discount,
+			StoreId:  product.StoreId,
+		}
+		if patchProductRequestDto.Name != nil {
+			patchedProduct.Name = *patchProductRequestDto.Name
+		}
+		if patchProductRequestDto.Price != nil {
+			patchedProduct.Price = *patchProductRequestDto.Price
+		}
+		if patchProductRequestDto.Currency != nil {
+			patchedProduct.Currency = *patchProductRequestDto.Currency
+		}
+		if patchProductRequestDto.Discount != nil {
+			patchedProduct.Discount = *patchProductRequestDto.Discount
+		}
+		if patchProductRequestDto.StoreId != nil {
+			patchedProduct.StoreId = *patchProductRequestDto.StoreId
+		}
+		return patchedProduct
+	})
 }
 
-func (productService *ProductService) GetAllProductsByStoreName(storeName string) []domain.Product {
-	return productService.productRepository.GetAllProductsByStoreName(storeName)
+// update re-validates the product produced by modify with the creation rules and persists only the columns it
+// changed, reading and writing in one transaction and provided nobody else has written the product since it
+// was read.
+func (productService *ProductService) update(ctx context.Context, productId int64, expectedVersion int64, modify func(product domain.Product) dto.CreateProductRequestDto) (domain.Product, error) {
+	var updatedProduct domain.Product
+	txErr := productService.transactionManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		currentProduct, getErr := productService.productRepository.GetById(ctx, productId)
+		if getErr != nil {
+			return getErr
+		}
+
+		versionErr := productService.checkVersion(ctx, currentProduct, expectedVersion)
+		if versionErr != nil {
+			return versionErr
+		}
+
+		validatedProduct, validationErr := validateCreateProductRequestDto(modify(currentProduct))
+		if validationErr != nil {
+			return validationErr
+		}
+		validatedProduct.Id = currentProduct.Id
+		validatedProduct.Store = currentProduct.Store
+		validatedProduct.Version = currentProduct.Version + 1
+
+		changes := currentProduct.ChangesTo(validatedProduct)
+		if changes.IsEmpty() {
+			updatedProduct = currentProduct
+			return nil
+		}
+
+		if changes.StoreId != nil {
+			store, storeErr := findProductStore(ctx, productService.storeRepository, validatedProduct.StoreId)
+			if storeErr != nil {
+				return storeErr
+			}
+			validatedProduct.Store = store.Name
+		}
+
+		updateErr := productService.productRepository.Update(ctx, productId, currentProduct.Version, changes)
+		if updateErr != nil {
+			return productService.expectingVersion(ctx, productId, updateErr, expectedVersion)
+		}
+
+		updatedProduct = validatedProduct
+		return nil
+	})
+	if txErr != nil {
+		return domain.Product{}, txErr
+	}
+
+	return productService.withPromotions(ctx, updatedProduct, time.Now())
+}
+
+func (productService *ProductService) GetById(ctx context.Context, productId int64) (domain.Product, error) {
+	return productService.GetByIdAt(ctx, productId, time.Now())
 }
 
-func validateCreateProductRequestDto(createProductRequestDto dto.CreateProductRequestDto) error {
-	if createProductRequestDto.Discount > float32(50.0) {
-		return errors.New(fmt.Sprintf("Discount must be less than 50 percent"))
+// GetByIdAt returns the product with the promotions that apply at the given time, the zero time meaning now.
+func (productService *ProductService) GetByIdAt(ctx context.Context, productId int64, at time.Time) (domain.Product, error) {
+	if at.IsZero() {
+		at = time.Now()
 	}
 
-	if createProductRequestDto.Store == "" {
-		return errors.New(fmt.Sprintf("Store must be specified"))
+	product, getErr := productService.productRepository.GetById(ctx, productId)
+	if getErr != nil {
+		return domain.Product{}, getErr
 	}
 
-	if createProductRequestDto.Name == "" {
-		return errors.New(fmt.Sprintf("Name must be specified"))
+	return productService.withPromotions(ctx, product, at)
+}
+
+func (productService *ProductService) GetProducts(ctx context.Context, query domain.ProductQuery) (domain.ProductPage, error) {
+	normalizedQuery, validationErr := normalizeProductQuery(query)
+	if validationErr != nil {
+		return domain.ProductPage{}, validationErr
+	}
+
+	if normalizedQuery.StoreId != 0 {
+		if _, storeErr := productService.storeRepository.GetById(ctx, normalizedQuery.StoreId); storeErr != nil {
+			return domain.ProductPage{}, storeErr
+		}
+	}
+
+	productPage, getErr := productService.productRepository.GetProducts(ctx, normalizedQuery)
+	if getErr != nil {
+		return domain.ProductPage{}, getErr
+	}
+
+	promotions, promotionsErr := productService.promotionRepository.GetActivePromotions(ctx, normalizedQuery.At, productPage.Items)
+	if promotionsErr != nil {
+		return domain.ProductPage{}, promotionsErr
+	}
+	for index, product := range productPage.Items {
+		productPage.Items[index] = product.WithPromotionsAt(promotions, normalizedQuery.At)
+	}
+
+	return productPage, nil
+}
+
+// ExportProducts passes every product matching the filters of query to write, with its promotions applied, in
+// the order of the query. Paging does not apply to an export; products are fetched and written a batch at a
+// time, so write starts receiving products before the last one is read.
+func (productService *ProductService) ExportProducts(ctx context.Context, query domain.ProductQuery, write func(domain.Product) error) error {
+	query.Limit, query.Offset, query.Cursor = 0, 0, nil
+	normalizedQuery, validationErr := normalizeProductQuery(query)
+	if validationErr != nil {
+		return validationErr
+	}
+
+	if normalizedQuery.StoreId != 0 {
+		if _, storeErr := productService.storeRepository.GetById(ctx, normalizedQuery.StoreId); storeErr != nil {
+			return storeErr
+		}
 	}
 
-	if createProductRequestDto.Price < float32(10.0) {
-		return errors.New(fmt.Sprintf("Price must be greater than 10"))
+	batch := make([]domain.Product, 0, productExportBatchSize)
+	exportedCount := 0
+	writeBatch := func() error {
+		promotions, promotionsErr := productService.promotionRepository.GetActivePromotions(ctx, normalizedQuery.At, batch)
+		if promotionsErr != nil {
+			return promotionsErr
+		}
+		for _, product := range batch {
+			if writeErr := write(product.WithPromotionsAt(promotions, normalizedQuery.At)); writeErr != nil {
+				return writeErr
+			}
+		}
+		exportedCount += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+
+	exportErr := productService.productRepository.ExportProducts(ctx, normalizedQuery, func(product domain.Product) error {
+		batch = append(batch, product)
+		if len(batch) < productExportBatchSize {
+			return nil
+		}
+		return writeBatch()
+	})
+	if exportErr == nil && len(batch) > 0 {
+		exportErr = writeBatch()
+	}
+	if exportErr != nil {
+		return exportErr
 	}
 
+	productService.logger.InfoContext(ctx, "Exported products", logging.CountKey, exportedCount)
 	return nil
 }
 
+func (productService *ProductService) SearchProducts(ctx context.Context, query domain.ProductSearchQuery) (domain.ProductSearchResult, error) {
+	normalizedQuery, validationErr := normalizeProductSearchQuery(query)
+	if validationErr != nil {
+		return domain.ProductSearchResult{}, validationErr
+	}
+
+	if normalizedQuery.StoreId != 0 {
+		if _, storeErr := productService.storeRepository.GetById(ctx, normalizedQuery.StoreId); storeErr != nil {
+			return domain.ProductSearchResult{}, storeErr
+		}
+	}
+
+	result, searchErr := productService.productRepository.SearchProducts(ctx, normalizedQuery)
+	if searchErr != nil {
+		return domain.ProductSearchResult{}, searchErr
+	}
+
+	products := make([]domain.Product, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		products = append(products, hit.Product)
+	}
+	promotions, promotionsErr := productService.promotionRepository.GetActivePromotions(ctx, normalizedQuery.At, products)
+	if promotionsErr != nil {
+		return domain.ProductSearchResult{}, promotionsErr
+	}
+	for index, hit := range result.Hits {
+		result.Hits[index].Product = hit.Product.WithPromotionsAt(promotions, normalizedQuery.At)
+	}
+
+	return result, nil
+}
+
+func (productService *ProductService) withPromotions(ctx context.Context, product domain.Product, at time.Time) (domain.Product, error) {
+	promotions, promotionsErr := productService.promotionRepository.GetActivePromotions(ctx, at, []domain.Product{product})
+	if promotionsErr != nil {
+		return domain.Product{}, promotionsErr
+	}
+
+	return product.WithPromotionsAt(promotions, at), nil
+}
+
+// validateCreateProductRequestDto reports every invalid field of the request at once.
+func validateCreateProductRequestDto(createProductRequestDto dto.CreateProductRequestDto) (domain.Product, error) {
+	var fieldErrors []domainerror.FieldError
+	if createProductRequestDto.Discount > maximumProductDiscount {
+		fieldErrors = append(fieldErrors, domainerror.FieldError{Field: "discount", Message: "Discount must be less than 50 percent"})
+	}
+
+	if createProductRequestDto.Discount < 0 {
+		fieldErrors = append(fieldErrors, domainerror.FieldError{Field: "discount", Message: "Discount must not be negative"})
+	}
+
+	if createProductRequestDto.StoreId == 0 {
+		fieldErrors = append(fieldErrors, domainerror.FieldError{Field: "store_id", Message: "Store must be specified"})
+	}
+
+	if createProductRequestDto.Name == "" {
+		fieldErrors = append(fieldErrors, domainerror.FieldError{Field: "name", Message: "Name must be specified"})
+	}
+
+	currency := createProductRequestDto.Currency
+	if currency == "" {
+		currency = domain.DefaultCurrency
+	}
+
+	price, priceErr := validatePrice(createProductRequestDto.Price, currency)
+	var priceDomainError *domainerror.Error
+	if errors.As(priceErr, &priceDomainError) {
+		fieldErrors = append(fieldErrors, priceDomainError.Fields...)
+	} else if priceErr != nil {
+		return domain.Product{}, priceErr
+	}
+
+	if len(fieldErrors) > 0 {
+		messages := make([]string, len(fieldErrors))
+		for index, fieldError := range fieldErrors {
+			messages[index] = fieldError.Message
+		}
+		return domain.Product{}, domainerror.Validation(domain.InvalidProductCode, strings.Join(messages, "; "), fieldErrors...)
+	}
+
+	return domain.Product{
+		Name:     createProductRequestDto.Name,
+		Price:    price,
+		Discount: createProductRequestDto.Discount,
+		StoreId:  createProductRequestDto.StoreId,
+	}, nil
+}
+
+// findProductStore reports a missing store as an invalid product rather than a missing resource, since it is
+// referenced from the request body.
+func findProductStore(ctx context.Context, storeRepository persistence.IStoreRepository, storeId int64) (domain.Store, error) {
+	store, storeErr := storeRepository.GetById(ctx, storeId)
+	if errors.Is(storeErr, domainerror.ErrNotFound) {
+		return domain.Store{}, invalidProductError("store_id", fmt.Sprintf("Store not found with id %d", storeId))
+	}
+	return store, storeErr
+}
+
 func validateUpdateProductRequestDto(updateProductRequestDto dto.UpdateProductRequestDto) error {
 	if updateProductRequestDto.Id == 0 {
-		return errors.New(fmt.Sprintf("Id must be specified"))
+		return invalidProductError("id", "Id must be specified")
 	}
 
-	if updateProductRequestDto.Price < float32(10.0) {
-		return errors.New(fmt.Sprintf("Price must be greater than 10"))
+	return nil
+}
+
+func validatePrice(amount domain.Decimal, currency string) (domain.Money, error) {
+	if !domain.IsSupportedCurrency(currency) {
+		return domain.Money{}, invalidProductError("currency", "Currency must be a supported ISO 4217 code")
 	}
 
-	return nil
+	price, moneyErr := domain.NewMoney(amount, currency)
+	if moneyErr != nil {
+		return domain.Money{}, invalidProductError("price", "Price "+moneyErr.Error())
+	}
+
+	if price.LessThan(minimumProductPrice) {
+		return domain.Money{}, invalidProductError("price", "Price must be greater than 10")
+	}
+
+	return price, nil
+}
+
+func invalidProductError(field string, message string) error {
+	return domainerror.Validation(domain.InvalidProductCode, message, domainerror.FieldError{Field: field, Message: message})
+}
+
+func normalizeProductQuery(query domain.ProductQuery) (domain.ProductQuery, error) {
+	if query.SortField == "" {
+		query.SortField = domain.SortById
+	}
+	if query.SortDirection == "" {
+		query.SortDirection = domain.Ascending
+	}
+	if query.Limit == 0 {
+		query.Limit = domain.DefaultProductPageSize
+	}
+	if query.At.IsZero() {
+		query.At = time.Now()
+	}
+
+	if !query.SortField.IsValid() {
+		return query, invalidProductQueryError("sort", "Sort must be one of id, name, price, discount")
+	}
+	if !query.SortDirection.IsValid() {
+		return query, invalidProductQueryError("order", "Order must be asc or desc")
+	}
+	if query.Limit < 0 || query.Limit > domain.MaxProductPageSize {
+		return query, invalidProductQueryError("limit", fmt.Sprintf("Limit must be between 1 and %d", domain.MaxProductPageSize))
+	}
+	if query.Offset < 0 {
+		return query, invalidProductQueryError("offset", "Offset must not be negative")
+	}
+	if query.Cursor != nil && query.Offset > 0 {
+		return query, invalidProductQueryError("cursor", "Cursor and offset cannot be combined")
+	}
+	if query.Cursor != nil && (query.Cursor.SortField != query.SortField || query.Cursor.SortDirection != query.SortDirection) {
+		return query, invalidProductQueryError("cursor", "Cursor does not match the requested sort")
+	}
+	if query.MinPrice != nil && query.MaxPrice != nil && *query.MinPrice > *query.MaxPrice {
+		return query, invalidProductQueryError("min_price", "Min price must not be greater than max price")
+	}
+	if query.MinDiscount != nil && query.MaxDiscount != nil && *query.MinDiscount > *query.MaxDiscount {
+		return query, invalidProductQueryError("min_discount", "Min discount must not be greater than max discount")
+	}
+
+	return query, nil
+}
+
+func normalizeProductSearchQuery(query domain.ProductSearchQuery) (domain.ProductSearchQuery, error) {
+	query.Text = strings.TrimSpace(query.Text)
+	if query.Limit == 0 {
+		query.Limit = domain.DefaultProductPageSize
+	}
+	if query.At.IsZero() {
+		query.At = time.Now()
+	}
+
+	if query.Text == "" {
+		return query, invalidProductSearchError("q", "Search text must be specified")
+	}
+	if utf8.RuneCountInString(query.Text) > maxProductSearchTextLength {
+		return query, invalidProductSearchError("q", fmt.Sprintf("Search text must not be longer than %d characters", maxProductSearchTextLength))
+	}
+	if query.Limit < 0 || query.Limit > domain.MaxProductPageSize {
+		return query, invalidProductSearchError("limit", fmt.Sprintf("Limit must be between 1 and %d", domain.MaxProductPageSize))
+	}
+	if query.Offset < 0 {
+		return query, invalidProductSearchError("offset", "Offset must not be negative")
+	}
+
+	return query, nil
+}
+
+func invalidProductSearchError(field string, message string) error {
+	return domainerror.Validation(domain.InvalidProductSearchCode, message, domainerror.FieldError{Field: field, Message: message})
+}
+
+func invalidProductQueryError(field string, message string) error {
+	return domainerror.Validation(domain.InvalidProductQueryCode, message, domainerror.FieldError{Field: field, Message: message})
+}
+
+// checkVersion fails the precondition when the caller expects a version other than the current one; an
+// expectedVersion of 0 means the caller did not ask for a check.
+func (productService *ProductService) checkVersion(ctx context.Context, product domain.Product, expectedVersion int64) error {
+	if expectedVersion == 0 || product.Version == expectedVersion {
+		return nil
+	}
+	return productService.expectingVersion(ctx, product.Id, domain.ProductVersionConflict(product.Id, expectedVersion), expectedVersion)
+}
+
+// expectingVersion reports a version conflict as a failed precondition when the caller sent the version it
+// expected, and leaves it a conflict when the service lost a race against a concurrent writer.
+func (productService *ProductService) expectingVersion(ctx context.Context, productId int64, err error, expectedVersion int64) error {
+	var domainError *domainerror.Error
+	if !errors.As(err, &domainError) || domainError.Code != domain.ProductVersionConflictCode {
+		return err
+	}
+
+	productService.logger.InfoContext(ctx, "Rejected write to a modified product", logging.ProductIdKey, productId, "expected_version", expectedVersion)
+	if expectedVersion != 0 {
+		return domainerror.PreconditionFailed(domainError.Code, domainError.Message)
+	}
+	return err
 }
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"Service-schema/domain"
+	"Service-schema/domain/domainerror"
+	"Service-schema/persistence"
+	"Service-schema/service/dto"
+	"context"
+	"strings"
+)
+
+var maximumPromotionPercentage = domain.NewDecimal(100)
+
+type IPromotionService interface {
+	Add(ctx context.Context, promotionRequestDto dto.PromotionRequestDto) (domain.Promotion, error)
+	Update(ctx context.Context, promotionRequestDto dto.PromotionRequestDto) (domain.Promotion, error)
+	Delete(ctx context.Context, promotionId int64) error
+	GetById(ctx context.Context, promotionId int64) (domain.Promotion, error)
+	GetAllPromotions(ctx context.Context) ([]domain.Promotion, error)
+}
+
+type PromotionService struct {
+	promotionRepository persistence.IPromotionRepository
+}
+
+func NewPromotionService(promotionRepository persistence.IPromotionRepository) IPromotionService {
+	return &PromotionService{
+		promotionRepository: promotionRepository,
+	}
+}
+
+func (promotionService *PromotionService) Add(ctx context.Context, promotionRequestDto dto.PromotionRequestDto) (domain.Promotion, error) {
+	promotion, validationErr := validatePromotionRequestDto(promotionRequestDto)
+	if validationErr != nil {
+		return domain.Promotion{}, validationErr
+	}
+
+	return promotionService.promotionRepository.Add(ctx, promotion)
+}
+
+func (promotionService *PromotionService) Update(ctx context.Context, promotionRequestDto dto.PromotionRequestDto) (domain.Promotion, error) {
+	promotion, validationErr := validatePromotionRequestDto(promotionRequestDto)
+	if validationErr != nil {
+		return domain.Promotion{}, validationErr
+	}
+	promotion.Id = promotionRequestDto.Id
+
+	return promotionService.promotionRepository.Update(ctx, promotion)
+}
+
+func (promotionService *PromotionService) Delete(ctx context.Context, promotionId int64) error {
+	return promotionService.promotionRepository.DeleteById(ctx, promotionId)
+}
+
+func (promotionService *PromotionService) GetById(ctx context.Context, promotionId int64) (domain.Promotion, error) {
+	return promotionService.promotionRepository.GetById(ctx, promotionId)
+}
+
+func (promotionService *PromotionService) GetAllPromotions(ctx context.Context) ([]domain.Promotion, error) {
+	return promotionService.promotionRepository.GetAllPromotions(ctx)
+}
+
+func validatePromotionRequestDto(promotionRequestDto dto.PromotionRequestDto) (domain.Promotion, error) {
+	name := strings.TrimSpace(promotionRequestDto.Name)
+	if name == "" {
+		return domain.Promotion{}, invalidPromotionError("name", "Name must be specified")
+	}
+
+	if !promotionRequestDto.Type.IsValid() {
+		return domain.Promotion{}, invalidPromotionError("type", "Type must be percentage or fixed_amount")
+	}
+
+	if promotionRequestDto.Value <= 0 {
+		return domain.Promotion{}, invalidPromotionError("value", "Value must be greater than zero")
+	}
+
+	currency := ""
+	if promotionRequestDto.Type == domain.PercentagePromotion {
+		if promotionRequestDto.Value > maximumPromotionPercentage {
+			return domain.Promotion{}, invalidPromotionError("value", "Value must not be greater than 100 percent")
+		}
+	} else {
+		if !domain.IsSupportedCurrency(promotionRequestDto.Currency) {
+			return domain.Promotion{}, invalidPromotionError("currency", "Currency must be a supported ISO 4217 code")
+		}
+		amount, moneyErr := domain.NewMoney(promotionRequestDto.Value, promotionRequestDto.Currency)
+		if moneyErr != nil {
+			return domain.Promotion{}, invalidPromotionError("value", "Value "+moneyErr.Error())
+		}
+		currency = amount.Currency
+	}
+
+	if promotionRequestDto.StartsAt.IsZero() || promotionRequestDto.EndsAt.IsZero() {
+		return domain.Promotion{}, invalidPromotionError("starts_at", "Start and end times must be specified")
+	}
+
+	if !promotionRequestDto.EndsAt.After(promotionRequestDto.StartsAt) {
+		return domain.Promotion{}, invalidPromotionError("ends_at", "End time must be after start time")
+	}
+
+	return domain.Promotion{
+		Name:      name,
+		Type:      promotionRequestDto.Type,
+		Value:     promotionRequestDto.Value,
+		Currency:  currency,
+		StoreId:   promotionRequestDto.StoreId,
+		ProductId: promotionRequestDto.ProductId,
+		Stackable: promotionRequestDto.Stackable,
+		StartsAt:  promotionRequestDto.StartsAt,
+		EndsAt:    promotionRequestDto.EndsAt,
+	}, nil
+}
+
+func invalidPromotionError(field string, message string) error {
+	return domainerror.Validation(domain.InvalidPromotionCode, message, domainerror.FieldError{Field: field, Message: message})
+}
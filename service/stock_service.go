@@ -0,0 +1,63 @@
+package service
+
+import (
+	"Service-schema/domain"
+	"Service-schema/persistence"
+	"Service-schema/service/dto"
+	"context"
+)
+
+type IStockService interface {
+	GetProductStock(ctx context.Context, productId int64) (domain.StockLevel, error)
+	GetStoreStock(ctx context.Context, storeId int64) ([]domain.StockLevel, error)
+	Adjust(ctx context.Context, stockOperationRequestDto dto.StockOperationRequestDto) (domain.StockLevel, error)
+	Reserve(ctx context.Context, stockOperationRequestDto dto.StockOperationRequestDto) (domain.StockLevel, error)
+	Release(ctx context.Context, stockOperationRequestDto dto.StockOperationRequestDto) (domain.StockLevel, error)
+	Commit(ctx context.Context, stockOperationRequestDto dto.StockOperationRequestDto) (domain.StockLevel, error)
+}
+
+type StockService struct {
+	stockRepository persistence.IStockRepository
+	storeRepository persistence.IStoreRepository
+}
+
+func NewStockService(stockRepository persistence.IStockRepository, storeRepository persistence.IStoreRepository) IStockService {
+	return &StockService{
+		stockRepository: stockRepository,
+		storeRepository: storeRepository,
+	}
+}
+
+func (stockService *StockService) GetProductStock(ctx context.Context, productId int64) (domain.StockLevel, error) {
+	return stockService.stockRepository.GetByProductId(ctx, productId)
+}
+
+func (stockService *StockService) GetStoreStock(ctx context.Context, storeId int64) ([]domain.StockLevel, error) {
+	if _, storeErr := stockService.storeRepository.GetById(ctx, storeId); storeErr != nil {
+		return nil, storeErr
+	}
+
+	return stockService.stockRepository.GetByStoreId(ctx, storeId)
+}
+
+func (stockService *StockService) Adjust(ctx context.Context, stockOperationRequestDto dto.StockOperationRequestDto) (domain.StockLevel, error) {
+	return stockService.apply(ctx, domain.AdjustStock, stockOperationRequestDto)
+}
+
+func (stockService *StockService) Reserve(ctx context.Context, stockOperationRequestDto dto.StockOperationRequestDto) (domain.StockLevel, error) {
+	return stockService.apply(ctx, domain.ReserveStock, stockOperationRequestDto)
+}
+
+func (stockService *StockService) Release(ctx context.Context, stockOperationRequestDto dto.StockOperationRequestDto) (domain.StockLevel, error) {
+	return stockService.apply(ctx, domain.ReleaseStock, stockOperationRequestDto)
+}
+
+func (stockService *StockService) Commit(ctx context.Context, stockOperationRequestDto dto.StockOperationRequestDto) (domain.StockLevel, error) {
+	return stockService.apply(ctx, domain.CommitStock, stockOperationRequestDto)
+}
+
+func (stockService *StockService) apply(ctx context.Context, operation domain.StockOperation, stockOperationRequestDto dto.StockOperationRequestDto) (domain.StockLevel, error) {
+	return stockService.stockRepository.Update(ctx, stockOperationRequestDto.ProductId, func(stockLevel domain.StockLevel) (domain.StockLevel, error) {
+		return stockLevel.Apply(operation, stockOperationRequestDto.Quantity)
+	})
+}
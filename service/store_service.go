@@ -0,0 +1,74 @@
+package service
+
+import (
+	"Service-schema/domain"
+	"Service-schema/domain/domainerror"
+	"Service-schema/persistence"
+	"Service-schema/service/dto"
+	"context"
+	"strings"
+	"unicode/utf8"
+)
+
+type IStoreService interface {
+	Add(ctx context.Context, createStoreRequestDto dto.CreateStoreRequestDto) (domain.Store, error)
+	Update(ctx context.Context, updateStoreRequestDto dto.UpdateStoreRequestDto) (domain.Store, error)
+	Delete(ctx context.Context, storeId int64) error
+	GetById(ctx context.Context, storeId int64) (domain.Store, error)
+	GetAllStores(ctx context.Context) ([]domain.Store, error)
+}
+
+type StoreService struct {
+	storeRepository persistence.IStoreRepository
+}
+
+func NewStoreService(storeRepository persistence.IStoreRepository) IStoreService {
+	return &StoreService{
+		storeRepository: storeRepository,
+	}
+}
+
+func (storeService *StoreService) Add(ctx context.Context, createStoreRequestDto dto.CreateStoreRequestDto) (domain.Store, error) {
+	name, validationErr := validateStoreName(createStoreRequestDto.Name)
+	if validationErr != nil {
+		return domain.Store{}, validationErr
+	}
+
+	return storeService.storeRepository.Add(ctx, domain.Store{Name: name})
+}
+
+func (storeService *StoreService) Update(ctx context.Context, updateStoreRequestDto dto.UpdateStoreRequestDto) (domain.Store, error) {
+	name, validationErr := validateStoreName(updateStoreRequestDto.Name)
+	if validationErr != nil {
+		return domain.Store{}, validationErr
+	}
+
+	return storeService.storeRepository.Update(ctx, domain.Store{Id: updateStoreRequestDto.Id, Name: name})
+}
+
+func (storeService *StoreService) Delete(ctx context.Context, storeId int64) error {
+	return storeService.storeRepository.DeleteById(ctx, storeId)
+}
+
+func (storeService *StoreService) GetById(ctx context.Context, storeId int64) (domain.Store, error) {
+	return storeService.storeRepository.GetById(ctx, storeId)
+}
+
+func (storeService *StoreService) GetAllStores(ctx context.Context) ([]domain.Store, error) {
+	return storeService.storeRepository.GetAllStores(ctx)
+}
+
+func validateStoreName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", invalidStoreError("name", "Name must be specified")
+	}
+	if utf8.RuneCountInString(name) > 255 {
+		return "", invalidStoreError("name", "Name must not be longer than 255 characters")
+	}
+	return name, nil
+}
+
+func invalidStoreError(field string, message string) error {
+	return domainerror.Validation(domain.InvalidStoreCode, message, domainerror.FieldError{Field: field, Message: message})
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"Service-schema/core/tracing"
+	"Service-schema/domain"
+	"Service-schema/service/dto"
+	"context"
+	"go.opentelemetry.io/otel/trace"
+	"time"
+)
+
+const productServiceTracerName = "Service-schema/service"
+
+// TracedProductService wraps every IProductService call in a span.
+type TracedProductService struct {
+	next   IProductService
+	tracer trace.Tracer
+}
+
+func NewTracedProductService(next IProductService, tracerProvider trace.TracerProvider) IProductService {
+	return &TracedProductService{next: next, tracer: tracerProvider.Tracer(productServiceTracerName)}
+}
+
+func (tracedService *TracedProductService) Add(ctx context.Context, createProductRequestDto dto.CreateProductRequestDto) (domain.Product, error) {
+	ctx, span := tracedService.tracer.Start(ctx, "ProductService.Add")
+	result, err := tracedService.next.Add(ctx, createProductRequestDto)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (tracedService *TracedProductService) Delete(ctx context.Context, productId int64, expectedVersion int64) error {
+	ctx, span := tracedService.tracer.Start(ctx, "ProductService.Delete", trace.WithAttributes(tracing.ProductIdKey.Int64(productId)))
+	err := tracedService.next.Delete(ctx, productId, expectedVersion)
+	tracing.End(span, err)
+	return err
+}
+
+func (tracedService *TracedProductService) UpdatePrice(ctx context.Context, updateProductRequestDto dto.UpdateProductRequestDto) error {
+	ctx, span := tracedService.tracer.Start(ctx, "ProductService.UpdatePrice", trace.WithAttributes(tracing.ProductIdKey.Int64(updateProductRequestDto.Id)))
+	err := tracedService.next.UpdatePrice(ctx, updateProductRequestDto)
+	tracing.End(span, err)
+	return err
+}
+
+func (tracedService *TracedProductService) Replace(ctx context.Context, replaceProductRequestDto dto.ReplaceProductRequestDto) (domain.Product, error) {
+	ctx, span := tracedService.tracer.Start(ctx, "ProductService.Replace", trace.WithAttributes(tracing.ProductIdKey.Int64(replaceProductRequestDto.Id)))
+	result, err := tracedService.next.Replace(ctx, replaceProductRequestDto)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (tracedService *TracedProductService) Patch(ctx context.Context, patchProductRequestDto dto.PatchProductRequestDto) (domain.Product, error) {
+	ctx, span := tracedService.tracer.Start(ctx, "ProductService.Patch", trace.WithAttributes(tracing.ProductIdKey.Int64(patchProductRequestDto.Id)))
+	result, err := tracedService.next.Patch(ctx, patchProductRequestDto)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (tracedService *TracedProductService) GetById(ctx context.Context, productId int64) (domain.Product, error) {
+	ctx, span := tracedService.tracer.Start(ctx, "ProductService.GetById", trace.WithAttributes(tracing.ProductIdKey.Int64(productId)))
+	result, err := tracedService.next.GetById(ctx, productId)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (tracedService *TracedProductService) GetByIdAt(ctx context.Context, productId int64, at time.Time) (domain.Product, error) {
+	ctx, span := tracedService.tracer.Start(ctx, "ProductService.GetByIdAt", trace.WithAttributes(tracing.ProductIdKey.Int64(productId)))
+	result, err := tracedService.next.GetByIdAt(ctx, productId, at)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (tracedService *TracedProductService) GetProducts(ctx context.Context, query domain.ProductQuery) (domain.ProductPage, error) {
+	ctx, span := tracedService.tracer.Start(ctx, "ProductService.GetProducts")
+	result, err := tracedService.next.GetProducts(ctx, query)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (tracedService *TracedProductService) ExportProducts(ctx context.Context, query domain.ProductQuery, write func(domain.Product) error) error {
+	ctx, span := tracedService.tracer.Start(ctx, "ProductService.ExportProducts")
+	err := tracedService.next.ExportProducts(ctx, query, write)
+	tracing.End(span, err)
+	return err
+}
+
+func (tracedService *TracedProductService) SearchProducts(ctx context.Context, query domain.ProductSearchQuery) (domain.ProductSearchResult, error) {
+	ctx, span := tracedService.tracer.Start(ctx, "ProductService.SearchProducts")
+	result, err := tracedService.next.SearchProducts(ctx, query)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (tracedService *TracedProductService) Restore(ctx context.Context, productId int64) (domain.Product, error) {
+	ctx, span := tracedService.tracer.Start(ctx, "ProductService.Restore", trace.WithAttributes(tracing.ProductIdKey.Int64(productId)))
+	result, err := tracedService.next.Restore(ctx, productId)
+	tracing.End(span, err)
+	return result, err
+}
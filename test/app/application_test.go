@@ -0,0 +1,163 @@
+package app
+
+import (
+	"Service-schema/core/app"
+	"Service-schema/core/server"
+	"context"
+	"errors"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func ephemeralServerConfig() server.Config {
+	return server.Config{Host: "127.0.0.1", Port: 0, ShutdownTimeout: 5 * time.Second}
+}
+
+// runApplication runs the application in the background and waits for its listener to accept connections.
+func runApplication(t *testing.T, ctx context.Context, e *echo.Echo, application *app.Application) (string, <-chan error) {
+	runErrs := make(chan error, 1)
+	go func() {
+		runErrs <- application.Run(ctx)
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for e.ListenerAddr() == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("application did not start listening")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return "http://" + e.ListenerAddr().String(), runErrs
+}
+
+func waitForRun(t *testing.T, runErrs <-chan error) error {
+	select {
+	case runErr := <-runErrs:
+		return runErr
+	case <-time.After(10 * time.Second):
+		t.Fatal("application did not stop")
+		return nil
+	}
+}
+
+func Test_WhenContextCancelled_ShouldDrainInFlightRequestsAndRunHooksInReverseOrder(t *testing.T) {
+	t.Run("WhenContextCancelled_ShouldDrainInFlightRequestsAndRunHooksInReverseOrder", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		handlerStarted, releaseHandler := make(chan struct{}), make(chan struct{})
+		e := echo.New()
+		e.GET("/slow", func(c echo.Context) error {
+			close(handlerStarted)
+			<-releaseHandler
+			return c.NoContent(http.StatusOK)
+		})
+		var closedHooks []string
+		application := app.NewApplication(e, ephemeralServerConfig(), discardLogger)
+		for _, name := range []string{"database pool", "tracer provider", "purge job"} {
+			application.OnShutdown(name, func(ctx context.Context) error {
+				closedHooks = append(closedHooks, name)
+				return nil
+			})
+		}
+		address, runErrs := runApplication(t, ctx, e, application)
+
+		responseStatuses := make(chan int, 1)
+		go func() {
+			response, getErr := http.Get(address + "/slow")
+			if getErr != nil {
+				responseStatuses <- 0
+				return
+			}
+			_ = response.Body.Close()
+			responseStatuses <- response.StatusCode
+		}()
+		<-handlerStarted
+		cancel()
+		time.Sleep(50 * time.Millisecond)
+		close(releaseHandler)
+
+		assert.Nil(t, waitForRun(t, runErrs))
+		assert.Equal(t, http.StatusOK, <-responseStatuses)
+		assert.Equal(t, []string{"purge job", "tracer provider", "database pool"}, closedHooks)
+	})
+}
+
+func Test_WhenTerminationSignalReceived_ShouldShutDownGracefully(t *testing.T) {
+	t.Run("WhenTerminationSignalReceived_ShouldShutDownGracefully", func(t *testing.T) {
+		e := echo.New()
+		hookRan := false
+		application := app.NewApplication(e, ephemeralServerConfig(), discardLogger)
+		application.OnShutdown("database pool", func(ctx context.Context) error {
+			hookRan = true
+			return nil
+		})
+		_, runErrs := runApplication(t, context.Background(), e, application)
+
+		assert.Nil(t, syscall.Kill(os.Getpid(), syscall.SIGTERM))
+
+		assert.Nil(t, waitForRun(t, runErrs))
+		assert.True(t, hookRan)
+	})
+}
+
+func Test_WhenHookOutlivesShutdownTimeout_ShouldReportItAndRunRemainingHooks(t *testing.T) {
+	t.Run("WhenHookOutlivesShutdownTimeout_ShouldReportItAndRunRemainingHooks", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		e := echo.New()
+		serverConfig := ephemeralServerConfig()
+		serverConfig.ShutdownTimeout = 50 * time.Millisecond
+		firstHookRan := false
+		application := app.NewApplication(e, serverConfig, discardLogger)
+		application.OnShutdown("database pool", func(ctx context.Context) error {
+			firstHookRan = true
+			return nil
+		})
+		application.OnShutdown("purge job", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		_, runErrs := runApplication(t, ctx, e, application)
+
+		cancel()
+		runErr := waitForRun(t, runErrs)
+
+		assert.ErrorIs(t, runErr, context.DeadlineExceeded)
+		assert.Equal(t, "purge job shutdown: context deadline exceeded", runErr.Error())
+		assert.True(t, firstHookRan)
+	})
+}
+
+func Test_WhenServerFailsToStart_ShouldReturnErrorAfterRunningHooks(t *testing.T) {
+	t.Run("WhenServerFailsToStart_ShouldReturnErrorAfterRunningHooks", func(t *testing.T) {
+		listener, listenErr := net.Listen("tcp", "127.0.0.1:0")
+		assert.Nil(t, listenErr)
+		defer listener.Close()
+		serverConfig := ephemeralServerConfig()
+		serverConfig.Port = listener.Addr().(*net.TCPAddr).Port
+		hookRan := false
+		application := app.NewApplication(echo.New(), serverConfig, discardLogger)
+		application.OnShutdown("database pool", func(ctx context.Context) error {
+			hookRan = true
+			return nil
+		})
+
+		runErr := application.Run(context.Background())
+
+		var opErr *net.OpError
+		assert.ErrorAs(t, runErr, &opErr)
+		assert.Contains(t, runErr.Error(), "http server: ")
+		assert.True(t, hookRan)
+		assert.False(t, errors.Is(runErr, http.ErrServerClosed))
+	})
+}
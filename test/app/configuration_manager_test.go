@@ -0,0 +1,123 @@
+package app
+
+import (
+	"Service-schema/core/app"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeConfigurationFile(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	assert.Nil(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func Test_WhenKeySetInSeveralLayers_ShouldApplyTheLastLayer(t *testing.T) {
+	fileContent := "postgresql:\n  port: 5432\n  db_name: from_file\n"
+	testCases := []struct {
+		name             string
+		file             bool
+		environment      map[string]string
+		args             []string
+		expectedPort     int
+		expectedDbName   string
+		expectedPoolSize int
+	}{
+		{name: "Defaults", expectedPort: 6432, expectedDbName: "product_service", expectedPoolSize: 10},
+		{name: "FileOverDefaults", file: true, expectedPort: 5432, expectedDbName: "from_file", expectedPoolSize: 10},
+		{name: "EnvironmentOverFile", file: true, environment: map[string]string{"POSTGRESQL_PORT": "7432"},
+			expectedPort: 7432, expectedDbName: "from_file", expectedPoolSize: 10},
+		{name: "FlagsOverEnvironment", file: true, environment: map[string]string{"POSTGRESQL_PORT": "7432", "POSTGRESQL_MAX_CONNECTIONS": "20"},
+			args: []string{"-postgresql.port=8432"}, expectedPort: 8432, expectedDbName: "from_file", expectedPoolSize: 20},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			args := testCase.args
+			if testCase.file {
+				args = append([]string{"-config", writeConfigurationFile(t, "config.yaml", fileContent)}, args...)
+			}
+			for name, value := range testCase.environment {
+				t.Setenv(name, value)
+			}
+
+			configurationManager, err := app.NewConfigurationManager(args)
+
+			assert.Nil(t, err)
+			assert.Equal(t, testCase.expectedPort, configurationManager.PostgresqlConfig.Port)
+			assert.Equal(t, testCase.expectedDbName, configurationManager.PostgresqlConfig.DbName)
+			assert.Equal(t, testCase.expectedPoolSize, configurationManager.PostgresqlConfig.MaxConnections)
+		})
+	}
+}
+
+func Test_WhenConfigurationInvalid_ShouldReportEveryProblem(t *testing.T) {
+	testCases := []struct {
+		name           string
+		fileName       string
+		content        string
+		args           []string
+		expectedErrors []string
+	}{
+		{name: "UnknownKey", fileName: "config.yaml", content: "postgresql:\n  hots: db\n",
+			expectedErrors: []string{"invalid configuration postgresql.hots: unknown key in"}},
+		{name: "UnparsableValues", fileName: "config.yaml", content: "server:\n  port: http\n  read_timeout: soon\nauth:\n  require_token_for_price_changes: sometimes\n",
+			expectedErrors: []string{`invalid configuration server.port: "http" is not an integer`, `invalid configuration server.read_timeout: "soon" is not a duration`,
+				`invalid configuration auth.require_token_for_price_changes: "sometimes" is not a boolean`}},
+		{name: "SeveralInvalidValues", args: []string{"-postgresql.max_connections=0", "-purge.retention=0s", "-logging.format=xml", "-auth.admin_token_sha256=secret"},
+			expectedErrors: []string{"postgresql.max_connections: must be greater than 0", "purge.retention: must be greater than 0", "logging.format: must be text or json",
+				"auth.admin_token_sha256: must be 64 hexadecimal characters"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			args := testCase.args
+			if testCase.fileName != "" {
+				args = append([]string{"-config", writeConfigurationFile(t, testCase.fileName, testCase.content)}, args...)
+			}
+
+			_, err := app.NewConfigurationManager(args)
+
+			assert.NotNil(t, err)
+			for _, expectedError := range testCase.expectedErrors {
+				assert.ErrorContains(t, err, expectedError)
+			}
+		})
+	}
+}
+
+func Test_WhenJSONFileHasLargeNumbers_ShouldKeepThemIntegers(t *testing.T) {
+	t.Run("WhenJSONFileHasLargeNumbers_ShouldKeepThemIntegers", func(t *testing.T) {
+		path := writeConfigurationFile(t, "config.json", `{"postgresql": {"max_connections": 1000000, "read_query_timeout": "2s"}}`)
+
+		configurationManager, err := app.NewConfigurationManager([]string{"-config", path})
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1000000, configurationManager.PostgresqlConfig.MaxConnections)
+		assert.Equal(t, 2*time.Second, configurationManager.PostgresqlConfig.QueryTimeouts.Read)
+	})
+}
+
+func Test_WhenArgumentsFollowFlags_ShouldKeepThemPositional(t *testing.T) {
+	t.Run("WhenArgumentsFollowFlags_ShouldKeepThemPositional", func(t *testing.T) {
+		configurationManager, err := app.NewConfigurationManager([]string{"-server.port=9090", "migrate", "up"})
+
+		assert.Nil(t, err)
+		assert.Equal(t, 9090, configurationManager.ServerConfig.Port)
+		assert.Equal(t, []string{"migrate", "up"}, configurationManager.Args)
+	})
+}
+
+func Test_WhenFlagsFollowSubcommand_ShouldStillApplyThem(t *testing.T) {
+	t.Run("WhenFlagsFollowSubcommand_ShouldStillApplyThem", func(t *testing.T) {
+		configurationManager, err := app.NewConfigurationManager([]string{"migrate", "down", "-postgresql.host=db", "2", "--", "-server.port=1"})
+
+		assert.Nil(t, err)
+		assert.Equal(t, "db", configurationManager.PostgresqlConfig.Host)
+		assert.Equal(t, 8080, configurationManager.ServerConfig.Port)
+		assert.Equal(t, []string{"migrate", "down", "2", "-server.port=1"}, configurationManager.Args)
+	})
+}
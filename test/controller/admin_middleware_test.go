@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"Service-schema/controller"
+	"Service-schema/core/auth"
+	"Service-schema/domain"
+	"Service-schema/service"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type listingProductService struct {
+	service.IProductService
+	queries []domain.ProductQuery
+}
+
+func (listingService *listingProductService) GetProducts(ctx context.Context, query domain.ProductQuery) (domain.ProductPage, error) {
+	listingService.queries = append(listingService.queries, query)
+	return domain.NewProductPage(query, nil, 0), nil
+}
+
+func tokenDigest(token string) string {
+	digest := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(digest[:])
+}
+
+func newAdminTokenServer(t *testing.T, productService service.IProductService, authConfig auth.Config) *echo.Echo {
+	authConfig.AdminTokenSHA256 = tokenDigest("admin-token")
+	assert.True(t, auth.IsValidTokenDigest(authConfig.AdminTokenSHA256))
+
+	e := echo.New()
+	e.HTTPErrorHandler = controller.NewHTTPErrorHandler(discardLogger)
+	e.Use(controller.AdminTokenMiddleware(auth.NewAdminToken(authConfig.AdminTokenSHA256)))
+	controller.NewProductController(productService, authConfig, discardLogger).RegisterRoutes(e)
+	return e
+}
+
+func getWithToken(e *echo.Echo, path string, authorization string) int {
+	request := httptest.NewRequest(http.MethodGet, path, nil)
+	if authorization != "" {
+		request.Header.Set(echo.HeaderAuthorization, authorization)
+	}
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+	return recorder.Code
+}
+
+func Test_WhenListingDeletedProducts_ShouldRequireAdmin(t *testing.T) {
+	t.Run("WhenListingDeletedProducts_ShouldRequireAdmin", func(t *testing.T) {
+		productService := &listingProductService{}
+		e := newAdminTokenServer(t, productService, auth.Config{})
+
+		assert.Equal(t, http.StatusUnauthorized, getWithToken(e, "/api/v1/products/?include_deleted=true", ""))
+		assert.Equal(t, http.StatusUnauthorized, getWithToken(e, "/api/v1/stores/1/products?include_deleted=true", ""))
+		assert.Equal(t, http.StatusOK, getWithToken(e, "/api/v1/products/?include_deleted=true", "Bearer admin-token"))
+		assert.Equal(t, http.StatusOK, getWithToken(e, "/api/v1/products/", ""))
+
+		assert.Equal(t, 2, len(productService.queries))
+		assert.True(t, productService.queries[0].IncludeDeleted)
+		assert.False(t, productService.queries[1].IncludeDeleted)
+	})
+}
+
+func Test_WhenTokenUnknown_ShouldRejectRequest(t *testing.T) {
+	t.Run("WhenTokenUnknown_ShouldRejectRequest", func(t *testing.T) {
+		e := newAdminTokenServer(t, &listingProductService{}, auth.Config{})
+
+		assert.Equal(t, http.StatusUnauthorized, getWithToken(e, "/api/v1/products/", "Bearer guessed-token"))
+		assert.Equal(t, http.StatusUnauthorized, getWithToken(e, "/api/v1/products/", "Bearer admin-token-and-more"))
+		assert.Equal(t, http.StatusUnauthorized, getWithToken(e, "/api/v1/products/", "Basic YWRtaW46YWRtaW4tdG9rZW4="))
+	})
+}
+
+func sendMalformedBody(e *echo.Echo, method string, path string, authorization string) int {
+	request := httptest.NewRequest(method, path, strings.NewReader("{"))
+	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if authorization != "" {
+		request.Header.Set(echo.HeaderAuthorization, authorization)
+	}
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+	return recorder.Code
+}
+
+func Test_WhenChangingPricesAnonymously_ShouldAcceptRequestByDefault(t *testing.T) {
+	t.Run("WhenChangingPricesAnonymously_ShouldAcceptRequestByDefault", func(t *testing.T) {
+		e := newAdminTokenServer(t, &listingProductService{}, auth.Config{})
+
+		assert.Equal(t, http.StatusBadRequest, sendMalformedBody(e, http.MethodPut, "/api/v1/products/", ""))
+		assert.Equal(t, http.StatusBadRequest, sendMalformedBody(e, http.MethodPut, "/api/v1/products/1", ""))
+		assert.Equal(t, http.StatusBadRequest, sendMalformedBody(e, http.MethodPatch, "/api/v1/products/1", ""))
+	})
+}
+
+func Test_WhenTokenRequiredForPriceChanges_ShouldRejectAnonymousRequest(t *testing.T) {
+	t.Run("WhenTokenRequiredForPriceChanges_ShouldRejectAnonymousRequest", func(t *testing.T) {
+		e := newAdminTokenServer(t, &listingProductService{}, auth.Config{RequireTokenForPriceChanges: true})
+
+		assert.Equal(t, http.StatusUnauthorized, sendMalformedBody(e, http.MethodPut, "/api/v1/products/", ""))
+		assert.Equal(t, http.StatusUnauthorized, sendMalformedBody(e, http.MethodPut, "/api/v1/products/1", ""))
+		assert.Equal(t, http.StatusUnauthorized, sendMalformedBody(e, http.MethodPatch, "/api/v1/products/1", ""))
+		assert.Equal(t, http.StatusBadRequest, sendMalformedBody(e, http.MethodPut, "/api/v1/products/", "Bearer admin-token"))
+	})
+}
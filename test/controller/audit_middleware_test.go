@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"Service-schema/controller"
+	"Service-schema/core/audit"
+	"Service-schema/core/auth"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func auditedChange(admin bool, headers map[string]string) audit.Change {
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodPut, "/api/v1/products/", nil)
+	for name, value := range headers {
+		request.Header.Set(name, value)
+	}
+	if admin {
+		request = request.WithContext(auth.WithAdmin(request.Context()))
+	}
+	c := e.NewContext(request, httptest.NewRecorder())
+
+	var change audit.Change
+	_ = controller.AuditMiddleware(func(c echo.Context) error {
+		change = audit.FromContext(c.Request().Context())
+		return nil
+	})(c)
+	return change
+}
+
+func Test_WhenCallerIsAdmin_ShouldStoreAdminAsActor(t *testing.T) {
+	t.Run("WhenCallerIsAdmin_ShouldStoreAdminAsActor", func(t *testing.T) {
+		change := auditedChange(true, map[string]string{"X-Actor": "someone-else@example.com", "X-Change-Reason": "Supplier price increase"})
+		assert.Equal(t, audit.Change{Actor: audit.AdminActor, Reason: "Supplier price increase"}, change)
+	})
+}
+
+func Test_WhenCallerAnonymous_ShouldIgnoreActorHeader(t *testing.T) {
+	t.Run("WhenCallerAnonymous_ShouldIgnoreActorHeader", func(t *testing.T) {
+		change := auditedChange(false, map[string]string{"X-Actor": "finance@example.com"})
+		assert.Equal(t, audit.AnonymousActor, change.Actor)
+	})
+}
@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"Service-schema/controller"
+	"Service-schema/domain/domainerror"
+	"errors"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func handleError(err error) *httptest.ResponseRecorder {
+	e := echo.New()
+	recorder := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/api/v1/products/1", nil), recorder)
+	controller.NewHTTPErrorHandler(discardLogger)(err, c)
+	return recorder
+}
+
+func Test_WhenNotFoundErrorReturned_ShouldRespondWith404(t *testing.T) {
+	t.Run("WhenNotFoundErrorReturned_ShouldRespondWith404", func(t *testing.T) {
+		recorder := handleError(domainerror.NotFound("product_not_found", "Product not found with id 1"))
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+		assert.JSONEq(t, `{"error_code":"product_not_found","error_description":"Product not found with id 1"}`, recorder.Body.String())
+	})
+}
+
+func Test_WhenValidationErrorReturned_ShouldRespondWith422AndFields(t *testing.T) {
+	t.Run("WhenValidationErrorReturned_ShouldRespondWith422AndFields", func(t *testing.T) {
+		recorder := handleError(domainerror.Validation("invalid_product", "Name must be specified", domainerror.FieldError{Field: "name", Message: "Name must be specified"}))
+		assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+		assert.JSONEq(t, `{"error_code":"invalid_product","error_description":"Name must be specified","fields":[{"field":"name","message":"Name must be specified"}]}`, recorder.Body.String())
+	})
+}
+
+func Test_WhenConflictErrorReturned_ShouldRespondWith409(t *testing.T) {
+	t.Run("WhenConflictErrorReturned_ShouldRespondWith409", func(t *testing.T) {
+		recorder := handleError(domainerror.Conflict("product_conflict", "Product was modified"))
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+	})
+}
+
+func Test_WhenUnknownErrorReturned_ShouldRespondWith500WithoutDetails(t *testing.T) {
+	t.Run("WhenUnknownErrorReturned_ShouldRespondWith500WithoutDetails", func(t *testing.T) {
+		recorder := handleError(errors.New("connection refused"))
+		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+		assert.JSONEq(t, `{"error_code":"internal_error","error_description":"Internal Server Error"}`, recorder.Body.String())
+	})
+}
+
+func Test_WhenPreconditionFailedErrorReturned_ShouldRespondWith412(t *testing.T) {
+	t.Run("WhenPreconditionFailedErrorReturned_ShouldRespondWith412", func(t *testing.T) {
+		recorder := handleError(domainerror.PreconditionFailed("product_version_conflict", "Product with id 1 was modified since version 1"))
+		assert.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+		assert.JSONEq(t, `{"error_code":"product_version_conflict","error_description":"Product with id 1 was modified since version 1"}`, recorder.Body.String())
+	})
+}
@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"Service-schema/controller"
+	"Service-schema/core/health"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newHealthServer(healthRegistry health.IRegistry) *echo.Echo {
+	e := echo.New()
+	e.HTTPErrorHandler = controller.NewHTTPErrorHandler(discardLogger)
+	controller.NewHealthController(healthRegistry).RegisterRoutes(e)
+	return e
+}
+
+func getHealth(e *echo.Echo, path string) (int, map[string]any) {
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+	var body map[string]any
+	_ = json.Unmarshal(recorder.Body.Bytes(), &body)
+	return recorder.Code, body
+}
+
+func Test_WhenDependencyIsDown_ShouldReportNotReadyButLive(t *testing.T) {
+	t.Run("WhenDependencyIsDown_ShouldReportNotReadyButLive", func(t *testing.T) {
+		healthRegistry := health.NewRegistry(health.Config{CheckTimeout: time.Second})
+		healthRegistry.Register("database", health.CheckerFunc(func(ctx context.Context) error { return nil }))
+		healthRegistry.Register("migrations", health.CheckerFunc(func(ctx context.Context) error {
+			return errors.New("1 pending migration(s), starting at 10_product_search")
+		}))
+		e := newHealthServer(healthRegistry)
+
+		status, body := getHealth(e, "/health/ready")
+
+		assert.Equal(t, http.StatusServiceUnavailable, status)
+		assert.Equal(t, "down", body["status"])
+		checks := body["checks"].(map[string]any)
+		assert.Equal(t, "up", checks["database"].(map[string]any)["status"])
+		assert.Equal(t, "down", checks["migrations"].(map[string]any)["status"])
+		assert.Equal(t, "1 pending migration(s), starting at 10_product_search", checks["migrations"].(map[string]any)["error"])
+
+		status, body = getHealth(e, "/health/live")
+
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "up", body["status"])
+	})
+}
+
+func Test_WhenChecksAreUp_ShouldReportReadyAndReuseCachedResults(t *testing.T) {
+	t.Run("WhenChecksAreUp_ShouldReportReadyAndReuseCachedResults", func(t *testing.T) {
+		calls := 0
+		healthRegistry := health.NewRegistry(health.Config{CheckTimeout: time.Second, CacheTTL: time.Minute})
+		healthRegistry.Register("database", health.CheckerFunc(func(ctx context.Context) error {
+			calls++
+			return nil
+		}))
+		e := newHealthServer(healthRegistry)
+
+		firstStatus, _ := getHealth(e, "/health/ready")
+		secondStatus, body := getHealth(e, "/health/ready")
+
+		assert.Equal(t, http.StatusOK, firstStatus)
+		assert.Equal(t, http.StatusOK, secondStatus)
+		assert.Equal(t, "up", body["status"])
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func Test_WhenCheckHangs_ShouldReportItDownAfterTimeout(t *testing.T) {
+	t.Run("WhenCheckHangs_ShouldReportItDownAfterTimeout", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+		healthRegistry := health.NewRegistry(health.Config{CheckTimeout: 20 * time.Millisecond})
+		healthRegistry.Register("database", health.CheckerFunc(func(ctx context.Context) error {
+			<-release
+			return nil
+		}))
+		e := newHealthServer(healthRegistry)
+
+		startedAt := time.Now()
+		status, body := getHealth(e, "/health/ready")
+
+		assert.Less(t, time.Since(startedAt), time.Second)
+		assert.Equal(t, http.StatusServiceUnavailable, status)
+		assert.Equal(t, "timed out after 20ms", body["checks"].(map[string]any)["database"].(map[string]any)["error"])
+	})
+}
@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"Service-schema/controller"
+	"Service-schema/core/logging"
+	"Service-schema/domain/domainerror"
+	"bytes"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func Test_WhenRequestsHandled_ShouldCountThemByRouteAndStatus(t *testing.T) {
+	t.Run("WhenRequestsHandled_ShouldCountThemByRouteAndStatus", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		e := echo.New()
+		e.HTTPErrorHandler = controller.NewHTTPErrorHandler(discardLogger)
+		e.Use(controller.MetricsMiddleware(registry, middleware.DefaultSkipper))
+		e.GET("/api/v1/products/:id", func(c echo.Context) error {
+			if c.Param("id") == "3" {
+				return domainerror.NotFound("product_not_found", "Product not found with id 3")
+			}
+			return c.NoContent(http.StatusOK)
+		})
+
+		for _, path := range []string{"/api/v1/products/1", "/api/v1/products/2", "/api/v1/products/3", "/wp-login.php"} {
+			e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+		}
+
+		expected := `
+# HELP http_requests_total Number of HTTP requests handled.
+# TYPE http_requests_total counter
+http_requests_total{method="GET",route="/api/v1/products/:id",status="200"} 2
+http_requests_total{method="GET",route="/api/v1/products/:id",status="404"} 1
+http_requests_total{method="GET",route="unmatched",status="404"} 1
+`
+		assert.Nil(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "http_requests_total"))
+	})
+}
+
+func Test_WhenOperationalRoutesRequested_ShouldNotCountOrLogThem(t *testing.T) {
+	t.Run("WhenOperationalRoutesRequested_ShouldNotCountOrLogThem", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		var logOutput bytes.Buffer
+		logger := logging.NewLogger(logging.Config{Format: logging.JSONFormat, Level: "info"}, &logOutput)
+		e := echo.New()
+		e.Use(controller.RequestLoggingMiddleware(logger, controller.SkipOperationalRoutes))
+		e.Use(controller.MetricsMiddleware(registry, controller.SkipOperationalRoutes))
+		for _, path := range []string{"/health/live", "/health/ready", "/metrics", "/api/v1/products"} {
+			e.GET(path, func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})
+		}
+
+		for _, path := range []string{"/health/live", "/health/ready", "/metrics", "/api/v1/products"} {
+			e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+		}
+
+		expected := `
+# HELP http_requests_total Number of HTTP requests handled.
+# TYPE http_requests_total counter
+http_requests_total{method="GET",route="/api/v1/products",status="200"} 1
+`
+		assert.Nil(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "http_requests_total"))
+		assert.Equal(t, 1, strings.Count(logOutput.String(), "Handled request"))
+		assert.Contains(t, logOutput.String(), `"route":"/api/v1/products"`)
+	})
+}
@@ -0,0 +1,128 @@
+package controller
+
+import (
+	"Service-schema/controller"
+	"Service-schema/controller/request"
+	"Service-schema/controller/response"
+	"Service-schema/core/auth"
+	"Service-schema/domain"
+	"Service-schema/service"
+	"bytes"
+	"context"
+	"errors"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/xuri/excelize/v2"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var exportedProduct = domain.Product{
+	Id:       7,
+	Name:     `27" Monitor, matte`,
+	Price:    domain.MustMoney("2000", "USD"),
+	Discount: domain.MustDecimal("12.5"),
+	StoreId:  1,
+	Store:    "BENQ",
+	Version:  3,
+}
+
+func writeProductExport(t *testing.T, format response.ProductExportFormat) *bytes.Buffer {
+	var buffer bytes.Buffer
+	exportWriter, err := response.NewProductExportWriter(format, &buffer)
+	assert.Nil(t, err)
+	assert.Nil(t, exportWriter.Write(exportedProduct))
+	assert.Nil(t, exportWriter.Close())
+	return &buffer
+}
+
+func Test_WhenExportFormatRequested_ShouldPreferQueryOverAccept(t *testing.T) {
+	t.Run("WhenExportFormatRequested_ShouldPreferQueryOverAccept", func(t *testing.T) {
+		format, err := request.ProductExportRequest{Format: "XLSX"}.ToFormat("text/csv")
+		assert.Nil(t, err)
+		assert.Equal(t, response.XLSXProductExport, format)
+
+		format, _ = request.ProductExportRequest{}.ToFormat("application/json, application/x-ndjson;q=0.9")
+		assert.Equal(t, response.NDJSONProductExport, format)
+
+		format, _ = request.ProductExportRequest{}.ToFormat("")
+		assert.Equal(t, response.CSVProductExport, format)
+
+		_, err = request.ProductExportRequest{}.ToFormat("application/pdf")
+		assert.ErrorIs(t, err, request.ErrUnsupportedProductExportFormat)
+
+		_, err = request.ProductExportRequest{Format: "pdf"}.ToFormat("")
+		assert.ErrorIs(t, err, request.ErrUnsupportedProductExportFormat)
+	})
+}
+
+func Test_WhenExportingCSV_ShouldWriteHeaderAndQuotedRows(t *testing.T) {
+	t.Run("WhenExportingCSV_ShouldWriteHeaderAndQuotedRows", func(t *testing.T) {
+		buffer := writeProductExport(t, response.CSVProductExport)
+		assert.Equal(t, "id,name,price,currency,discount,store_id,store,discount_amount,discounted_price,version\n"+
+			`7,"27"" Monitor, matte",2000.00,USD,12.5,1,BENQ,250.00,1750.00,3`+"\n", buffer.String())
+	})
+}
+
+func Test_WhenExportingNDJSON_ShouldWriteOneProductPerLine(t *testing.T) {
+	t.Run("WhenExportingNDJSON_ShouldWriteOneProductPerLine", func(t *testing.T) {
+		lines := strings.Split(strings.TrimSuffix(writeProductExport(t, response.NDJSONProductExport).String(), "\n"), "\n")
+		assert.Equal(t, 1, len(lines))
+		assert.JSONEq(t, `{"id":7,"name":"27\" Monitor, matte","price":"2000.00","currency":"USD","discount":"12.5","store_id":1,"store":"BENQ",
+			"discount_amount":"250.00","discounted_price":"1750.00","version":3,"applied_promotions":[]}`, lines[0])
+	})
+}
+
+func Test_WhenExportingXLSX_ShouldWriteProductsSheet(t *testing.T) {
+	t.Run("WhenExportingXLSX_ShouldWriteProductsSheet", func(t *testing.T) {
+		workbook, err := excelize.OpenReader(writeProductExport(t, response.XLSXProductExport))
+		assert.Nil(t, err)
+		defer workbook.Close()
+
+		rows, _ := workbook.GetRows("Products")
+		assert.Equal(t, 2, len(rows))
+		assert.Equal(t, "discounted_price", rows[0][8])
+		assert.Equal(t, []string{"7", `27" Monitor, matte`, "2000.00", "USD", "12.5", "1", "BENQ", "250.00", "1750.00", "3"}, rows[1])
+	})
+}
+
+type failingExportProductService struct {
+	service.IProductService
+}
+
+func (failingService *failingExportProductService) ExportProducts(ctx context.Context, query domain.ProductQuery, write func(domain.Product) error) error {
+	if writeErr := write(exportedProduct); writeErr != nil {
+		return writeErr
+	}
+	return errors.New("connection reset by database")
+}
+
+func Test_WhenExportFailsAfterFirstRow_ShouldRecordFailureAndCloseConnection(t *testing.T) {
+	t.Run("WhenExportFailsAfterFirstRow_ShouldRecordFailureAndCloseConnection", func(t *testing.T) {
+		recordedStatuses := make(chan int, 1)
+		e := echo.New()
+		e.HTTPErrorHandler = controller.NewHTTPErrorHandler(discardLogger)
+		e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+			return func(c echo.Context) error {
+				err := next(c)
+				recordedStatuses <- c.Response().Status
+				return err
+			}
+		})
+		controller.NewProductController(&failingExportProductService{}, auth.Config{}, discardLogger).RegisterRoutes(e)
+		server := httptest.NewServer(e)
+		defer server.Close()
+
+		exportResponse, err := http.Get(server.URL + "/api/v1/products/export?format=ndjson")
+		assert.Nil(t, err)
+		defer exportResponse.Body.Close()
+		_, readErr := io.ReadAll(exportResponse.Body)
+
+		assert.Equal(t, http.StatusOK, exportResponse.StatusCode)
+		assert.ErrorIs(t, readErr, io.ErrUnexpectedEOF)
+		assert.Equal(t, http.StatusInternalServerError, <-recordedStatuses)
+	})
+}
@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"Service-schema/controller/request"
+	"Service-schema/domain"
+	"Service-schema/service/dto"
+	"github.com/stretchr/testify/assert"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func Test_WhenCSVImportGiven_ShouldReadRowsByHeader(t *testing.T) {
+	t.Run("WhenCSVImportGiven_ShouldReadRowsByHeader", func(t *testing.T) {
+		body := "store_id,name,price\n5,Pencil,200\n5,Eraser,cheap\n5,Ruler\n"
+		productImportRequestDto, err := request.ProductImportRequest{}.ToDto("text/csv; charset=utf-8", strings.NewReader(body))
+		rows := slices.Collect(productImportRequestDto.Rows)
+
+		assert.Nil(t, err)
+		assert.Equal(t, domain.AllOrNothingImport, productImportRequestDto.Mode)
+		assert.Equal(t, 3, len(rows))
+		assert.Equal(t, dto.ProductImportRowDto{Row: 1, Product: dto.CreateProductRequestDto{Name: "Pencil", Price: domain.MustDecimal("200"), StoreId: 5}}, rows[0])
+		assert.Equal(t, "price must be a decimal number such as 12.50", rows[1].ParseErr.Error())
+		assert.NotNil(t, rows[2].ParseErr)
+	})
+}
+
+func Test_WhenNDJSONImportGiven_ShouldReadOneRowPerLine(t *testing.T) {
+	t.Run("WhenNDJSONImportGiven_ShouldReadOneRowPerLine", func(t *testing.T) {
+		body := `{"name":"Pencil","price":"200","store_id":5}` + "\n\n" + `{"name":"Eraser","colour":"red"}` + "\n"
+		productImportRequestDto, err := request.ProductImportRequest{Mode: "best_effort"}.ToDto("application/x-ndjson", strings.NewReader(body))
+		rows := slices.Collect(productImportRequestDto.Rows)
+
+		assert.Nil(t, err)
+		assert.Equal(t, domain.BestEffortImport, productImportRequestDto.Mode)
+		assert.Equal(t, 2, len(rows))
+		assert.Equal(t, "Pencil", rows[0].Product.Name)
+		assert.Equal(t, 2, rows[1].Row)
+		assert.NotNil(t, rows[1].ParseErr)
+	})
+}
+
+func Test_WhenCSVHeaderHasUnknownColumn_ShouldRejectImport(t *testing.T) {
+	t.Run("WhenCSVHeaderHasUnknownColumn_ShouldRejectImport", func(t *testing.T) {
+		_, err := request.ProductImportRequest{}.ToDto("text/csv", strings.NewReader("name,price,store_id,colour\n"))
+		_, contentTypeErr := request.ProductImportRequest{}.ToDto("application/xml", strings.NewReader(""))
+		assert.Equal(t, `csv column "colour" is unknown, expected name, price, currency, discount, store_id`, err.Error())
+		assert.ErrorIs(t, contentTypeErr, request.ErrUnsupportedProductImportType)
+	})
+}
@@ -0,0 +1,31 @@
+package controller
+
+import (
+	"Service-schema/controller/request"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_WhenLimitAbsent_ShouldLeaveItToTheDefault(t *testing.T) {
+	t.Run("WhenLimitAbsent_ShouldLeaveItToTheDefault", func(t *testing.T) {
+		query, err := request.ProductListRequest{}.ToQuery()
+		assert.Nil(t, err)
+		assert.Equal(t, 0, query.Limit)
+
+		query, err = request.ProductListRequest{Limit: "100"}.ToQuery()
+		assert.Nil(t, err)
+		assert.Equal(t, 100, query.Limit)
+	})
+}
+
+func Test_WhenLimitOutOfRange_ShouldRejectQuery(t *testing.T) {
+	for _, limit := range []string{"0", "-1", "101", "ten"} {
+		t.Run("WhenLimitIs"+limit+"_ShouldRejectQuery", func(t *testing.T) {
+			_, err := request.ProductListRequest{Limit: limit}.ToQuery()
+			assert.EqualError(t, err, "limit must be an integer between 1 and 100")
+
+			_, err = request.ProductSearchRequest{Q: "monitor", Limit: limit}.ToQuery()
+			assert.EqualError(t, err, "limit must be an integer between 1 and 100")
+		})
+	}
+}
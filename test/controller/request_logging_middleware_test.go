@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"Service-schema/controller"
+	"Service-schema/core/logging"
+	"Service-schema/domain/domainerror"
+	"bytes"
+	"encoding/json"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func serveLoggedRequest(requestId string) (*httptest.ResponseRecorder, []map[string]interface{}) {
+	var logOutput bytes.Buffer
+	logger := logging.NewLogger(logging.Config{Format: logging.JSONFormat, Level: "info"}, &logOutput)
+
+	e := echo.New()
+	e.HTTPErrorHandler = controller.NewHTTPErrorHandler(discardLogger)
+	e.Use(controller.RequestLoggingMiddleware(logger, middleware.DefaultSkipper))
+	e.GET("/api/v1/products/:id", func(c echo.Context) error {
+		logger.InfoContext(c.Request().Context(), "Looked up product", logging.ProductIdKey, 3)
+		return domainerror.NotFound("product_not_found", "Product not found with id 3")
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/api/v1/products/3", nil)
+	if requestId != "" {
+		request.Header.Set(echo.HeaderXRequestID, requestId)
+	}
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(logOutput.String()), "\n") {
+		var record map[string]interface{}
+		_ = json.Unmarshal([]byte(line), &record)
+		records = append(records, record)
+	}
+	return recorder, records
+}
+
+func Test_WhenRequestIdGiven_ShouldPropagateItToResponseAndLogs(t *testing.T) {
+	t.Run("WhenRequestIdGiven_ShouldPropagateItToResponseAndLogs", func(t *testing.T) {
+		recorder, records := serveLoggedRequest("checkout-42")
+
+		assert.Equal(t, "checkout-42", recorder.Header().Get(echo.HeaderXRequestID))
+		assert.Equal(t, 2, len(records))
+		assert.Equal(t, "Looked up product", records[0]["msg"])
+		assert.Equal(t, float64(3), records[0]["product_id"])
+		for _, record := range records {
+			assert.Equal(t, "checkout-42", record["request_id"])
+			assert.Equal(t, "/api/v1/products/:id", record["route"])
+		}
+		assert.Equal(t, float64(http.StatusNotFound), records[1]["status"])
+		assert.Contains(t, records[1], "latency")
+	})
+}
+
+func Test_WhenRequestIdMissingOrMalformed_ShouldAssignNewOne(t *testing.T) {
+	t.Run("WhenRequestIdMissingOrMalformed_ShouldAssignNewOne", func(t *testing.T) {
+		recorder, records := serveLoggedRequest("")
+		requestId := recorder.Header().Get(echo.HeaderXRequestID)
+		assert.Equal(t, 32, len(requestId))
+		assert.Equal(t, requestId, records[1]["request_id"])
+
+		recorder, _ = serveLoggedRequest("id with spaces\nand a forged line")
+		assert.NotContains(t, recorder.Header().Get(echo.HeaderXRequestID), " ")
+	})
+}
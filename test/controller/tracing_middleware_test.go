@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"Service-schema/controller"
+	"Service-schema/core/tracing"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_WhenRequestCarriesTraceparent_ShouldContinueCallerTrace(t *testing.T) {
+	t.Run("WhenRequestCarriesTraceparent_ShouldContinueCallerTrace", func(t *testing.T) {
+		recorder := tracetest.NewSpanRecorder()
+		tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+		e := echo.New()
+		e.HTTPErrorHandler = controller.NewHTTPErrorHandler(discardLogger)
+		e.Use(controller.TracingMiddleware(tracerProvider, tracing.NewPropagator(), middleware.DefaultSkipper))
+		var handlerSpanContext trace.SpanContext
+		e.GET("/api/v1/products/:id", func(c echo.Context) error {
+			handlerSpanContext = trace.SpanContextFromContext(c.Request().Context())
+			return c.NoContent(http.StatusOK)
+		})
+
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/products/1", nil)
+		request.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		e.ServeHTTP(httptest.NewRecorder(), request)
+
+		spans := recorder.Ended()
+		assert.Len(t, spans, 1)
+		span := spans[0]
+		assert.Equal(t, "GET /api/v1/products/:id", span.Name())
+		assert.Equal(t, trace.SpanKindServer, span.SpanKind())
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+		assert.Equal(t, "00f067aa0ba902b7", span.Parent().SpanID().String())
+		assert.True(t, span.Parent().IsRemote())
+		assert.Equal(t, span.SpanContext().SpanID(), handlerSpanContext.SpanID())
+		assert.Contains(t, span.Attributes(), semconv.HTTPResponseStatusCode(http.StatusOK))
+	})
+}
+
+func Test_WhenRequestFails_ShouldMarkServerSpanAsError(t *testing.T) {
+	t.Run("WhenRequestFails_ShouldMarkServerSpanAsError", func(t *testing.T) {
+		recorder := tracetest.NewSpanRecorder()
+		tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+		e := echo.New()
+		e.HTTPErrorHandler = controller.NewHTTPErrorHandler(discardLogger)
+		e.Use(controller.TracingMiddleware(tracerProvider, tracing.NewPropagator(), middleware.DefaultSkipper))
+		e.GET("/api/v1/products/:id", func(c echo.Context) error {
+			return echo.NewHTTPError(http.StatusInternalServerError)
+		})
+
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/products/1", nil))
+
+		spans := recorder.Ended()
+		assert.Len(t, spans, 1)
+		assert.False(t, spans[0].Parent().IsValid())
+		assert.Equal(t, "Error", spans[0].Status().Code.String())
+		assert.Contains(t, spans[0].Attributes(), semconv.HTTPResponseStatusCode(http.StatusInternalServerError))
+	})
+}
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"Service-schema/domain"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_ShouldNestCategoriesUnderTheirParents(t *testing.T) {
+	t.Run("ShouldNestCategoriesUnderTheirParents", func(t *testing.T) {
+		electronics, computers := int64(1), int64(2)
+		categories := []domain.Category{
+			{Id: 1, Name: "Electronics"},
+			{Id: 2, Name: "Computers", ParentId: &electronics},
+			{Id: 3, Name: "Monitors", ParentId: &computers},
+			{Id: 4, Name: "Books"},
+		}
+
+		tree := domain.NewCategoryTree(categories)
+
+		assert.Equal(t, 2, len(tree))
+		assert.Equal(t, "Electronics", tree[0].Name)
+		assert.Equal(t, "Computers", tree[0].Children[0].Name)
+		assert.Equal(t, "Monitors", tree[0].Children[0].Children[0].Name)
+		assert.Empty(t, tree[0].Children[0].Children[0].Children)
+		assert.Equal(t, "Books", tree[1].Name)
+	})
+}
+
+func Test_WhenParentMissing_ShouldTreatCategoryAsRoot(t *testing.T) {
+	t.Run("WhenParentMissing_ShouldTreatCategoryAsRoot", func(t *testing.T) {
+		missingParent := int64(10)
+		tree := domain.NewCategoryTree([]domain.Category{{Id: 2, Name: "Computers", ParentId: &missingParent}})
+
+		assert.Equal(t, 1, len(tree))
+		assert.Equal(t, int64(2), tree[0].Id)
+	})
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"Service-schema/domain"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func Test_ShouldParseAndFormatDecimalExactly(t *testing.T) {
+	t.Run("ShouldParseAndFormatDecimalExactly", func(t *testing.T) {
+		decimal, err := domain.ParseDecimal("11999.99")
+		assert.Nil(t, err)
+		assert.Equal(t, "11999.99", decimal.String())
+		assert.Equal(t, "11999.9900", decimal.StringFixed(4))
+		assert.Equal(t, "-0.5", domain.MustDecimal("-0.50").String())
+		assert.Equal(t, "12000", domain.MustDecimal("12000.0000").String())
+		assert.Equal(t, "5", domain.MustDecimal("+5").String())
+		assert.Equal(t, "-0.500000", domain.MustDecimal("-0.5").StringFixed(6))
+		assert.Equal(t, "-922337203685477.5808", domain.Decimal(math.MinInt64).String())
+	})
+}
+
+func Test_WhenDecimalIsMalformed_ShouldNotParse(t *testing.T) {
+	t.Run("WhenDecimalIsMalformed_ShouldNotParse", func(t *testing.T) {
+		for _, value := range []string{"", "abc", "1.2.3", ".5", "1.", "-+5", "+-5", "--5", "-", "1e3", "0.00001"} {
+			_, err := domain.ParseDecimal(value)
+			assert.NotNil(t, err, value)
+		}
+	})
+}
+
+func Test_ShouldEncodeDecimalAsJSONStringAndAcceptNumbers(t *testing.T) {
+	t.Run("ShouldEncodeDecimalAsJSONStringAndAcceptNumbers", func(t *testing.T) {
+		content, _ := json.Marshal(domain.MustDecimal("12.5"))
+		assert.Equal(t, `"12.5"`, string(content))
+
+		var fromString, fromNumber domain.Decimal
+		assert.Nil(t, json.Unmarshal([]byte(`"11999.99"`), &fromString))
+		assert.Nil(t, json.Unmarshal([]byte(`11999.99`), &fromNumber))
+		assert.Equal(t, domain.MustDecimal("11999.99"), fromString)
+		assert.Equal(t, fromString, fromNumber)
+	})
+}
+
+func Test_WhenDecimalJSONQuotesUnbalanced_ShouldNotUnmarshal(t *testing.T) {
+	t.Run("WhenDecimalJSONQuotesUnbalanced_ShouldNotUnmarshal", func(t *testing.T) {
+		for _, content := range []string{`"12.5`, `12.5"`, `""12.5""`} {
+			var decimal domain.Decimal
+			assert.NotNil(t, decimal.UnmarshalJSON([]byte(content)), content)
+		}
+	})
+}
+
+func Test_WhenDecimalResultOutOfRange_ShouldPanic(t *testing.T) {
+	t.Run("WhenDecimalResultOutOfRange_ShouldPanic", func(t *testing.T) {
+		assert.Panics(t, func() { domain.NewDecimal(math.MaxInt64 / 1000) })
+		assert.Panics(t, func() { domain.Decimal(math.MaxInt64).RoundTo(0) })
+		assert.Equal(t, domain.MustDecimal("-3"), domain.MustDecimal("-2.5").RoundTo(0))
+	})
+}
+
+func Test_ShouldFormatMoneyWithCurrencyMinorUnits(t *testing.T) {
+	t.Run("ShouldFormatMoneyWithCurrencyMinorUnits", func(t *testing.T) {
+		assert.Equal(t, "12000.00", domain.MustMoney("12000", "USD").String())
+		assert.Equal(t, "500", domain.MustMoney("500", "JPY").String())
+		assert.Equal(t, "1.250", domain.MustMoney("1.25", "KWD").String())
+	})
+}
+
+func Test_WhenAmountTooPreciseOrCurrencyUnknown_ShouldNotCreateMoney(t *testing.T) {
+	t.Run("WhenAmountTooPreciseOrCurrencyUnknown_ShouldNotCreateMoney", func(t *testing.T) {
+		_, precisionErr := domain.NewMoney(domain.MustDecimal("10.001"), "USD")
+		_, yenErr := domain.NewMoney(domain.MustDecimal("10.5"), "JPY")
+		_, currencyErr := domain.NewMoney(domain.MustDecimal("10"), "XYZ")
+		assert.Equal(t, "must not have more than 2 decimal places for USD", precisionErr.Error())
+		assert.NotNil(t, yenErr)
+		assert.NotNil(t, currencyErr)
+	})
+}
+
+func Test_ShouldRoundPercentHalfAwayFromZeroToMinorUnit(t *testing.T) {
+	t.Run("ShouldRoundPercentHalfAwayFromZeroToMinorUnit", func(t *testing.T) {
+		assert.Equal(t, domain.MustMoney("0.13", "USD"), domain.MustMoney("0.25", "USD").Percent(domain.MustDecimal("50")))
+		assert.Equal(t, domain.MustMoney("1667", "JPY"), domain.MustMoney("3333", "JPY").Percent(domain.MustDecimal("50")))
+		assert.Equal(t, domain.MustMoney("1.23", "USD"), domain.MustMoney("9.87", "USD").Percent(domain.MustDecimal("12.5")))
+	})
+}
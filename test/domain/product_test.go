@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"Service-schema/domain"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_ShouldComputeDiscountAmountAndDiscountedPrice(t *testing.T) {
+	t.Run("ShouldComputeDiscountAmountAndDiscountedPrice", func(t *testing.T) {
+		product := domain.Product{Name: "RTX 5090", Price: domain.MustMoney("10000", "USD"), Discount: domain.MustDecimal("20"), Store: "Nvidia"}
+		assert.Equal(t, domain.MustMoney("2000", "USD"), product.DiscountAmount())
+		assert.Equal(t, domain.MustMoney("8000", "USD"), product.DiscountedPrice())
+	})
+}
+
+func Test_WhenDiscountHasFractionOfCent_ShouldRoundHalfAwayFromZero(t *testing.T) {
+	t.Run("WhenDiscountHasFractionOfCent_ShouldRoundHalfAwayFromZero", func(t *testing.T) {
+		product := domain.Product{Name: "Pencil", Price: domain.MustMoney("10.05", "USD"), Discount: domain.MustDecimal("50"), Store: "Amazon"}
+		assert.Equal(t, domain.MustMoney("5.03", "USD"), product.DiscountAmount())
+		assert.Equal(t, domain.MustMoney("5.02", "USD"), product.DiscountedPrice())
+	})
+}
+
+func Test_WhenNoDiscount_ShouldKeepPrice(t *testing.T) {
+	t.Run("WhenNoDiscount_ShouldKeepPrice", func(t *testing.T) {
+		product := domain.Product{Name: "Iphone 17", Price: domain.MustMoney("3000", "USD"), Discount: domain.MustDecimal("0"), Store: "Apple"}
+		assert.Equal(t, domain.MustMoney("0", "USD"), product.DiscountAmount())
+		assert.Equal(t, domain.MustMoney("3000", "USD"), product.DiscountedPrice())
+	})
+}
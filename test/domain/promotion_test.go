@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"Service-schema/domain"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+var promotionStart = time.Date(2025, time.November, 28, 0, 0, 0, 0, time.UTC)
+
+func promotion(id int64, promotionType domain.PromotionType, value string, stackable bool) domain.Promotion {
+	return domain.Promotion{
+		Id:        id,
+		Name:      "Promotion",
+		Type:      promotionType,
+		Value:     domain.MustDecimal(value),
+		Currency:  "USD",
+		Stackable: stackable,
+		StartsAt:  promotionStart,
+		EndsAt:    promotionStart.AddDate(0, 0, 7),
+	}
+}
+
+func Test_WhenPromotionsStackable_ShouldApplyThemAfterProductDiscount(t *testing.T) {
+	t.Run("WhenPromotionsStackable_ShouldApplyThemAfterProductDiscount", func(t *testing.T) {
+		product := domain.Product{Id: 1, Price: domain.MustMoney("1000", "USD"), Discount: domain.MustDecimal("10"), StoreId: 1}
+		promotions := []domain.Promotion{
+			promotion(1, domain.PercentagePromotion, "10", true),
+			promotion(2, domain.FixedAmountPromotion, "50", true),
+		}
+
+		resolvedProduct := product.WithPromotionsAt(promotions, promotionStart)
+
+		assert.Equal(t, 2, len(resolvedProduct.AppliedPromotions))
+		assert.Equal(t, domain.MustMoney("760", "USD"), resolvedProduct.DiscountedPrice())
+	})
+}
+
+func Test_WhenExclusivePromotionIsBetter_ShouldApplyOnlyIt(t *testing.T) {
+	t.Run("WhenExclusivePromotionIsBetter_ShouldApplyOnlyIt", func(t *testing.T) {
+		product := domain.Product{Id: 1, Price: domain.MustMoney("1000", "USD"), Discount: domain.MustDecimal("10"), StoreId: 1}
+		promotions := []domain.Promotion{
+			promotion(1, domain.PercentagePromotion, "10", true),
+			promotion(2, domain.PercentagePromotion, "25", false),
+			promotion(3, domain.PercentagePromotion, "15", false),
+		}
+
+		resolvedProduct := product.WithPromotionsAt(promotions, promotionStart)
+
+		assert.Equal(t, []int64{2}, []int64{resolvedProduct.AppliedPromotions[0].Id})
+		assert.Equal(t, domain.MustMoney("750", "USD"), resolvedProduct.DiscountedPrice())
+	})
+}
+
+func Test_WhenPromotionOutOfScope_ShouldNotApplyIt(t *testing.T) {
+	t.Run("WhenPromotionOutOfScope_ShouldNotApplyIt", func(t *testing.T) {
+		otherStoreId := int64(2)
+		product := domain.Product{Id: 1, Price: domain.MustMoney("1000", "EUR"), StoreId: 1}
+		otherStorePromotion := promotion(1, domain.PercentagePromotion, "10", true)
+		otherStorePromotion.StoreId = &otherStoreId
+		otherCurrencyPromotion := promotion(2, domain.FixedAmountPromotion, "50", true)
+		expiredPromotion := promotion(3, domain.PercentagePromotion, "10", true)
+
+		resolvedProduct := product.WithPromotionsAt([]domain.Promotion{otherStorePromotion, otherCurrencyPromotion}, promotionStart)
+		expiredProduct := product.WithPromotionsAt([]domain.Promotion{expiredPromotion}, promotionStart.AddDate(0, 0, 7))
+
+		assert.Empty(t, resolvedProduct.AppliedPromotions)
+		assert.Empty(t, expiredProduct.AppliedPromotions)
+	})
+}
+
+func Test_WhenFixedAmountExceedsPrice_ShouldNotGoBelowZero(t *testing.T) {
+	t.Run("WhenFixedAmountExceedsPrice_ShouldNotGoBelowZero", func(t *testing.T) {
+		product := domain.Product{Id: 1, Price: domain.MustMoney("30", "USD"), StoreId: 1}
+		resolvedProduct := product.WithPromotionsAt([]domain.Promotion{promotion(1, domain.FixedAmountPromotion, "50", false)}, promotionStart)
+		assert.Equal(t, domain.MustMoney("0", "USD"), resolvedProduct.DiscountedPrice())
+	})
+}
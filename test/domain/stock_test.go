@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"Service-schema/domain"
+	"Service-schema/domain/domainerror"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func Test_ShouldReserveCommitAndReleaseStock(t *testing.T) {
+	t.Run("ShouldReserveCommitAndReleaseStock", func(t *testing.T) {
+		stockLevel := domain.StockLevel{ProductId: 1, OnHand: 10}
+
+		stockLevel, _ = stockLevel.Apply(domain.ReserveStock, 6)
+		assert.Equal(t, int64(4), stockLevel.Available())
+
+		stockLevel, _ = stockLevel.Apply(domain.CommitStock, 4)
+		assert.Equal(t, domain.StockLevel{ProductId: 1, OnHand: 6, Reserved: 2}, stockLevel)
+
+		stockLevel, _ = stockLevel.Apply(domain.ReleaseStock, 2)
+		assert.Equal(t, domain.StockLevel{ProductId: 1, OnHand: 6, Reserved: 0}, stockLevel)
+	})
+}
+
+func Test_WhenReservationExceedsAvailable_ShouldRejectReservation(t *testing.T) {
+	t.Run("WhenReservationExceedsAvailable_ShouldRejectReservation", func(t *testing.T) {
+		stockLevel := domain.StockLevel{OnHand: 5, Reserved: 3}
+		_, err := stockLevel.Apply(domain.ReserveStock, 3)
+		assert.ErrorIs(t, err, domainerror.ErrConflict)
+		assert.Equal(t, "Cannot reserve 3 units, only 2 are available", err.Error())
+	})
+}
+
+func Test_WhenAdjustmentRemovesReservedUnits_ShouldRejectAdjustment(t *testing.T) {
+	t.Run("WhenAdjustmentRemovesReservedUnits_ShouldRejectAdjustment", func(t *testing.T) {
+		stockLevel := domain.StockLevel{OnHand: 5, Reserved: 3}
+		_, err := stockLevel.Apply(domain.AdjustStock, -3)
+		assert.ErrorIs(t, err, domainerror.ErrConflict)
+
+		stockLevel, err = stockLevel.Apply(domain.AdjustStock, -2)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), stockLevel.OnHand)
+	})
+}
+
+func Test_WhenQuantityNotPositive_ShouldRejectOperation(t *testing.T) {
+	t.Run("WhenQuantityNotPositive_ShouldRejectOperation", func(t *testing.T) {
+		stockLevel := domain.StockLevel{OnHand: 5}
+		_, reserveErr := stockLevel.Apply(domain.ReserveStock, -1)
+		_, adjustErr := stockLevel.Apply(domain.AdjustStock, 0)
+		assert.ErrorIs(t, reserveErr, domainerror.ErrValidation)
+		assert.ErrorIs(t, adjustErr, domainerror.ErrValidation)
+	})
+}
+
+func Test_WhenAdjustmentOverflowsOnHand_ShouldRejectAdjustment(t *testing.T) {
+	t.Run("WhenAdjustmentOverflowsOnHand_ShouldRejectAdjustment", func(t *testing.T) {
+		stockLevel := domain.StockLevel{OnHand: 5, Reserved: 3}
+		_, overflowErr := stockLevel.Apply(domain.AdjustStock, math.MaxInt64-4)
+		_, underflowErr := stockLevel.Apply(domain.AdjustStock, math.MinInt64)
+		assert.ErrorIs(t, overflowErr, domainerror.ErrValidation)
+		assert.Equal(t, "Cannot add 9223372036854775803 units, stock on hand would exceed 9223372036854775807", overflowErr.Error())
+		assert.ErrorIs(t, underflowErr, domainerror.ErrValidation)
+
+		stockLevel, err := stockLevel.Apply(domain.AdjustStock, math.MaxInt64-5)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(math.MaxInt64), stockLevel.OnHand)
+	})
+}
@@ -0,0 +1,38 @@
+package infrastructure
+
+import (
+	"Service-schema/core/postgresql"
+	"Service-schema/persistence/migrations"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDatabaseHealthChecksAreUp(t *testing.T) {
+	ctx := context.Background()
+	t.Run("TestDatabaseHealthChecksAreUp", func(t *testing.T) {
+		migrator, migratorErr := migrations.NewMigrator(dbPool, logger)
+		assert.Nil(t, migratorErr)
+
+		pending, pendingErr := migrator.Pending(ctx)
+
+		assert.Nil(t, pendingErr)
+		assert.Empty(t, pending)
+		assert.Nil(t, postgresql.NewPingChecker(dbPool).Check(ctx))
+		assert.Nil(t, migrations.NewHealthChecker(migrator).Check(ctx))
+		assert.Nil(t, postgresql.NewPoolSaturationChecker(dbPool, 100).Check(ctx))
+	})
+}
+
+func TestPoolSaturationCheckerFailsWhenConnectionsAreCheckedOut(t *testing.T) {
+	ctx := context.Background()
+	t.Run("TestPoolSaturationCheckerFailsWhenConnectionsAreCheckedOut", func(t *testing.T) {
+		conn, acquireErr := dbPool.Acquire(ctx)
+		assert.Nil(t, acquireErr)
+		defer conn.Release()
+
+		checkErr := postgresql.NewPoolSaturationChecker(dbPool, 1).Check(ctx)
+
+		assert.ErrorContains(t, checkErr, "connections in use")
+	})
+}
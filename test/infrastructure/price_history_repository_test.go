@@ -0,0 +1,53 @@
+package infrastructure
+
+import (
+	"Service-schema/core/audit"
+	"Service-schema/domain"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestUpdatePriceRecordsPriceHistory(t *testing.T) {
+	ctx := context.Background()
+	setup(ctx, dbPool)
+	t.Run("TestUpdatePriceRecordsPriceHistory", func(t *testing.T) {
+		auditedCtx := audit.WithChange(ctx, audit.Change{Actor: "finance", Reason: "Supplier price increase"})
+		_ = productRepository.UpdatePrice(auditedCtx, 3, domain.MustMoney("12000", "USD"))
+		name := "RTX 5090 Ti"
+		_ = productRepository.Update(ctx, 3, 0, domain.ProductChanges{Name: &name})
+
+		priceChanges, _ := priceHistoryRepository.GetPriceHistory(ctx, domain.PriceHistoryQuery{ProductId: 3})
+		assert.Equal(t, 1, len(priceChanges))
+		assert.Equal(t, domain.MustMoney("10000", "USD"), priceChanges[0].OldPrice)
+		assert.Equal(t, domain.MustMoney("12000", "USD"), priceChanges[0].NewPrice)
+		assert.Equal(t, domain.MustDecimal("20"), priceChanges[0].NewDiscount)
+		assert.Equal(t, "finance", priceChanges[0].Actor)
+		assert.Equal(t, "Supplier price increase", priceChanges[0].Reason)
+
+		to := priceChanges[0].ChangedAt.Add(-time.Second)
+		earlierChanges, _ := priceHistoryRepository.GetPriceHistory(ctx, domain.PriceHistoryQuery{ProductId: 3, To: &to})
+		assert.Empty(t, earlierChanges)
+	})
+	clear(ctx, dbPool)
+}
+
+func TestPurgeDeletedProductKeepsPriceHistory(t *testing.T) {
+	ctx := context.Background()
+	setup(ctx, dbPool)
+	t.Run("TestPurgeDeletedProductKeepsPriceHistory", func(t *testing.T) {
+		_ = productRepository.UpdatePrice(ctx, 3, domain.MustMoney("12000", "USD"))
+		_ = productRepository.DeleteById(ctx, 3, 0)
+		purgedCount, _ := productRepository.PurgeDeleted(ctx, time.Now().Add(time.Minute))
+
+		hiddenChanges, _ := priceHistoryRepository.GetPriceHistory(ctx, domain.PriceHistoryQuery{ProductId: 3})
+		priceChanges, err := priceHistoryRepository.GetPriceHistory(ctx, domain.PriceHistoryQuery{ProductId: 3, IncludeDeleted: true})
+		assert.Nil(t, err)
+		assert.Empty(t, hiddenChanges)
+		assert.Equal(t, int64(1), purgedCount)
+		assert.Equal(t, 1, len(priceChanges))
+		assert.Equal(t, "RTX 5090", priceChanges[0].ProductName)
+	})
+	clear(ctx, dbPool)
+}
@@ -1,33 +1,64 @@
 package infrastructure
 
 import (
+	"Service-schema/core/app"
+	"Service-schema/core/logging"
 	"Service-schema/core/postgresql"
 	"Service-schema/domain"
+	"Service-schema/domain/domainerror"
 	"Service-schema/persistence"
+	"Service-schema/persistence/migrations"
 	"context"
+	"errors"
 	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/stretchr/testify/assert"
+	"io"
+	"log/slog"
 	"os"
 	"testing"
+	"time"
 )
 
 var productRepository persistence.IProductRepository
+var storeRepository persistence.IStoreRepository
+var categoryRepository persistence.ICategoryRepository
+var stockRepository persistence.IStockRepository
+var priceHistoryRepository persistence.IPriceHistoryRepository
+var promotionRepository persistence.IPromotionRepository
+var transactionManager persistence.ITransactionManager
 var dbPool *pgxpool.Pool
+var logger *slog.Logger
 
 func TestMain(m *testing.M) {
 	ctx := context.Background()
 
-	dbPool = postgresql.GetConnectionPool(ctx, postgresql.Config{
-		Host:                  "localhost",
-		Port:                  "6432",
-		UserName:              "postgres",
-		Password:              "password",
-		DbName:                "product_service",
-		MaxConnections:        "10",
-		MaxConnectionIdleTime: "30s",
-	})
+	configurationManager, configurationErr := app.NewConfigurationManager(nil)
+	if configurationErr != nil {
+		panic(configurationErr)
+	}
+
+	logger = logging.NewLogger(configurationManager.LoggingConfig, io.Discard)
+	var connectErr error
+	dbPool, connectErr = postgresql.GetConnectionPool(ctx, configurationManager.PostgresqlConfig, nil, logger)
+	if connectErr != nil {
+		panic(connectErr)
+	}
 
-	productRepository = persistence.NewProductRepository(dbPool)
+	migrator, migratorErr := migrations.NewMigrator(dbPool, logger)
+	if migratorErr != nil {
+		panic(migratorErr)
+	}
+	if _, migrateErr := migrator.Up(ctx); migrateErr != nil {
+		panic(migrateErr)
+	}
+
+	productRepository = persistence.NewProductRepository(dbPool, configurationManager.PostgresqlConfig.QueryTimeouts, logger)
+	storeRepository = persistence.NewStoreRepository(dbPool, configurationManager.PostgresqlConfig.QueryTimeouts, logger)
+	categoryRepository = persistence.NewCategoryRepository(dbPool, configurationManager.PostgresqlConfig.QueryTimeouts, logger)
+	stockRepository = persistence.NewStockRepository(dbPool, configurationManager.PostgresqlConfig.QueryTimeouts, logger)
+	priceHistoryRepository = persistence.NewPriceHistoryRepository(dbPool, configurationManager.PostgresqlConfig.QueryTimeouts, logger)
+	promotionRepository = persistence.NewPromotionRepository(dbPool, configurationManager.PostgresqlConfig.QueryTimeouts, logger)
+	transactionManager = persistence.NewTransactionManager(dbPool, configurationManager.PostgresqlConfig.TransactionOptions, logger)
 	exitCode := m.Run()
 	os.Exit(exitCode)
 }
@@ -39,84 +70,153 @@ func TestGetAllProducts(t *testing.T) {
 		{
 			Id:       1,
 			Name:     `360Hz 24" Monitor`,
-			Price:    2000.0,
-			Discount: 12.0,
+			Price:    domain.MustMoney("2000", "USD"),
+			Discount: domain.MustDecimal("12"),
+			StoreId:  1,
 			Store:    "BENQ",
+			Version:  1,
 		},
 		{
 			Id:       2,
 			Name:     `EC-2B Mouse`,
-			Price:    1200.0,
-			Discount: 10.0,
+			Price:    domain.MustMoney("1200", "USD"),
+			Discount: domain.MustDecimal("10"),
+			StoreId:  2,
 			Store:    "Zowie",
+			Version:  1,
 		},
 		{
 			Id:       3,
 			Name:     `RTX 5090`,
-			Price:    10000.0,
-			Discount: 20.0,
+			Price:    domain.MustMoney("10000", "USD"),
+			Discount: domain.MustDecimal("20"),
+			StoreId:  3,
 			Store:    "Nvidia",
+			Version:  1,
 		},
 		{
 			Id:       4,
 			Name:     `Iphone 17`,
-			Price:    3000.0,
-			Discount: 0.0,
+			Price:    domain.MustMoney("3000", "USD"),
+			Discount: domain.MustDecimal("0"),
+			StoreId:  4,
 			Store:    "Apple",
+			Version:  1,
 		},
 	}
 	t.Run("TestGetAllProducts", func(t *testing.T) {
-		actualProducts := productRepository.GetAllProducts()
+		actualProducts := getAllProducts(ctx)
 		assert.Equal(t, 4, len(actualProducts))
 		assert.Equal(t, expected, actualProducts)
 	})
 	clear(ctx, dbPool)
 }
 
-func TestGetAllProductsByStoreName(t *testing.T) {
+func TestGetAllProductsByStoreId(t *testing.T) {
 	ctx := context.Background()
 	setup(ctx, dbPool)
 	expected := []domain.Product{
 		{
 			Id:       3,
 			Name:     `RTX 5090`,
-			Price:    10000.0,
-			Discount: 20.0,
+			Price:    domain.MustMoney("10000", "USD"),
+			Discount: domain.MustDecimal("20"),
+			StoreId:  3,
 			Store:    "Nvidia",
+			Version:  1,
 		},
 	}
-	t.Run("TestGetAllProductsByStoreName", func(t *testing.T) {
-		actualProducts := productRepository.GetAllProductsByStoreName("Nvidia")
+	t.Run("TestGetAllProductsByStoreId", func(t *testing.T) {
+		actualProducts := getAllProductsByQuery(ctx, domain.ProductQuery{StoreId: 3})
 		assert.Equal(t, 1, len(actualProducts))
 		assert.Equal(t, expected, actualProducts)
 	})
 	clear(ctx, dbPool)
 }
 
+func TestGetAllProductsByCategoryIncludingDescendants(t *testing.T) {
+	ctx := context.Background()
+	setup(ctx, dbPool)
+	t.Run("TestGetAllProductsByCategoryIncludingDescendants", func(t *testing.T) {
+		electronics, _ := categoryRepository.Add(ctx, domain.Category{Name: "Electronics"})
+		computers, _ := categoryRepository.Add(ctx, domain.Category{Name: "Computers", ParentId: &electronics.Id})
+		monitors, _ := categoryRepository.Add(ctx, domain.Category{Name: "Monitors", ParentId: &computers.Id})
+		phones, _ := categoryRepository.Add(ctx, domain.Category{Name: "Phones", ParentId: &electronics.Id})
+		_ = categoryRepository.SetProductCategories(ctx, 1, []int64{monitors.Id})
+		_ = categoryRepository.SetProductCategories(ctx, 3, []int64{computers.Id})
+		_ = categoryRepository.SetProductCategories(ctx, 4, []int64{phones.Id})
+
+		assert.Equal(t, 3, len(getAllProductsByQuery(ctx, domain.ProductQuery{CategoryId: electronics.Id})))
+		assert.Equal(t, 2, len(getAllProductsByQuery(ctx, domain.ProductQuery{CategoryId: computers.Id})))
+
+		_, _ = categoryRepository.Update(ctx, domain.Category{Id: monitors.Id, Name: "Monitors", ParentId: &phones.Id})
+		assert.Equal(t, 1, len(getAllProductsByQuery(ctx, domain.ProductQuery{CategoryId: computers.Id})))
+		assert.Equal(t, 2, len(getAllProductsByQuery(ctx, domain.ProductQuery{CategoryId: phones.Id})))
+	})
+	clear(ctx, dbPool)
+}
+
+func TestGetProductsWithCursorAndFilters(t *testing.T) {
+	ctx := context.Background()
+	setup(ctx, dbPool)
+	minPrice := domain.MustDecimal("1500")
+	t.Run("TestGetProductsWithCursorAndFilters", func(t *testing.T) {
+		query := domain.ProductQuery{MinPrice: &minPrice, SortField: domain.SortByPrice, SortDirection: domain.Descending, Limit: 2}
+		firstPage, _ := productRepository.GetProducts(ctx, query)
+		assert.Equal(t, int64(3), firstPage.TotalCount)
+		assert.Equal(t, 2, len(firstPage.Items))
+		assert.Equal(t, int64(3), firstPage.Items[0].Id)
+		assert.Equal(t, int64(4), firstPage.Items[1].Id)
+
+		cursor, _ := domain.DecodeProductCursor(firstPage.NextCursor)
+		query.Cursor = &cursor
+		secondPage, _ := productRepository.GetProducts(ctx, query)
+		assert.Equal(t, 1, len(secondPage.Items))
+		assert.Equal(t, int64(1), secondPage.Items[0].Id)
+		assert.Empty(t, secondPage.NextCursor)
+	})
+	clear(ctx, dbPool)
+}
+
 func TestAddProduct(t *testing.T) {
 	ctx := context.Background()
 	expected := []domain.Product{
 		{
 			Id:       1,
 			Name:     "Samsung Galaxy A72",
-			Price:    5000.0,
-			Discount: 0.0,
+			Price:    domain.MustMoney("5000", "USD"),
+			Discount: domain.MustDecimal("0"),
+			StoreId:  1,
 			Store:    "Samsung",
+			Version:  1,
 		},
 	}
 	t.Run("TestAddProduct", func(t *testing.T) {
+		_, _ = storeRepository.Add(ctx, domain.Store{Name: "Samsung"})
 		newProduct := domain.Product{
 			Name:     "Samsung Galaxy A72",
-			Price:    5000.0,
-			Discount: 0.0,
+			Price:    domain.MustMoney("5000", "USD"),
+			Discount: domain.MustDecimal("0"),
+			StoreId:  1,
 			Store:    "Samsung",
 		}
 
-		_ = productRepository.Add(newProduct)
+		addedProduct, _ := productRepository.Add(ctx, newProduct)
 
-		products := productRepository.GetAllProducts()
+		products := getAllProducts(ctx)
 		assert.Equal(t, 1, len(products))
 		assert.Equal(t, expected, products)
+		assert.Equal(t, expected[0], addedProduct)
+	})
+	clear(ctx, dbPool)
+}
+
+func TestAddProductWithUnknownStore(t *testing.T) {
+	ctx := context.Background()
+	t.Run("TestAddProductWithUnknownStore", func(t *testing.T) {
+		_, err := productRepository.Add(ctx, domain.Product{Name: "Pencil", Price: domain.MustMoney("200", "USD"), StoreId: 10})
+		assert.ErrorIs(t, err, domainerror.ErrValidation)
+		assert.Equal(t, "Store not found with id 10", err.Error())
 	})
 	clear(ctx, dbPool)
 }
@@ -127,13 +227,15 @@ func TestGetById(t *testing.T) {
 	expectedProduct := domain.Product{
 		Id:       3,
 		Name:     `RTX 5090`,
-		Price:    10000.0,
-		Discount: 20.0,
+		Price:    domain.MustMoney("10000", "USD"),
+		Discount: domain.MustDecimal("20"),
+		StoreId:  3,
 		Store:    "Nvidia",
+		Version:  1,
 	}
 	t.Run("TestGetById", func(t *testing.T) {
-		actualProduct, _ := productRepository.GetById(3)
-		_, err := productRepository.GetById(5)
+		actualProduct, _ := productRepository.GetById(ctx, 3)
+		_, err := productRepository.GetById(ctx, 5)
 		assert.Equal(t, expectedProduct, actualProduct)
 		assert.Equal(t, "Product not found with id 5", err.Error())
 	})
@@ -144,8 +246,8 @@ func TestDeleteById(t *testing.T) {
 	ctx := context.Background()
 	setup(ctx, dbPool)
 	t.Run("TestDeleteById", func(t *testing.T) {
-		_ = productRepository.DeleteById(3)
-		products := productRepository.GetAllProducts()
+		_ = productRepository.DeleteById(ctx, 3, 0)
+		products := getAllProducts(ctx)
 		assert.Equal(t, 3, len(products))
 	})
 	clear(ctx, dbPool)
@@ -155,10 +257,37 @@ func TestUpdateProductPrice(t *testing.T) {
 	ctx := context.Background()
 	setup(ctx, dbPool)
 	t.Run("TestUpdateProductPrice", func(t *testing.T) {
-		_ = productRepository.UpdatePrice(3, 12000.0)
-		err := productRepository.UpdatePrice(5, 5000)
-		actualProduct, _ := productRepository.GetById(3)
-		assert.Equal(t, float32(12000.0), actualProduct.Price)
+		_ = productRepository.UpdatePrice(ctx, 3, domain.MustMoney("12000", "USD"))
+		err := productRepository.UpdatePrice(ctx, 5, domain.MustMoney("5000", "USD"))
+		actualProduct, _ := productRepository.GetById(ctx, 3)
+		assert.Equal(t, domain.MustMoney("12000", "USD"), actualProduct.Price)
+		assert.Equal(t, "Product not found with id 5", err.Error())
+	})
+	clear(ctx, dbPool)
+}
+
+func getAllProducts(ctx context.Context) []domain.Product {
+	return getAllProductsByQuery(ctx, domain.ProductQuery{})
+}
+
+func getAllProductsByQuery(ctx context.Context, query domain.ProductQuery) []domain.Product {
+	query.SortField = domain.SortById
+	query.SortDirection = domain.Ascending
+	query.Limit = 100
+	productPage, _ := productRepository.GetProducts(ctx, query)
+	return productPage.Items
+}
+
+func TestUpdateProduct(t *testing.T) {
+	ctx := context.Background()
+	setup(ctx, dbPool)
+	t.Run("TestUpdateProduct", func(t *testing.T) {
+		name := "RTX 5090 Ti"
+		discount := domain.MustDecimal("25")
+		_ = productRepository.Update(ctx, 3, 0, domain.ProductChanges{Name: &name, Discount: &discount})
+		err := productRepository.Update(ctx, 5, 0, domain.ProductChanges{Name: &name})
+		actualProduct, _ := productRepository.GetById(ctx, 3)
+		assert.Equal(t, domain.Product{Id: 3, Name: "RTX 5090 Ti", Price: domain.MustMoney("10000", "USD"), Discount: domain.MustDecimal("25"), StoreId: 3, Store: "Nvidia", Version: 2}, actualProduct)
 		assert.Equal(t, "Product not found with id 5", err.Error())
 	})
 	clear(ctx, dbPool)
@@ -171,3 +300,138 @@ func setup(ctx context.Context, dbPool *pgxpool.Pool) {
 func clear(ctx context.Context, dbPool *pgxpool.Pool) {
 	TruncateTestData(ctx, dbPool)
 }
+
+func TestRestoreAndPurgeDeletedProduct(t *testing.T) {
+	ctx := context.Background()
+	setup(ctx, dbPool)
+	t.Run("TestRestoreAndPurgeDeletedProduct", func(t *testing.T) {
+		_ = productRepository.DeleteById(ctx, 3, 0)
+		_, getErr := productRepository.GetById(ctx, 3)
+		deletedProducts := getAllProductsByQuery(ctx, domain.ProductQuery{IncludeDeleted: true})
+		assert.Equal(t, "Product not found with id 3", getErr.Error())
+		assert.Equal(t, 4, len(deletedProducts))
+		assert.NotNil(t, deletedProducts[2].DeletedAt)
+
+		restoredProduct, _ := productRepository.Restore(ctx, 3)
+		assert.Nil(t, restoredProduct.DeletedAt)
+		assert.Equal(t, 4, len(getAllProducts(ctx)))
+
+		_ = productRepository.DeleteById(ctx, 3, 0)
+		retainedCount, _ := productRepository.PurgeDeleted(ctx, time.Now().Add(-time.Hour))
+		purgedCount, _ := productRepository.PurgeDeleted(ctx, time.Now().Add(time.Minute))
+		_, restoreErr := productRepository.Restore(ctx, 3)
+		assert.Equal(t, int64(0), retainedCount)
+		assert.Equal(t, int64(1), purgedCount)
+		assert.Equal(t, "Product not found with id 3", restoreErr.Error())
+	})
+	clear(ctx, dbPool)
+}
+
+func TestUpdateWithStaleVersion(t *testing.T) {
+	ctx := context.Background()
+	setup(ctx, dbPool)
+	t.Run("TestUpdateWithStaleVersion", func(t *testing.T) {
+		name := "RTX 5090 Ti"
+		staleName := "RTX 5090 Super"
+		_ = productRepository.Update(ctx, 3, 1, domain.ProductChanges{Name: &name})
+		updateErr := productRepository.Update(ctx, 3, 1, domain.ProductChanges{Name: &staleName})
+		deleteErr := productRepository.DeleteById(ctx, 3, 1)
+		actualProduct, _ := productRepository.GetById(ctx, 3)
+		assert.ErrorIs(t, updateErr, domainerror.ErrConflict)
+		assert.ErrorIs(t, deleteErr, domainerror.ErrConflict)
+		assert.Equal(t, "RTX 5090 Ti", actualProduct.Name)
+		assert.Equal(t, int64(2), actualProduct.Version)
+	})
+	clear(ctx, dbPool)
+}
+
+func TestAddAllProducts(t *testing.T) {
+	ctx := context.Background()
+	setup(ctx, dbPool)
+	t.Run("TestAddAllProducts", func(t *testing.T) {
+		newProducts, _ := productRepository.AddAll(ctx, []domain.Product{
+			{Name: "Pencil", Price: domain.MustMoney("200", "USD"), StoreId: 4, Store: "Apple"},
+			{Name: "Eraser", Price: domain.MustMoney("150.5", "EUR"), Discount: domain.MustDecimal("5"), StoreId: 4, Store: "Apple"},
+		})
+		_, storeErr := productRepository.AddAll(ctx, []domain.Product{{Name: "Ruler", Price: domain.MustMoney("300", "USD"), StoreId: 10}})
+
+		actualProduct, _ := productRepository.GetById(ctx, newProducts[1].Id)
+		assert.Equal(t, 2, len(newProducts))
+		assert.Equal(t, newProducts[1], actualProduct)
+		assert.Equal(t, 6, len(getAllProducts(ctx)))
+		assert.ErrorIs(t, storeErr, domainerror.ErrValidation)
+	})
+	clear(ctx, dbPool)
+}
+
+func TestAddAllRejectedBatchKeepsTransactionUsable(t *testing.T) {
+	ctx := context.Background()
+	setup(ctx, dbPool)
+	t.Run("TestAddAllRejectedBatchKeepsTransactionUsable", func(t *testing.T) {
+		var storeErr error
+		txErr := transactionManager.WithinTransaction(ctx, func(ctx context.Context) error {
+			_, storeErr = productRepository.AddAll(ctx, []domain.Product{
+				{Name: "Pencil", Price: domain.MustMoney("200", "USD"), StoreId: 4},
+				{Name: "Ruler", Price: domain.MustMoney("300", "USD"), StoreId: 10},
+			})
+			_, addErr := productRepository.AddAll(ctx, []domain.Product{{Name: "Pencil", Price: domain.MustMoney("200", "USD"), StoreId: 4}})
+			return addErr
+		})
+
+		assert.ErrorIs(t, storeErr, domainerror.ErrValidation)
+		assert.Nil(t, txErr)
+		assert.Equal(t, 5, len(getAllProducts(ctx)))
+	})
+	clear(ctx, dbPool)
+}
+
+func TestExportProducts(t *testing.T) {
+	ctx := context.Background()
+	setup(ctx, dbPool)
+	minPrice := domain.MustDecimal("1500")
+	t.Run("TestExportProducts", func(t *testing.T) {
+		var exportedIds []int64
+		query := domain.ProductQuery{MinPrice: &minPrice, SortField: domain.SortByPrice, SortDirection: domain.Descending, Limit: 1}
+		exportErr := productRepository.ExportProducts(ctx, query, func(product domain.Product) error {
+			exportedIds = append(exportedIds, product.Id)
+			return nil
+		})
+		assert.Nil(t, exportErr)
+		assert.Equal(t, []int64{3, 4, 1}, exportedIds)
+
+		writeErr := errors.New("client went away")
+		exportErr = productRepository.ExportProducts(ctx, domain.ProductQuery{SortField: domain.SortById}, func(product domain.Product) error {
+			return writeErr
+		})
+		assert.ErrorIs(t, exportErr, writeErr)
+	})
+	clear(ctx, dbPool)
+}
+
+func TestSearchProducts(t *testing.T) {
+	ctx := context.Background()
+	setup(ctx, dbPool)
+	t.Run("TestSearchProducts", func(t *testing.T) {
+		_, _ = productRepository.Add(ctx, domain.Product{Name: "Apple Pencil", Price: domain.MustMoney("500", "USD"), StoreId: 4})
+		query := domain.ProductSearchQuery{Text: "apple", Limit: 10}
+		result, searchErr := productRepository.SearchProducts(ctx, query)
+		assert.Nil(t, searchErr)
+		assert.Equal(t, int64(2), result.TotalCount)
+		assert.Equal(t, "Apple Pencil", result.Hits[0].Product.Name)
+		assert.Equal(t, "<mark>Apple</mark> Pencil - <mark>Apple</mark>", result.Hits[0].Snippet)
+		assert.Greater(t, result.Hits[0].Rank, result.Hits[1].Rank)
+		assert.Equal(t, []domain.StoreFacet{{StoreId: 4, Store: "Apple", Count: 2}}, result.StoreFacets)
+
+		typoResult, _ := productRepository.SearchProducts(ctx, domain.ProductSearchQuery{Text: "Monitr", Limit: 10})
+		assert.Equal(t, 1, len(typoResult.Hits))
+		assert.Equal(t, int64(1), typoResult.Hits[0].Product.Id)
+
+		_ = productRepository.DeleteById(ctx, 4, 0)
+		query.StoreId = 1
+		storeResult, _ := productRepository.SearchProducts(ctx, query)
+		assert.Equal(t, int64(0), storeResult.TotalCount)
+		assert.Empty(t, storeResult.Hits)
+		assert.Equal(t, 1, len(storeResult.StoreFacets))
+	})
+	clear(ctx, dbPool)
+}
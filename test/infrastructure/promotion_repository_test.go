@@ -0,0 +1,32 @@
+package infrastructure
+
+import (
+	"Service-schema/domain"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestGetActivePromotions(t *testing.T) {
+	ctx := context.Background()
+	setup(ctx, dbPool)
+	t.Run("TestGetActivePromotions", func(t *testing.T) {
+		startsAt := time.Date(2025, time.November, 28, 0, 0, 0, 0, time.UTC)
+		nvidiaStoreId := int64(3)
+		appleStoreId := int64(4)
+		_, _ = promotionRepository.Add(ctx, domain.Promotion{Name: "Nvidia week", Type: domain.PercentagePromotion, Value: domain.MustDecimal("10"),
+			StoreId: &nvidiaStoreId, Stackable: true, StartsAt: startsAt, EndsAt: startsAt.AddDate(0, 0, 7)})
+		_, _ = promotionRepository.Add(ctx, domain.Promotion{Name: "Apple week", Type: domain.PercentagePromotion, Value: domain.MustDecimal("10"),
+			StoreId: &appleStoreId, Stackable: true, StartsAt: startsAt, EndsAt: startsAt.AddDate(0, 0, 7)})
+		_, _ = promotionRepository.Add(ctx, domain.Promotion{Name: "Next month", Type: domain.FixedAmountPromotion, Value: domain.MustDecimal("100"),
+			Currency: "USD", StartsAt: startsAt.AddDate(0, 1, 0), EndsAt: startsAt.AddDate(0, 2, 0)})
+
+		nvidiaProduct, _ := productRepository.GetById(ctx, 3)
+		activePromotions, _ := promotionRepository.GetActivePromotions(ctx, startsAt, []domain.Product{nvidiaProduct})
+
+		assert.Equal(t, 1, len(activePromotions))
+		assert.Equal(t, "Nvidia week", activePromotions[0].Name)
+	})
+	clear(ctx, dbPool)
+}
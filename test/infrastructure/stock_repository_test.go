@@ -0,0 +1,79 @@
+package infrastructure
+
+import (
+	"Service-schema/domain"
+	"Service-schema/domain/domainerror"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+func TestGetStockOfProductWithoutStockRow(t *testing.T) {
+	ctx := context.Background()
+	setup(ctx, dbPool)
+	t.Run("TestGetStockOfProductWithoutStockRow", func(t *testing.T) {
+		stockLevel, _ := stockRepository.GetByProductId(ctx, 3)
+		_, err := stockRepository.GetByProductId(ctx, 10)
+		assert.Equal(t, domain.StockLevel{ProductId: 3, StoreId: 3}, stockLevel)
+		assert.ErrorIs(t, err, domainerror.ErrNotFound)
+	})
+	clear(ctx, dbPool)
+}
+
+func TestConcurrentReservationsNeverOversell(t *testing.T) {
+	ctx := context.Background()
+	setup(ctx, dbPool)
+	t.Run("TestConcurrentReservationsNeverOversell", func(t *testing.T) {
+		_, _ = stockRepository.Update(ctx, 1, func(stockLevel domain.StockLevel) (domain.StockLevel, error) {
+			return stockLevel.Apply(domain.AdjustStock, 5)
+		})
+
+		var waitGroup sync.WaitGroup
+		var mutex sync.Mutex
+		rejected := 0
+		for range 8 {
+			waitGroup.Add(1)
+			go func() {
+				defer waitGroup.Done()
+				_, err := stockRepository.Update(ctx, 1, func(stockLevel domain.StockLevel) (domain.StockLevel, error) {
+					return stockLevel.Apply(domain.ReserveStock, 1)
+				})
+				if err != nil {
+					mutex.Lock()
+					rejected++
+					mutex.Unlock()
+				}
+			}()
+		}
+		waitGroup.Wait()
+
+		stockLevel, _ := stockRepository.GetByProductId(ctx, 1)
+		assert.Equal(t, int64(5), stockLevel.Reserved)
+		assert.Equal(t, 3, rejected)
+	})
+	clear(ctx, dbPool)
+}
+
+func TestSoftDeletedProductHasNoStockOrCategories(t *testing.T) {
+	ctx := context.Background()
+	setup(ctx, dbPool)
+	t.Run("TestSoftDeletedProductHasNoStockOrCategories", func(t *testing.T) {
+		_, _ = stockRepository.Update(ctx, 3, func(stockLevel domain.StockLevel) (domain.StockLevel, error) {
+			return stockLevel.Apply(domain.AdjustStock, 5)
+		})
+		_ = productRepository.DeleteById(ctx, 3, 0)
+
+		_, getErr := stockRepository.GetByProductId(ctx, 3)
+		storeStockLevels, _ := stockRepository.GetByStoreId(ctx, 3)
+		_, reserveErr := stockRepository.Update(ctx, 3, func(stockLevel domain.StockLevel) (domain.StockLevel, error) {
+			return stockLevel.Apply(domain.ReserveStock, 1)
+		})
+		setCategoriesErr := categoryRepository.SetProductCategories(ctx, 3, nil)
+		assert.ErrorIs(t, getErr, domainerror.ErrNotFound)
+		assert.Empty(t, storeStockLevels)
+		assert.ErrorIs(t, reserveErr, domainerror.ErrNotFound)
+		assert.ErrorIs(t, setCategoriesErr, domainerror.ErrNotFound)
+	})
+	clear(ctx, dbPool)
+}
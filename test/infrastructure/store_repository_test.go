@@ -0,0 +1,69 @@
+package infrastructure
+
+import (
+	"Service-schema/domain"
+	"Service-schema/domain/domainerror"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGetAllStores(t *testing.T) {
+	ctx := context.Background()
+	setup(ctx, dbPool)
+	t.Run("TestGetAllStores", func(t *testing.T) {
+		actualStores, _ := storeRepository.GetAllStores(ctx)
+		assert.Equal(t, []domain.Store{{Id: 1, Name: "BENQ"}, {Id: 2, Name: "Zowie"}, {Id: 3, Name: "Nvidia"}, {Id: 4, Name: "Apple"}}, actualStores)
+	})
+	clear(ctx, dbPool)
+}
+
+func TestAddStoreWithDuplicateName(t *testing.T) {
+	ctx := context.Background()
+	setup(ctx, dbPool)
+	t.Run("TestAddStoreWithDuplicateName", func(t *testing.T) {
+		_, err := storeRepository.Add(ctx, domain.Store{Name: "NVIDIA"})
+		assert.ErrorIs(t, err, domainerror.ErrConflict)
+	})
+	clear(ctx, dbPool)
+}
+
+func TestUpdateStore(t *testing.T) {
+	ctx := context.Background()
+	setup(ctx, dbPool)
+	t.Run("TestUpdateStore", func(t *testing.T) {
+		_, _ = storeRepository.Update(ctx, domain.Store{Id: 3, Name: "NVIDIA"})
+		_, err := storeRepository.Update(ctx, domain.Store{Id: 10, Name: "Samsung"})
+		actualProduct, _ := productRepository.GetById(ctx, 3)
+		assert.Equal(t, "NVIDIA", actualProduct.Store)
+		assert.Equal(t, "Store not found with id 10", err.Error())
+	})
+	clear(ctx, dbPool)
+}
+
+func TestDeleteStoreWithProducts(t *testing.T) {
+	ctx := context.Background()
+	setup(ctx, dbPool)
+	t.Run("TestDeleteStoreWithProducts", func(t *testing.T) {
+		err := storeRepository.DeleteById(ctx, 3)
+		_, getErr := storeRepository.GetById(ctx, 3)
+		assert.ErrorIs(t, err, domainerror.ErrConflict)
+		assert.Equal(t, "Store 3 still has products", err.Error())
+		assert.Nil(t, getErr)
+	})
+	clear(ctx, dbPool)
+}
+
+func TestDeleteStoreWithDeletedProducts(t *testing.T) {
+	ctx := context.Background()
+	setup(ctx, dbPool)
+	t.Run("TestDeleteStoreWithDeletedProducts", func(t *testing.T) {
+		_ = productRepository.DeleteById(ctx, 3, 0)
+		err := storeRepository.DeleteById(ctx, 3)
+		notFoundErr := storeRepository.DeleteById(ctx, 10)
+		assert.ErrorIs(t, err, domainerror.ErrConflict)
+		assert.Equal(t, "Store 3 still has deleted products awaiting purge", err.Error())
+		assert.ErrorIs(t, notFoundErr, domainerror.ErrNotFound)
+	})
+	clear(ctx, dbPool)
+}
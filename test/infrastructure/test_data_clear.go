@@ -7,10 +7,10 @@ import (
 )
 
 func TruncateTestData(ctx context.Context, dbPool *pgxpool.Pool) {
-	_, truncateResultErr := dbPool.Exec(ctx, "TRUNCATE products RESTART IDENTITY")
+	_, truncateResultErr := dbPool.Exec(ctx, "TRUNCATE promotions, price_history, stock_levels, product_categories, category_closure, categories, products, stores RESTART IDENTITY")
 	if truncateResultErr != nil {
 		log.Error(truncateResultErr)
 	} else {
-		log.Info("Products table truncated")
+		log.Info("Test tables truncated")
 	}
 }
@@ -7,14 +7,28 @@ import (
 	"github.com/labstack/gommon/log"
 )
 
-var INSERT_PRODUCTS = `INSERT INTO products (name, price, discount,store) 
-VALUES('360Hz 24" Monitor',2000.0, 12.0, 'BENQ'),
-('EC-2B Mouse',1200.0, 10.0, 'Zowie'),
-('RTX 5090',10000.0, 20.0, 'Nvidia'),
-('Iphone 17',3000.0, 0.0, 'Apple');
+var INSERT_STORES = `INSERT INTO stores (name)
+VALUES('BENQ'),
+('Zowie'),
+('Nvidia'),
+('Apple');
+`
+
+var INSERT_PRODUCTS = `INSERT INTO products (name, price, discount,store_id) 
+VALUES('360Hz 24" Monitor',2000.0, 12.0, 1),
+('EC-2B Mouse',1200.0, 10.0, 2),
+('RTX 5090',10000.0, 20.0, 3),
+('Iphone 17',3000.0, 0.0, 4);
 `
 
 func TestDataInitialize(ctx context.Context, dbPool *pgxpool.Pool) {
+	insertStoresResult, insertStoresErr := dbPool.Exec(ctx, INSERT_STORES)
+	if insertStoresErr != nil {
+		log.Error(insertStoresErr)
+	} else {
+		log.Info(fmt.Sprintf("Stores data created with %d rows", insertStoresResult.RowsAffected()))
+	}
+
 	insertProductsResult, insertProductsErr := dbPool.Exec(ctx, INSERT_PRODUCTS)
 	if insertProductsErr != nil {
 		log.Error(insertProductsErr)
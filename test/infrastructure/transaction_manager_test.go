@@ -0,0 +1,128 @@
+package infrastructure
+
+import (
+	"Service-schema/core/postgresql"
+	"Service-schema/domain"
+	"Service-schema/persistence"
+	"context"
+	"errors"
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"log/slog"
+	"testing"
+)
+
+func TestWithinTransactionRollsBackEveryRepositoryCall(t *testing.T) {
+	ctx := context.Background()
+	setup(ctx, dbPool)
+	t.Run("TestWithinTransactionRollsBackEveryRepositoryCall", func(t *testing.T) {
+		rollbackErr := errors.New("rollback")
+		txErr := transactionManager.WithinTransaction(ctx, func(ctx context.Context) error {
+			_, _ = storeRepository.Add(ctx, domain.Store{Name: "Samsung"})
+			_ = productRepository.UpdatePrice(ctx, 3, domain.MustMoney("12000", "USD"))
+			return rollbackErr
+		})
+
+		stores, _ := storeRepository.GetAllStores(ctx)
+		actualProduct, _ := productRepository.GetById(ctx, 3)
+		assert.ErrorIs(t, txErr, rollbackErr)
+		assert.Equal(t, 4, len(stores))
+		assert.Equal(t, domain.MustMoney("10000", "USD"), actualProduct.Price)
+	})
+	clear(ctx, dbPool)
+}
+
+func TestWithinTransactionCommitsNestedCalls(t *testing.T) {
+	ctx := context.Background()
+	setup(ctx, dbPool)
+	t.Run("TestWithinTransactionCommitsNestedCalls", func(t *testing.T) {
+		txErr := transactionManager.WithinTransaction(ctx, func(ctx context.Context) error {
+			_, _ = storeRepository.Add(ctx, domain.Store{Name: "Samsung"})
+			return transactionManager.WithinTransaction(ctx, func(ctx context.Context) error {
+				return productRepository.UpdatePrice(ctx, 3, domain.MustMoney("12000", "USD"))
+			})
+		})
+
+		stores, _ := storeRepository.GetAllStores(ctx)
+		actualProduct, _ := productRepository.GetById(ctx, 3)
+		assert.Nil(t, txErr)
+		assert.Equal(t, 5, len(stores))
+		assert.Equal(t, domain.MustMoney("12000", "USD"), actualProduct.Price)
+	})
+	clear(ctx, dbPool)
+}
+
+// updateAfterConcurrentChange reads product 3, which fixes the snapshot of the transaction, changes the product
+// outside of the transaction and then updates it inside, which cannot serialize above read committed.
+func updateAfterConcurrentChange(ctx context.Context) error {
+	if _, getErr := productRepository.GetById(ctx, 3); getErr != nil {
+		return getErr
+	}
+	if _, concurrentErr := dbPool.Exec(context.Background(), `UPDATE products SET name = name || '!' WHERE id = 3`); concurrentErr != nil {
+		return concurrentErr
+	}
+	return productRepository.UpdatePrice(ctx, 3, domain.MustMoney("12000", "USD"))
+}
+
+func newTransactionManager(isolationLevel string, maxRetries int) persistence.ITransactionManager {
+	return persistence.NewTransactionManager(dbPool, postgresql.TransactionOptions{IsolationLevel: isolationLevel, MaxRetries: maxRetries},
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestWithinTransactionRetriesSerializationFailure(t *testing.T) {
+	ctx := context.Background()
+	setup(ctx, dbPool)
+	t.Run("TestWithinTransactionRetriesSerializationFailure", func(t *testing.T) {
+		attempts := 0
+		txErr := newTransactionManager("serializable", 2).WithinTransaction(ctx, func(ctx context.Context) error {
+			attempts++
+			if attempts == 1 {
+				return updateAfterConcurrentChange(ctx)
+			}
+			return productRepository.UpdatePrice(ctx, 3, domain.MustMoney("12000", "USD"))
+		})
+
+		actualProduct, _ := productRepository.GetById(ctx, 3)
+		assert.Nil(t, txErr)
+		assert.Equal(t, 2, attempts)
+		assert.Equal(t, domain.MustMoney("12000", "USD"), actualProduct.Price)
+	})
+	clear(ctx, dbPool)
+}
+
+func TestWithinTransactionGivesUpAfterMaxRetries(t *testing.T) {
+	ctx := context.Background()
+	setup(ctx, dbPool)
+	t.Run("TestWithinTransactionGivesUpAfterMaxRetries", func(t *testing.T) {
+		attempts := 0
+		txErr := newTransactionManager("serializable", 2).WithinTransaction(ctx, func(ctx context.Context) error {
+			attempts++
+			return updateAfterConcurrentChange(ctx)
+		})
+
+		var pgErr *pgconn.PgError
+		actualProduct, _ := productRepository.GetById(ctx, 3)
+		assert.True(t, errors.As(txErr, &pgErr))
+		assert.Equal(t, "40001", pgErr.Code)
+		assert.Equal(t, 3, attempts)
+		assert.Equal(t, domain.MustMoney("10000", "USD"), actualProduct.Price)
+	})
+	clear(ctx, dbPool)
+}
+
+func TestWithinTransactionUsesConfiguredIsolationLevel(t *testing.T) {
+	ctx := context.Background()
+	setup(ctx, dbPool)
+	t.Run("TestWithinTransactionUsesConfiguredIsolationLevel", func(t *testing.T) {
+		attempts := 0
+		txErr := newTransactionManager("read committed", 2).WithinTransaction(ctx, func(ctx context.Context) error {
+			attempts++
+			return updateAfterConcurrentChange(ctx)
+		})
+
+		assert.Nil(t, txErr)
+		assert.Equal(t, 1, attempts)
+	})
+	clear(ctx, dbPool)
+}
@@ -0,0 +1,101 @@
+package migrations
+
+import (
+	"Service-schema/persistence/migrations"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_WhenMigrationsLoaded_ShouldBeContiguousPairsInVersionOrder(t *testing.T) {
+	t.Run("WhenMigrationsLoaded_ShouldBeContiguousPairsInVersionOrder", func(t *testing.T) {
+		loadedMigrations, err := migrations.LoadMigrations()
+
+		assert.Nil(t, err)
+		assert.NotEmpty(t, loadedMigrations)
+		for index, migration := range loadedMigrations {
+			assert.Equal(t, int64(index+1), migration.Version)
+			assert.NotEmpty(t, migration.Name)
+			assert.NotEmpty(t, migration.UpSQL)
+			assert.NotEmpty(t, migration.DownSQL)
+		}
+	})
+}
+
+func Test_WhenMigrationCreated_ShouldNumberItAfterEmbeddedAndWrittenFiles(t *testing.T) {
+	t.Run("WhenMigrationCreated_ShouldNumberItAfterEmbeddedAndWrittenFiles", func(t *testing.T) {
+		directory := t.TempDir()
+		embeddedMigrations, _ := migrations.LoadMigrations()
+		latestVersion := embeddedMigrations[len(embeddedMigrations)-1].Version
+
+		firstPaths, firstErr := migrations.Create(directory, "Add_Index")
+		secondPaths, secondErr := migrations.Create(directory, "add_column")
+
+		assert.Nil(t, firstErr)
+		assert.Nil(t, secondErr)
+		assert.Equal(t, []string{
+			filepath.Join(directory, formatMigrationFileName(latestVersion+1, "add_index", "up")),
+			filepath.Join(directory, formatMigrationFileName(latestVersion+1, "add_index", "down")),
+		}, firstPaths)
+		assert.Equal(t, filepath.Join(directory, formatMigrationFileName(latestVersion+2, "add_column", "up")), secondPaths[0])
+	})
+}
+
+func Test_WhenMigrationCreateFails_ShouldNotTouchExistingFiles(t *testing.T) {
+	t.Run("WhenMigrationCreateFails_ShouldNotTouchExistingFiles", func(t *testing.T) {
+		directory := t.TempDir()
+		existingPath := filepath.Join(directory, "9000_existing.up.sql")
+		assert.Nil(t, os.WriteFile(existingPath, []byte("SELECT 1;"), 0o644))
+
+		_, invalidNameErr := migrations.Create(directory, "add-index")
+		_, missingDirectoryErr := migrations.Create(filepath.Join(directory, "missing"), "add_index")
+		paths, err := migrations.Create(directory, "add_index")
+
+		content, _ := os.ReadFile(existingPath)
+		assert.NotNil(t, invalidNameErr)
+		assert.NotNil(t, missingDirectoryErr)
+		assert.Nil(t, err)
+		assert.Equal(t, filepath.Join(directory, "9001_add_index.up.sql"), paths[0])
+		assert.Equal(t, "SELECT 1;", string(content))
+	})
+}
+
+func Test_WhenMigrateArgumentsParsed_ShouldAcceptOnlyKnownForms(t *testing.T) {
+	testCases := []struct {
+		name            string
+		args            []string
+		expectedCommand migrations.Command
+		expectedError   string
+	}{
+		{name: "Up", args: []string{"migrate", "up"}, expectedCommand: migrations.Command{Action: migrations.UpAction, Steps: 1}},
+		{name: "DownDefaultsToOneStep", args: []string{"migrate", "down"}, expectedCommand: migrations.Command{Action: migrations.DownAction, Steps: 1}},
+		{name: "DownWithSteps", args: []string{"migrate", "down", "3"}, expectedCommand: migrations.Command{Action: migrations.DownAction, Steps: 3}},
+		{name: "Status", args: []string{"migrate", "status"}, expectedCommand: migrations.Command{Action: migrations.StatusAction, Steps: 1}},
+		{name: "Create", args: []string{"migrate", "create", "add_index"}, expectedCommand: migrations.Command{Action: migrations.CreateAction, Steps: 1, Name: "add_index"}},
+		{name: "MissingAction", args: []string{"migrate"}, expectedError: migrations.Usage},
+		{name: "UnknownAction", args: []string{"migrate", "redo"}, expectedError: migrations.Usage},
+		{name: "ExtraArgument", args: []string{"migrate", "up", "now"}, expectedError: migrations.Usage},
+		{name: "CreateWithoutName", args: []string{"migrate", "create"}, expectedError: migrations.Usage},
+		{name: "NonPositiveSteps", args: []string{"migrate", "down", "0"}, expectedError: `steps must be a positive integer, got "0"`},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			command, err := migrations.ParseCommand(testCase.args)
+
+			if testCase.expectedError != "" {
+				assert.EqualError(t, err, testCase.expectedError)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, testCase.expectedCommand, command)
+			assert.Equal(t, testCase.expectedCommand.Action != migrations.CreateAction, command.NeedsDatabase())
+		})
+	}
+}
+
+func formatMigrationFileName(version int64, name string, direction string) string {
+	return fmt.Sprintf("%04d_%s.%s.sql", version, name, direction)
+}
@@ -17,14 +17,5 @@ done
 $WINPTY docker exec -i postgresql psql -U postgres -d postgres -c "CREATE DATABASE product_service"
 echo "DATABASE product_service created"
 
-$WINPTY docker exec -i postgresql psql -U postgres -d product_service -c "
-create table if not exists products
-(
-  id bigserial not null primary key,
-  name varchar(255) not null,
-  price double precision not null,
-  discount double precision,
-  store varchar(255) not null
-);
-"
-echo "Table products created"
+cd "$(dirname "$0")/../.." && go run . migrate up
+echo "Migrations applied"
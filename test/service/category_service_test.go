@@ -0,0 +1,111 @@
+package service
+
+import (
+	"Service-schema/domain"
+	"Service-schema/domain/domainerror"
+	"Service-schema/service"
+	"Service-schema/service/dto"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+var categoryService service.ICategoryService
+
+func setupCategories() {
+	electronics, computers := int64(1), int64(2)
+	var initializedCategories = []domain.Category{
+		{Id: 1, Name: "Electronics"},
+		{Id: 2, Name: "Computers", ParentId: &electronics},
+		{Id: 3, Name: "Monitors", ParentId: &computers},
+		{Id: 4, Name: "Phones", ParentId: &electronics},
+	}
+	categoryService = service.NewCategoryService(NewFakeCategoryRepository(initializedCategories))
+}
+
+func Test_ShouldGetCategoryTree(t *testing.T) {
+	ctx := context.Background()
+	setupCategories()
+	t.Run("ShouldGetCategoryTree", func(t *testing.T) {
+		categoryTree, _ := categoryService.GetCategoryTree(ctx)
+		assert.Equal(t, 1, len(categoryTree))
+		assert.Equal(t, 2, len(categoryTree[0].Children))
+		assert.Equal(t, "Monitors", categoryTree[0].Children[0].Children[0].Name)
+	})
+}
+
+func Test_WhenNoValidationErrorOccurred_ShouldAddCategory(t *testing.T) {
+	ctx := context.Background()
+	setupCategories()
+	parentId := int64(2)
+	t.Run("WhenNoValidationErrorOccurred_ShouldAddCategory", func(t *testing.T) {
+		addedCategory, err := categoryService.Add(ctx, dto.CreateCategoryRequestDto{Name: " Laptops ", ParentId: &parentId})
+		assert.Nil(t, err)
+		assert.Equal(t, domain.Category{Id: 5, Name: "Laptops", ParentId: &parentId}, addedCategory)
+	})
+}
+
+func Test_WhenNameFieldEmpty_ShouldNotAddCategory(t *testing.T) {
+	ctx := context.Background()
+	setupCategories()
+	t.Run("WhenNameFieldEmpty_ShouldNotAddCategory", func(t *testing.T) {
+		_, err := categoryService.Add(ctx, dto.CreateCategoryRequestDto{Name: ""})
+		assert.ErrorIs(t, err, domainerror.ErrValidation)
+		assert.Equal(t, "Name must be specified", err.Error())
+	})
+}
+
+func Test_WhenNameHas255MultibyteCharacters_ShouldAddCategory(t *testing.T) {
+	ctx := context.Background()
+	setupCategories()
+	t.Run("WhenNameHas255MultibyteCharacters_ShouldAddCategory", func(t *testing.T) {
+		addedCategory, err := categoryService.Add(ctx, dto.CreateCategoryRequestDto{Name: strings.Repeat("é", 255)})
+		assert.Nil(t, err)
+		assert.Equal(t, strings.Repeat("é", 255), addedCategory.Name)
+
+		_, err = categoryService.Add(ctx, dto.CreateCategoryRequestDto{Name: strings.Repeat("é", 256)})
+		assert.ErrorIs(t, err, domainerror.ErrValidation)
+	})
+}
+
+func Test_WhenParentIsItself_ShouldNotUpdateCategory(t *testing.T) {
+	ctx := context.Background()
+	setupCategories()
+	parentId := int64(2)
+	t.Run("WhenParentIsItself_ShouldNotUpdateCategory", func(t *testing.T) {
+		_, err := categoryService.Update(ctx, dto.UpdateCategoryRequestDto{Id: 2, Name: "Computers", ParentId: &parentId})
+		assert.ErrorIs(t, err, domainerror.ErrValidation)
+		assert.Equal(t, "Category cannot be its own parent", err.Error())
+	})
+}
+
+func Test_WhenParentIsDescendant_ShouldNotUpdateCategory(t *testing.T) {
+	ctx := context.Background()
+	setupCategories()
+	parentId := int64(3)
+	t.Run("WhenParentIsDescendant_ShouldNotUpdateCategory", func(t *testing.T) {
+		_, err := categoryService.Update(ctx, dto.UpdateCategoryRequestDto{Id: 1, Name: "Electronics", ParentId: &parentId})
+		assert.ErrorIs(t, err, domainerror.ErrValidation)
+	})
+}
+
+func Test_WhenCategoryHasChildren_ShouldNotDeleteCategory(t *testing.T) {
+	ctx := context.Background()
+	setupCategories()
+	t.Run("WhenCategoryHasChildren_ShouldNotDeleteCategory", func(t *testing.T) {
+		err := categoryService.Delete(ctx, 2)
+		assert.ErrorIs(t, err, domainerror.ErrConflict)
+		assert.Nil(t, categoryService.Delete(ctx, 3))
+	})
+}
+
+func Test_ShouldSetProductCategoriesWithoutDuplicates(t *testing.T) {
+	ctx := context.Background()
+	setupCategories()
+	t.Run("ShouldSetProductCategoriesWithoutDuplicates", func(t *testing.T) {
+		categories, err := categoryService.SetProductCategories(ctx, dto.SetProductCategoriesRequestDto{ProductId: 1, CategoryIds: []int64{4, 3, 4}})
+		assert.Nil(t, err)
+		assert.Equal(t, []int64{3, 4}, []int64{categories[0].Id, categories[1].Id})
+	})
+}
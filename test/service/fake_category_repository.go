@@ -0,0 +1,101 @@
+package service
+
+import (
+	"Service-schema/domain"
+	"Service-schema/domain/domainerror"
+	"Service-schema/persistence"
+	"context"
+)
+
+type FakeCategoryRepository struct {
+	categories        []domain.Category
+	productCategories map[int64][]int64
+	nextCategoryId    int64
+}
+
+func NewFakeCategoryRepository(initializeCategories []domain.Category) persistence.ICategoryRepository {
+	return &FakeCategoryRepository{
+		categories:        initializeCategories,
+		productCategories: map[int64][]int64{},
+		nextCategoryId:    int64(len(initializeCategories)) + 1,
+	}
+}
+
+func (fakeRepository *FakeCategoryRepository) GetAllCategories(ctx context.Context) ([]domain.Category, error) {
+	return append([]domain.Category{}, fakeRepository.categories...), nil
+}
+
+func (fakeRepository *FakeCategoryRepository) GetById(ctx context.Context, categoryId int64) (domain.Category, error) {
+	for _, category := range fakeRepository.categories {
+		if category.Id == categoryId {
+			return category, nil
+		}
+	}
+	return domain.Category{}, domainerror.NotFound(domain.CategoryNotFoundCode, "Category not found")
+}
+
+func (fakeRepository *FakeCategoryRepository) Add(ctx context.Context, category domain.Category) (domain.Category, error) {
+	if category.ParentId != nil {
+		if _, parentErr := fakeRepository.GetById(ctx, *category.ParentId); parentErr != nil {
+			return domain.Category{}, domainerror.Validation(domain.InvalidCategoryCode, "Parent category not found")
+		}
+	}
+	category.Id = fakeRepository.nextCategoryId
+	fakeRepository.categories = append(fakeRepository.categories, category)
+	fakeRepository.nextCategoryId++
+	return category, nil
+}
+
+func (fakeRepository *FakeCategoryRepository) Update(ctx context.Context, category domain.Category) (domain.Category, error) {
+	for parentId := category.ParentId; parentId != nil; {
+		if *parentId == category.Id {
+			return domain.Category{}, domainerror.Validation(domain.InvalidCategoryCode, "Category cannot be moved below itself or one of its descendants")
+		}
+		parent, parentErr := fakeRepository.GetById(ctx, *parentId)
+		if parentErr != nil {
+			return domain.Category{}, domainerror.Validation(domain.InvalidCategoryCode, "Parent category not found")
+		}
+		parentId = parent.ParentId
+	}
+	for index, existingCategory := range fakeRepository.categories {
+		if existingCategory.Id == category.Id {
+			fakeRepository.categories[index] = category
+			return category, nil
+		}
+	}
+	return domain.Category{}, domainerror.NotFound(domain.CategoryNotFoundCode, "Category not found")
+}
+
+func (fakeRepository *FakeCategoryRepository) DeleteById(ctx context.Context, categoryId int64) error {
+	for _, category := range fakeRepository.categories {
+		if category.ParentId != nil && *category.ParentId == categoryId {
+			return domainerror.Conflict(domain.CategoryConflictCode, "Category still has subcategories")
+		}
+	}
+	for index, category := range fakeRepository.categories {
+		if category.Id == categoryId {
+			fakeRepository.categories = append(fakeRepository.categories[:index], fakeRepository.categories[index+1:]...)
+			return nil
+		}
+	}
+	return domainerror.NotFound(domain.CategoryNotFoundCode, "Category not found")
+}
+
+func (fakeRepository *FakeCategoryRepository) GetProductCategories(ctx context.Context, productId int64) ([]domain.Category, error) {
+	categories := []domain.Category{}
+	for _, categoryId := range fakeRepository.productCategories[productId] {
+		category, _ := fakeRepository.GetById(ctx, categoryId)
+		categories = append(categories, category)
+	}
+	return categories, nil
+}
+
+func (fakeRepository *FakeCategoryRepository) SetProductCategories(ctx context.Context, productId int64, categoryIds []int64) error {
+	for _, categoryId := range categoryIds {
+		if _, getErr := fakeRepository.GetById(ctx, categoryId); getErr != nil {
+			return domainerror.Validation(domain.InvalidCategoryCode, "Categories must exist")
+		}
+	}
+	fakeRepository.productCategories[productId] = categoryIds
+	return nil
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"Service-schema/domain"
+	"Service-schema/persistence"
+	"context"
+)
+
+type FakePriceHistoryRepository struct {
+	priceChanges []domain.PriceChange
+}
+
+func NewFakePriceHistoryRepository(initializePriceChanges []domain.PriceChange) persistence.IPriceHistoryRepository {
+	return &FakePriceHistoryRepository{
+		priceChanges: initializePriceChanges,
+	}
+}
+
+func (fakeRepository *FakePriceHistoryRepository) GetPriceHistory(ctx context.Context, query domain.PriceHistoryQuery) ([]domain.PriceChange, error) {
+	priceChanges := []domain.PriceChange{}
+	for _, priceChange := range fakeRepository.priceChanges {
+		if priceChange.ProductId != query.ProductId ||
+			(query.From != nil && priceChange.ChangedAt.Before(*query.From)) ||
+			(query.To != nil && !priceChange.ChangedAt.Before(*query.To)) {
+			continue
+		}
+		priceChanges = append(priceChanges, priceChange)
+	}
+	return priceChanges, nil
+}
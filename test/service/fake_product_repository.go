@@ -2,9 +2,14 @@ package service
 
 import (
 	"Service-schema/domain"
+	"Service-schema/domain/domainerror"
 	"Service-schema/persistence"
-	"errors"
-	"fmt"
+	"cmp"
+	"context"
+	"slices"
+	"sort"
+	"strings"
+	"time"
 )
 
 type FakeProductRepository struct {
@@ -20,60 +25,228 @@ func NewFakeProductRepository(initializeProducts []domain.Product) persistence.I
 	}
 }
 
-func (fakeRepository *FakeProductRepository) GetAllProducts() []domain.Product {
-	return fakeRepository.products
+func (fakeRepository *FakeProductRepository) GetProducts(ctx context.Context, query domain.ProductQuery) (domain.ProductPage, error) {
+	var matchingProducts []domain.Product
+	for _, product := range fakeRepository.products {
+		if matchesProductQuery(product, query) {
+			matchingProducts = append(matchingProducts, product)
+		}
+	}
+
+	sort.SliceStable(matchingProducts, func(i, j int) bool {
+		return compareProducts(matchingProducts[i], matchingProducts[j], query) < 0
+	})
+
+	var pageProducts []domain.Product
+	for _, product := range matchingProducts {
+		if query.Cursor != nil && compareProducts(product, cursorProduct(query), query) <= 0 {
+			continue
+		}
+		pageProducts = append(pageProducts, product)
+	}
+
+	if query.Offset < len(pageProducts) {
+		pageProducts = pageProducts[query.Offset:]
+	} else {
+		pageProducts = nil
+	}
+	if len(pageProducts) > query.Limit+1 {
+		pageProducts = pageProducts[:query.Limit+1]
+	}
+
+	return domain.NewProductPage(query, pageProducts, int64(len(matchingProducts))), nil
 }
 
-func (fakeRepository *FakeProductRepository) GetAllProductsByStoreName(storeName string) []domain.Product {
-	var productsWithStoreName []domain.Product
-	for index, product := range fakeRepository.products {
-		if product.Store == storeName {
-			productsWithStoreName = append(productsWithStoreName, fakeRepository.products[index])
+func (fakeRepository *FakeProductRepository) ExportProducts(ctx context.Context, query domain.ProductQuery, write func(domain.Product) error) error {
+	var matchingProducts []domain.Product
+	for _, product := range fakeRepository.products {
+		if matchesProductQuery(product, query) {
+			matchingProducts = append(matchingProducts, product)
+		}
+	}
+
+	sort.SliceStable(matchingProducts, func(i, j int) bool {
+		return compareProducts(matchingProducts[i], matchingProducts[j], query) < 0
+	})
+
+	for _, product := range matchingProducts {
+		if writeErr := write(product); writeErr != nil {
+			return writeErr
+		}
+	}
+	return nil
+}
+
+// SearchProducts matches case-insensitive substrings instead of words, ranking name matches above store matches.
+func (fakeRepository *FakeProductRepository) SearchProducts(ctx context.Context, query domain.ProductSearchQuery) (domain.ProductSearchResult, error) {
+	result := domain.ProductSearchResult{Hits: []domain.ProductSearchHit{}, StoreFacets: []domain.StoreFacet{}}
+	text := strings.ToLower(query.Text)
+	for _, product := range fakeRepository.products {
+		rank := 0.0
+		if strings.Contains(strings.ToLower(product.Name), text) {
+			rank = 1
+		} else if strings.Contains(strings.ToLower(product.Store), text) {
+			rank = 0.5
+		}
+		if product.DeletedAt != nil || rank == 0 {
+			continue
+		}
+
+		facetIndex := slices.IndexFunc(result.StoreFacets, func(facet domain.StoreFacet) bool { return facet.StoreId == product.StoreId })
+		if facetIndex < 0 {
+			result.StoreFacets = append(result.StoreFacets, domain.StoreFacet{StoreId: product.StoreId, Store: product.Store})
+			facetIndex = len(result.StoreFacets) - 1
+		}
+		result.StoreFacets[facetIndex].Count++
+
+		if query.StoreId == 0 || product.StoreId == query.StoreId {
+			result.Hits = append(result.Hits, domain.ProductSearchHit{Product: product, Rank: rank, Snippet: product.Name + " - " + product.Store})
 		}
 	}
-	return productsWithStoreName
+
+	sort.SliceStable(result.Hits, func(i, j int) bool { return result.Hits[i].Rank > result.Hits[j].Rank })
+	result.TotalCount = int64(len(result.Hits))
+	result.Hits = result.Hits[min(query.Offset, len(result.Hits)):min(query.Offset+query.Limit, len(result.Hits))]
+	return result, nil
 }
 
-func (fakeRepository *FakeProductRepository) Add(product domain.Product) error {
-	fakeRepository.products = append(fakeRepository.products, domain.Product{
+func (fakeRepository *FakeProductRepository) Add(ctx context.Context, product domain.Product) (domain.Product, error) {
+	newProduct := domain.Product{
 		Id:       currentIdValue,
 		Name:     product.Name,
 		Price:    product.Price,
 		Discount: product.Discount,
+		StoreId:  product.StoreId,
 		Store:    product.Store,
-	})
+		Version:  1,
+	}
+	fakeRepository.products = append(fakeRepository.products, newProduct)
 	currentIdValue++
-	return nil
+	return newProduct, nil
+}
+
+func (fakeRepository *FakeProductRepository) AddAll(ctx context.Context, products []domain.Product) ([]domain.Product, error) {
+	newProducts := []domain.Product{}
+	for _, product := range products {
+		newProduct, _ := fakeRepository.Add(ctx, product)
+		newProducts = append(newProducts, newProduct)
+	}
+	return newProducts, nil
 }
 
-func (fakeRepository *FakeProductRepository) GetById(productId int64) (domain.Product, error) {
+func (fakeRepository *FakeProductRepository) GetById(ctx context.Context, productId int64) (domain.Product, error) {
 	for index, product := range fakeRepository.products {
-		if product.Id == productId {
+		if product.Id == productId && product.DeletedAt == nil {
 			return fakeRepository.products[index], nil
 		}
 	}
-	return domain.Product{}, errors.New(fmt.Sprintf("Product not found"))
+	return domain.Product{}, domainerror.NotFound(domain.ProductNotFoundCode, "Product not found")
 }
 
-func (fakeRepository *FakeProductRepository) DeleteById(productId int64) error {
+func (fakeRepository *FakeProductRepository) DeleteById(ctx context.Context, productId int64, expectedVersion int64) error {
 
 	for index, product := range fakeRepository.products {
-		if product.Id == productId {
-			fakeRepository.products = append(fakeRepository.products[:index-1], fakeRepository.products[index:]...)
+		if product.Id == productId && product.DeletedAt == nil {
+			if expectedVersion != 0 && product.Version != expectedVersion {
+				return domain.ProductVersionConflict(productId, expectedVersion)
+			}
+			deletedAt := time.Now()
+			fakeRepository.products[index].DeletedAt = &deletedAt
+			fakeRepository.products[index].Version++
 			return nil
 		}
 	}
 
-	return errors.New(fmt.Sprintf("Product not found"))
+	return domainerror.NotFound(domain.ProductNotFoundCode, "Product not found")
 }
 
-func (fakeRepository *FakeProductRepository) UpdatePrice(productId int64, price float32) error {
+func (fakeRepository *FakeProductRepository) Restore(ctx context.Context, productId int64) (domain.Product, error) {
 	for index, product := range fakeRepository.products {
 		if product.Id == productId {
+			if product.DeletedAt != nil {
+				fakeRepository.products[index].DeletedAt = nil
+				fakeRepository.products[index].Version++
+			}
+			return fakeRepository.products[index], nil
+		}
+	}
+
+	return domain.Product{}, domainerror.NotFound(domain.ProductNotFoundCode, "Product not found")
+}
+
+func (fakeRepository *FakeProductRepository) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	var remainingProducts []domain.Product
+	for _, product := range fakeRepository.products {
+		if product.DeletedAt == nil || !product.DeletedAt.Before(deletedBefore) {
+			remainingProducts = append(remainingProducts, product)
+		}
+	}
+
+	purgedCount := int64(len(fakeRepository.products) - len(remainingProducts))
+	fakeRepository.products = remainingProducts
+	return purgedCount, nil
+}
+
+func (fakeRepository *FakeProductRepository) UpdatePrice(ctx context.Context, productId int64, price domain.Money) error {
+	for index, product := range fakeRepository.products {
+		if product.Id == productId && product.DeletedAt == nil {
 			fakeRepository.products[index].Price = price
+			fakeRepository.products[index].Version++
+			return nil
+		}
+	}
+
+	return domainerror.NotFound(domain.ProductNotFoundCode, "Product not found")
+}
+
+func matchesProductQuery(product domain.Product, query domain.ProductQuery) bool {
+	return (query.IncludeDeleted || product.DeletedAt == nil) &&
+		(query.StoreId == 0 || product.StoreId == query.StoreId) &&
+		(query.NameContains == "" || strings.Contains(strings.ToLower(product.Name), strings.ToLower(query.NameContains))) &&
+		(query.MinPrice == nil || product.Price.Amount >= *query.MinPrice) &&
+		(query.MaxPrice == nil || product.Price.Amount <= *query.MaxPrice) &&
+		(query.MinDiscount == nil || product.Discount >= *query.MinDiscount) &&
+		(query.MaxDiscount == nil || product.Discount <= *query.MaxDiscount)
+}
+
+func cursorProduct(query domain.ProductQuery) domain.Product {
+	product := domain.Product{Id: query.Cursor.Id, Name: query.Cursor.SortValue}
+	sortValue, _ := domain.ParseDecimal(query.Cursor.SortValue)
+	product.Price = domain.Money{Amount: sortValue}
+	product.Discount = sortValue
+	return product
+}
+
+func compareProducts(left domain.Product, right domain.Product, query domain.ProductQuery) int {
+	result := 0
+	switch query.SortField {
+	case domain.SortByName:
+		result = strings.Compare(left.Name, right.Name)
+	case domain.SortByPrice:
+		result = cmp.Compare(left.Price.Amount, right.Price.Amount)
+	case domain.SortByDiscount:
+		result = cmp.Compare(left.Discount, right.Discount)
+	}
+	if result == 0 {
+		result = cmp.Compare(left.Id, right.Id)
+	}
+	if query.SortDirection == domain.Descending {
+		return -result
+	}
+	return result
+}
+
+func (fakeRepository *FakeProductRepository) Update(ctx context.Context, productId int64, expectedVersion int64, changes domain.ProductChanges) error {
+	for index, product := range fakeRepository.products {
+		if product.Id == productId && product.DeletedAt == nil {
+			if expectedVersion != 0 && product.Version != expectedVersion {
+				return domain.ProductVersionConflict(productId, expectedVersion)
+			}
+			fakeRepository.products[index] = product.Apply(changes)
+			fakeRepository.products[index].Version++
 			return nil
 		}
 	}
 
-	return errors.New(fmt.Sprintf("Product not found"))
+	return domainerror.NotFound(domain.ProductNotFoundCode, "Product not found")
 }
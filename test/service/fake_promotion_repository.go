@@ -0,0 +1,71 @@
+package service
+
+import (
+	"Service-schema/domain"
+	"Service-schema/domain/domainerror"
+	"Service-schema/persistence"
+	"context"
+	"time"
+)
+
+type FakePromotionRepository struct {
+	promotions      []domain.Promotion
+	nextPromotionId int64
+}
+
+func NewFakePromotionRepository(initializePromotions []domain.Promotion) persistence.IPromotionRepository {
+	return &FakePromotionRepository{
+		promotions:      initializePromotions,
+		nextPromotionId: int64(len(initializePromotions)) + 1,
+	}
+}
+
+func (fakeRepository *FakePromotionRepository) GetAllPromotions(ctx context.Context) ([]domain.Promotion, error) {
+	return append([]domain.Promotion{}, fakeRepository.promotions...), nil
+}
+
+func (fakeRepository *FakePromotionRepository) GetActivePromotions(ctx context.Context, at time.Time, products []domain.Product) ([]domain.Promotion, error) {
+	promotions := []domain.Promotion{}
+	for _, promotion := range fakeRepository.promotions {
+		if promotion.IsActiveAt(at) {
+			promotions = append(promotions, promotion)
+		}
+	}
+	return promotions, nil
+}
+
+func (fakeRepository *FakePromotionRepository) GetById(ctx context.Context, promotionId int64) (domain.Promotion, error) {
+	for _, promotion := range fakeRepository.promotions {
+		if promotion.Id == promotionId {
+			return promotion, nil
+		}
+	}
+	return domain.Promotion{}, domainerror.NotFound(domain.PromotionNotFoundCode, "Promotion not found")
+}
+
+func (fakeRepository *FakePromotionRepository) Add(ctx context.Context, promotion domain.Promotion) (domain.Promotion, error) {
+	promotion.Id = fakeRepository.nextPromotionId
+	fakeRepository.promotions = append(fakeRepository.promotions, promotion)
+	fakeRepository.nextPromotionId++
+	return promotion, nil
+}
+
+func (fakeRepository *FakePromotionRepository) Update(ctx context.Context, promotion domain.Promotion) (domain.Promotion, error) {
+	for index, existingPromotion := range fakeRepository.promotions {
+		if existingPromotion.Id == promotion.Id {
+			fakeRepository.promotions[index] = promotion
+			return promotion, nil
+		}
+	}
+	return domain.Promotion{}, domainerror.NotFound(domain.PromotionNotFoundCode, "Promotion not found")
+}
+
+func (fakeRepository *FakePromotionRepository) DeleteById(ctx context.Context, promotionId int64) error {
+	for index, promotion := range fakeRepository.promotions {
+		if promotion.Id == promotionId {
+			fakeRepository.promotions = append(fakeRepository.promotions[:index], fakeRepository.promotions[index+1:]...)
+			return nil
+		}
+	}
+	return domainerror.NotFound(domain.PromotionNotFoundCode, "Promotion not found")
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"Service-schema/domain"
+	"Service-schema/domain/domainerror"
+	"Service-schema/persistence"
+	"context"
+)
+
+type FakeStockRepository struct {
+	stockLevels map[int64]domain.StockLevel
+}
+
+func NewFakeStockRepository(initializeStockLevels []domain.StockLevel) persistence.IStockRepository {
+	stockLevels := map[int64]domain.StockLevel{}
+	for _, stockLevel := range initializeStockLevels {
+		stockLevels[stockLevel.ProductId] = stockLevel
+	}
+	return &FakeStockRepository{
+		stockLevels: stockLevels,
+	}
+}
+
+func (fakeRepository *FakeStockRepository) GetByProductId(ctx context.Context, productId int64) (domain.StockLevel, error) {
+	stockLevel, found := fakeRepository.stockLevels[productId]
+	if !found {
+		return domain.StockLevel{}, domainerror.NotFound(domain.ProductNotFoundCode, "Product not found")
+	}
+	return stockLevel, nil
+}
+
+func (fakeRepository *FakeStockRepository) GetByStoreId(ctx context.Context, storeId int64) ([]domain.StockLevel, error) {
+	stockLevels := []domain.StockLevel{}
+	for _, stockLevel := range fakeRepository.stockLevels {
+		if stockLevel.StoreId == storeId {
+			stockLevels = append(stockLevels, stockLevel)
+		}
+	}
+	return stockLevels, nil
+}
+
+func (fakeRepository *FakeStockRepository) Update(ctx context.Context, productId int64, modify func(stockLevel domain.StockLevel) (domain.StockLevel, error)) (domain.StockLevel, error) {
+	stockLevel, getErr := fakeRepository.GetByProductId(ctx, productId)
+	if getErr != nil {
+		return domain.StockLevel{}, getErr
+	}
+
+	updatedStockLevel, modifyErr := modify(stockLevel)
+	if modifyErr != nil {
+		return domain.StockLevel{}, modifyErr
+	}
+
+	fakeRepository.stockLevels[productId] = updatedStockLevel
+	return updatedStockLevel, nil
+}
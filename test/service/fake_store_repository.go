@@ -0,0 +1,76 @@
+package service
+
+import (
+	"Service-schema/domain"
+	"Service-schema/domain/domainerror"
+	"Service-schema/persistence"
+	"context"
+	"strings"
+)
+
+type FakeStoreRepository struct {
+	stores      []domain.Store
+	nextStoreId int64
+}
+
+func NewFakeStoreRepository(initializeStores []domain.Store) persistence.IStoreRepository {
+	return &FakeStoreRepository{
+		stores:      initializeStores,
+		nextStoreId: int64(len(initializeStores)) + 1,
+	}
+}
+
+func (fakeRepository *FakeStoreRepository) GetAllStores(ctx context.Context) ([]domain.Store, error) {
+	return append([]domain.Store{}, fakeRepository.stores...), nil
+}
+
+func (fakeRepository *FakeStoreRepository) GetById(ctx context.Context, storeId int64) (domain.Store, error) {
+	for _, store := range fakeRepository.stores {
+		if store.Id == storeId {
+			return store, nil
+		}
+	}
+	return domain.Store{}, domainerror.NotFound(domain.StoreNotFoundCode, "Store not found")
+}
+
+func (fakeRepository *FakeStoreRepository) Add(ctx context.Context, store domain.Store) (domain.Store, error) {
+	if fakeRepository.hasStoreNamed(store.Name, 0) {
+		return domain.Store{}, domainerror.Conflict(domain.StoreConflictCode, "Store already exists")
+	}
+	newStore := domain.Store{Id: fakeRepository.nextStoreId, Name: store.Name}
+	fakeRepository.stores = append(fakeRepository.stores, newStore)
+	fakeRepository.nextStoreId++
+	return newStore, nil
+}
+
+func (fakeRepository *FakeStoreRepository) Update(ctx context.Context, store domain.Store) (domain.Store, error) {
+	if fakeRepository.hasStoreNamed(store.Name, store.Id) {
+		return domain.Store{}, domainerror.Conflict(domain.StoreConflictCode, "Store already exists")
+	}
+	for index, existingStore := range fakeRepository.stores {
+		if existingStore.Id == store.Id {
+			fakeRepository.stores[index] = store
+			return store, nil
+		}
+	}
+	return domain.Store{}, domainerror.NotFound(domain.StoreNotFoundCode, "Store not found")
+}
+
+func (fakeRepository *FakeStoreRepository) DeleteById(ctx context.Context, storeId int64) error {
+	for index, store := range fakeRepository.stores {
+		if store.Id == storeId {
+			fakeRepository.stores = append(fakeRepository.stores[:index], fakeRepository.stores[index+1:]...)
+			return nil
+		}
+	}
+	return domainerror.NotFound(domain.StoreNotFoundCode, "Store not found")
+}
+
+func (fakeRepository *FakeStoreRepository) hasStoreNamed(name string, exceptId int64) bool {
+	for _, store := range fakeRepository.stores {
+		if store.Id != exceptId && strings.EqualFold(store.Name, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,16 @@
+package service
+
+import (
+	"Service-schema/persistence"
+	"context"
+)
+
+type FakeTransactionManager struct{}
+
+func NewFakeTransactionManager() persistence.ITransactionManager {
+	return &FakeTransactionManager{}
+}
+
+func (fakeTransactionManager *FakeTransactionManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
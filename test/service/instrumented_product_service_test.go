@@ -0,0 +1,54 @@
+package service
+
+import (
+	"Service-schema/persistence"
+	"Service-schema/service"
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func Test_WhenProductServiceInstrumented_ShouldCountCallsByOutcome(t *testing.T) {
+	ctx := context.Background()
+	setupTest(t)
+	t.Run("WhenProductServiceInstrumented_ShouldCountCallsByOutcome", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		instrumentedService := service.NewInstrumentedProductService(productService, registry)
+
+		_, _ = instrumentedService.GetById(ctx, 1)
+		_, _ = instrumentedService.GetById(ctx, 1)
+		_, _ = instrumentedService.GetById(ctx, 42)
+
+		expected := `
+# HELP product_service_calls_total Number of product service calls.
+# TYPE product_service_calls_total counter
+product_service_calls_total{method="GetById",outcome="not_found"} 1
+product_service_calls_total{method="GetById",outcome="ok"} 2
+`
+		assert.Nil(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "product_service_calls_total"))
+		assert.Equal(t, 1, testutil.CollectAndCount(registry, "product_service_call_duration_seconds"))
+	})
+}
+
+func Test_WhenProductRepositoryInstrumented_ShouldCountOnlyFailedCalls(t *testing.T) {
+	ctx := context.Background()
+	setupTest(t)
+	t.Run("WhenProductRepositoryInstrumented_ShouldCountOnlyFailedCalls", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		instrumentedRepository := persistence.NewInstrumentedProductRepository(productRepository, registry)
+
+		_, _ = instrumentedRepository.GetById(ctx, 1)
+		_ = instrumentedRepository.DeleteById(ctx, 42, 0)
+
+		expected := `
+# HELP product_repository_errors_total Number of product repository calls that returned an error.
+# TYPE product_repository_errors_total counter
+product_repository_errors_total{method="DeleteById",outcome="not_found"} 1
+`
+		assert.Nil(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "product_repository_errors_total"))
+		assert.Equal(t, 2, testutil.CollectAndCount(registry, "product_repository_query_duration_seconds"))
+	})
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"Service-schema/domain"
+	"Service-schema/domain/domainerror"
+	"Service-schema/service"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+var priceHistoryService service.IPriceHistoryService
+
+var priceChangeTime = time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+func setupPriceHistory(t *testing.T) {
+	setupTest(t)
+	var initializedPriceChanges = []domain.PriceChange{
+		{Id: 1, ProductId: 1, OldPrice: domain.MustMoney("1800", "USD"), NewPrice: domain.MustMoney("1900", "USD"), Actor: "finance", ChangedAt: priceChangeTime},
+		{Id: 2, ProductId: 1, OldPrice: domain.MustMoney("1900", "USD"), NewPrice: domain.MustMoney("2000", "USD"), Actor: "finance", ChangedAt: priceChangeTime.AddDate(0, 1, 0)},
+		{Id: 3, ProductId: 2, OldPrice: domain.MustMoney("1000", "USD"), NewPrice: domain.MustMoney("1200", "USD"), Actor: "finance", ChangedAt: priceChangeTime},
+		{Id: 4, ProductId: 20, ProductName: "Purged", OldPrice: domain.MustMoney("500", "USD"), NewPrice: domain.MustMoney("450", "USD"), Actor: "finance", ChangedAt: priceChangeTime},
+	}
+	priceHistoryService = service.NewPriceHistoryService(NewFakePriceHistoryRepository(initializedPriceChanges), productRepository)
+}
+
+func Test_WhenTimeRangeGiven_ShouldGetPriceChangesInRange(t *testing.T) {
+	ctx := context.Background()
+	setupPriceHistory(t)
+	from := priceChangeTime.AddDate(0, 0, 1)
+	t.Run("WhenTimeRangeGiven_ShouldGetPriceChangesInRange", func(t *testing.T) {
+		allPriceChanges, _ := priceHistoryService.GetPriceHistory(ctx, domain.PriceHistoryQuery{ProductId: 1})
+		priceChanges, _ := priceHistoryService.GetPriceHistory(ctx, domain.PriceHistoryQuery{ProductId: 1, From: &from})
+		assert.Equal(t, 2, len(allPriceChanges))
+		assert.Equal(t, 1, len(priceChanges))
+		assert.Equal(t, int64(2), priceChanges[0].Id)
+	})
+}
+
+func Test_WhenFromNotBeforeTo_ShouldNotGetPriceHistory(t *testing.T) {
+	ctx := context.Background()
+	setupPriceHistory(t)
+	t.Run("WhenFromNotBeforeTo_ShouldNotGetPriceHistory", func(t *testing.T) {
+		_, err := priceHistoryService.GetPriceHistory(ctx, domain.PriceHistoryQuery{ProductId: 1, From: &priceChangeTime, To: &priceChangeTime})
+		assert.ErrorIs(t, err, domainerror.ErrValidation)
+	})
+}
+
+func Test_WhenProductDoesNotExist_ShouldNotGetPriceHistory(t *testing.T) {
+	ctx := context.Background()
+	setupPriceHistory(t)
+	t.Run("WhenProductDoesNotExist_ShouldNotGetPriceHistory", func(t *testing.T) {
+		_, err := priceHistoryService.GetPriceHistory(ctx, domain.PriceHistoryQuery{ProductId: 10})
+		assert.ErrorIs(t, err, domainerror.ErrNotFound)
+	})
+}
+
+func Test_WhenProductPurged_ShouldStillGetPriceHistory(t *testing.T) {
+	ctx := context.Background()
+	setupPriceHistory(t)
+	t.Run("WhenProductPurged_ShouldStillGetPriceHistory", func(t *testing.T) {
+		_, notFoundErr := priceHistoryService.GetPriceHistory(ctx, domain.PriceHistoryQuery{ProductId: 20})
+		priceChanges, err := priceHistoryService.GetPriceHistory(ctx, domain.PriceHistoryQuery{ProductId: 20, IncludeDeleted: true})
+		assert.ErrorIs(t, notFoundErr, domainerror.ErrNotFound)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(priceChanges))
+		assert.Equal(t, "Purged", priceChanges[0].ProductName)
+	})
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"Service-schema/domain"
+	"Service-schema/domain/domainerror"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func exportProducts(ctx context.Context, query domain.ProductQuery) ([]domain.Product, error) {
+	var products []domain.Product
+	err := productService.ExportProducts(ctx, query, func(product domain.Product) error {
+		products = append(products, product)
+		return nil
+	})
+	return products, err
+}
+
+func Test_WhenFiltersGiven_ShouldExportMatchingProductsIgnoringPaging(t *testing.T) {
+	ctx := context.Background()
+	setupTest(t)
+	minPrice := domain.MustDecimal("1500")
+	t.Run("WhenFiltersGiven_ShouldExportMatchingProductsIgnoringPaging", func(t *testing.T) {
+		products, err := exportProducts(ctx, domain.ProductQuery{MinPrice: &minPrice, SortField: domain.SortByPrice, SortDirection: domain.Descending, Limit: 1, Offset: 1})
+
+		assert.Nil(t, err)
+		assert.Equal(t, []int64{3, 4, 1}, productIds(products))
+	})
+}
+
+func Test_WhenExportingProducts_ShouldApplyPromotions(t *testing.T) {
+	ctx := context.Background()
+	setupPromotions(t)
+	t.Run("WhenExportingProducts_ShouldApplyPromotions", func(t *testing.T) {
+		_, _ = promotionService.Add(ctx, blackFridayRequest())
+
+		products, _ := exportProducts(ctx, domain.ProductQuery{At: promotionStart})
+		assert.Equal(t, 4, len(products))
+		assert.Equal(t, domain.MustMoney("2100", "USD"), products[3].DiscountedPrice())
+	})
+}
+
+func Test_WhenExportStoreDoesNotExist_ShouldNotExportProducts(t *testing.T) {
+	ctx := context.Background()
+	setupTest(t)
+	t.Run("WhenExportStoreDoesNotExist_ShouldNotExportProducts", func(t *testing.T) {
+		products, err := exportProducts(ctx, domain.ProductQuery{StoreId: 42})
+
+		assert.ErrorIs(t, err, domainerror.ErrNotFound)
+		assert.Empty(t, products)
+	})
+}
+
+func Test_WhenWriteFails_ShouldStopExport(t *testing.T) {
+	ctx := context.Background()
+	setupTest(t)
+	t.Run("WhenWriteFails_ShouldStopExport", func(t *testing.T) {
+		writeErr := errors.New("client went away")
+		writtenCount := 0
+		err := productService.ExportProducts(ctx, domain.ProductQuery{}, func(product domain.Product) error {
+			writtenCount++
+			return writeErr
+		})
+
+		assert.ErrorIs(t, err, writeErr)
+		assert.Equal(t, 1, writtenCount)
+	})
+}
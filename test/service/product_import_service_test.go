@@ -0,0 +1,126 @@
+package service
+
+import (
+	"Service-schema/domain"
+	"Service-schema/domain/domainerror"
+	"Service-schema/persistence"
+	"Service-schema/service"
+	"Service-schema/service/dto"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"slices"
+	"testing"
+)
+
+var productImportService service.IProductImportService
+
+func setupProductImport(t *testing.T) {
+	setupTest(t)
+	productImportService = service.NewProductImportService(productRepository, storeRepository, NewFakeTransactionManager())
+}
+
+func importRows(rows ...dto.ProductImportRowDto) dto.ProductImportRequestDto {
+	return dto.ProductImportRequestDto{Rows: slices.Values(rows)}
+}
+
+func Test_WhenBestEffortImport_ShouldCreateValidRowsAndReportInvalidOnes(t *testing.T) {
+	ctx := context.Background()
+	setupProductImport(t)
+	t.Run("WhenBestEffortImport_ShouldCreateValidRowsAndReportInvalidOnes", func(t *testing.T) {
+		productImportRequest := importRows(
+			dto.ProductImportRowDto{Row: 1, Product: dto.CreateProductRequestDto{Name: "Pencil", Price: domain.MustDecimal("200"), StoreId: 5}},
+			dto.ProductImportRowDto{Row: 2, Product: dto.CreateProductRequestDto{Name: "", Price: domain.MustDecimal("200"), StoreId: 5}},
+			dto.ProductImportRowDto{Row: 3, Product: dto.CreateProductRequestDto{Name: "Eraser", Price: domain.MustDecimal("200"), StoreId: 10}},
+			dto.ProductImportRowDto{Row: 4, ParseErr: errors.New("price must be a decimal number such as 12.50")},
+			dto.ProductImportRowDto{Row: 5, Product: dto.CreateProductRequestDto{Name: "Ruler", Price: domain.MustDecimal("300"), StoreId: 5}},
+		)
+		productImportRequest.Mode = domain.BestEffortImport
+
+		report, err := productImportService.Import(ctx, productImportRequest)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, report.Created)
+		assert.Equal(t, 3, report.Failed)
+		assert.Equal(t, domain.ProductImportRowResult{Row: 1, Status: domain.ImportRowCreated, ProductId: 5}, report.Rows[0])
+		assert.Equal(t, domain.ProductImportRowResult{Row: 2, Status: domain.ImportRowInvalid, Message: "Name must be specified",
+			Fields: []domainerror.FieldError{{Field: "name", Message: "Name must be specified"}}}, report.Rows[1])
+		assert.Equal(t, "Store not found with id 10", report.Rows[2].Message)
+		assert.Equal(t, domain.ImportRowInvalid, report.Rows[3].Status)
+		assert.Equal(t, domain.ProductImportRowResult{Row: 5, Status: domain.ImportRowCreated, ProductId: 6}, report.Rows[4])
+		assert.Equal(t, 6, len(getAllProducts(ctx)))
+	})
+}
+
+func Test_WhenAllOrNothingImportHasInvalidRow_ShouldNotCreateAnyProduct(t *testing.T) {
+	ctx := context.Background()
+	setupProductImport(t)
+	t.Run("WhenAllOrNothingImportHasInvalidRow_ShouldNotCreateAnyProduct", func(t *testing.T) {
+		productImportRequest := importRows(
+			dto.ProductImportRowDto{Row: 1, Product: dto.CreateProductRequestDto{Name: "Pencil", Price: domain.MustDecimal("5"), StoreId: 5}},
+			dto.ProductImportRowDto{Row: 2, Product: dto.CreateProductRequestDto{Name: "Ruler", Price: domain.MustDecimal("300"), StoreId: 5}},
+		)
+		productImportRequest.Mode = domain.AllOrNothingImport
+
+		report, err := productImportService.Import(ctx, productImportRequest)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 0, report.Created)
+		assert.Equal(t, 1, report.Failed)
+		assert.Equal(t, domain.ImportRowInvalid, report.Rows[0].Status)
+		assert.Equal(t, domain.ProductImportRowResult{Row: 2, Status: domain.ImportRowNotImported}, report.Rows[1])
+		assert.Equal(t, 4, len(getAllProducts(ctx)))
+	})
+}
+
+func Test_WhenImportModeUnknown_ShouldNotImport(t *testing.T) {
+	ctx := context.Background()
+	setupProductImport(t)
+	t.Run("WhenImportModeUnknown_ShouldNotImport", func(t *testing.T) {
+		productImportRequest := importRows()
+		productImportRequest.Mode = "partial"
+		_, err := productImportService.Import(ctx, productImportRequest)
+		assert.ErrorIs(t, err, domainerror.ErrValidation)
+	})
+}
+
+// rejectingProductRepository rejects every batch containing a product with the given name, as COPY does for a
+// constraint violation in one of its rows.
+type rejectingProductRepository struct {
+	persistence.IProductRepository
+	rejectedName string
+}
+
+func (rejectingProductRepository rejectingProductRepository) AddAll(ctx context.Context, products []domain.Product) ([]domain.Product, error) {
+	for _, product := range products {
+		if product.Name == rejectingProductRepository.rejectedName {
+			return nil, domainerror.Validation(domain.InvalidProductCode, "One of the products violates a storage constraint")
+		}
+	}
+	return rejectingProductRepository.IProductRepository.AddAll(ctx, products)
+}
+
+func Test_WhenBestEffortImportBatchIsRejected_ShouldOnlyFailOffendingRow(t *testing.T) {
+	ctx := context.Background()
+	setupProductImport(t)
+	t.Run("WhenBestEffortImportBatchIsRejected_ShouldOnlyFailOffendingRow", func(t *testing.T) {
+		rejectingImportService := service.NewProductImportService(rejectingProductRepository{IProductRepository: productRepository, rejectedName: "Broken"},
+			storeRepository, NewFakeTransactionManager())
+		productImportRequest := importRows(
+			dto.ProductImportRowDto{Row: 1, Product: dto.CreateProductRequestDto{Name: "Pencil", Price: domain.MustDecimal("200"), StoreId: 5}},
+			dto.ProductImportRowDto{Row: 2, Product: dto.CreateProductRequestDto{Name: "Broken", Price: domain.MustDecimal("200"), StoreId: 5}},
+			dto.ProductImportRowDto{Row: 3, Product: dto.CreateProductRequestDto{Name: "Ruler", Price: domain.MustDecimal("300"), StoreId: 5}},
+		)
+		productImportRequest.Mode = domain.BestEffortImport
+
+		report, err := rejectingImportService.Import(ctx, productImportRequest)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, report.Created)
+		assert.Equal(t, 1, report.Failed)
+		assert.Equal(t, domain.ImportRowCreated, report.Rows[0].Status)
+		assert.Equal(t, domain.ProductImportRowResult{Row: 2, Status: domain.ImportRowFailed, Message: "One of the products violates a storage constraint"}, report.Rows[1])
+		assert.Equal(t, domain.ImportRowCreated, report.Rows[2].Status)
+		assert.Equal(t, 6, len(getAllProducts(ctx)))
+	})
+}
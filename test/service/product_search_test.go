@@ -0,0 +1,54 @@
+package service
+
+import (
+	"Service-schema/domain"
+	"Service-schema/domain/domainerror"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func Test_WhenSearchingProducts_ShouldRankHitsAndCountStores(t *testing.T) {
+	ctx := context.Background()
+	setupPromotions(t)
+	t.Run("WhenSearchingProducts_ShouldRankHitsAndCountStores", func(t *testing.T) {
+		_, _ = promotionService.Add(ctx, blackFridayRequest())
+
+		result, err := productService.SearchProducts(ctx, domain.ProductSearchQuery{Text: "  Mo ", At: promotionStart})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), result.TotalCount)
+		assert.Equal(t, []int64{1, 2}, []int64{result.Hits[0].Product.Id, result.Hits[1].Product.Id})
+		assert.Equal(t, domain.MustMoney("1400", "USD"), result.Hits[0].Product.DiscountedPrice())
+		assert.Equal(t, 2, len(result.StoreFacets))
+
+		storeResult, _ := productService.SearchProducts(ctx, domain.ProductSearchQuery{Text: "mo", StoreId: 2})
+		assert.Equal(t, int64(1), storeResult.TotalCount)
+		assert.Equal(t, 2, len(storeResult.StoreFacets))
+	})
+}
+
+func Test_WhenSearchTextInvalid_ShouldNotSearchProducts(t *testing.T) {
+	ctx := context.Background()
+	setupTest(t)
+	t.Run("WhenSearchTextInvalid_ShouldNotSearchProducts", func(t *testing.T) {
+		_, err := productService.SearchProducts(ctx, domain.ProductSearchQuery{Text: "   "})
+		assert.ErrorIs(t, err, domainerror.ErrValidation)
+		assert.Equal(t, "Search text must be specified", err.Error())
+
+		_, err = productService.SearchProducts(ctx, domain.ProductSearchQuery{Text: strings.Repeat("a", 201)})
+		assert.Equal(t, "Search text must not be longer than 200 characters", err.Error())
+
+		_, err = productService.SearchProducts(ctx, domain.ProductSearchQuery{Text: "monitor", Limit: 1000})
+		assert.Equal(t, "Limit must be between 1 and 100", err.Error())
+	})
+}
+
+func Test_WhenSearchStoreDoesNotExist_ShouldNotSearchProducts(t *testing.T) {
+	ctx := context.Background()
+	setupTest(t)
+	t.Run("WhenSearchStoreDoesNotExist_ShouldNotSearchProducts", func(t *testing.T) {
+		_, err := productService.SearchProducts(ctx, domain.ProductSearchQuery{Text: "monitor", StoreId: 42})
+		assert.ErrorIs(t, err, domainerror.ErrNotFound)
+	})
+}
@@ -1,127 +1,183 @@
 package service
 
 import (
+	"Service-schema/core/purge"
 	"Service-schema/domain"
+	"Service-schema/domain/domainerror"
 	"Service-schema/persistence"
 	"Service-schema/service"
 	"Service-schema/service/dto"
+	"context"
 	"github.com/stretchr/testify/assert"
+	"io"
+	"log/slog"
 	"os"
 	"testing"
+	"time"
 )
 
 var productService service.IProductService
+var storeService service.IStoreService
+var productRepository persistence.IProductRepository
+var promotionRepository persistence.IPromotionRepository
+var storeRepository persistence.IStoreRepository
 
 func TestMain(m *testing.M) {
+	setup()
+	exitCode := m.Run()
+	os.Exit(exitCode)
+}
+
+// setupTest gives a test its own copy of the fixture and restores it afterwards, so that the tests relying on the
+// fixture built by TestMain do not depend on the order tests run in.
+func setupTest(t *testing.T) {
+	setup()
+	t.Cleanup(setup)
+}
+
+func setup() {
 	var initializedProducts = []domain.Product{
 		{
 			Id:       1,
 			Name:     `360Hz 24" Monitor`,
-			Price:    2000.0,
-			Discount: 12.0,
+			Price:    domain.MustMoney("2000", "USD"),
+			Discount: domain.MustDecimal("12"),
+			StoreId:  1,
 			Store:    "BENQ",
 		},
 		{
 			Id:       2,
 			Name:     `EC-2B Mouse`,
-			Price:    1200.0,
-			Discount: 10.0,
+			Price:    domain.MustMoney("1200", "USD"),
+			Discount: domain.MustDecimal("10"),
+			StoreId:  2,
 			Store:    "Zowie",
 		},
 		{
 			Id:       3,
 			Name:     `RTX 5090`,
-			Price:    10000.0,
-			Discount: 20.0,
+			Price:    domain.MustMoney("10000", "USD"),
+			Discount: domain.MustDecimal("20"),
+			StoreId:  3,
 			Store:    "Nvidia",
 		},
 		{
 			Id:       4,
 			Name:     `Iphone 17`,
-			Price:    3000.0,
-			Discount: 0.0,
+			Price:    domain.MustMoney("3000", "USD"),
+			Discount: domain.MustDecimal("0"),
+			StoreId:  4,
 			Store:    "Apple",
 		},
 	}
-	fakeProductRepository := NewFakeProductRepository(initializedProducts)
-	productService = service.NewProductService(persistence.IProductRepository(fakeProductRepository))
+	var initializedStores = []domain.Store{
+		{Id: 1, Name: "BENQ"},
+		{Id: 2, Name: "Zowie"},
+		{Id: 3, Name: "Nvidia"},
+		{Id: 4, Name: "Apple"},
+		{Id: 5, Name: "Amazon"},
+	}
+	productRepository = NewFakeProductRepository(initializedProducts)
+	storeRepository = NewFakeStoreRepository(initializedStores)
+	promotionRepository = NewFakePromotionRepository(nil)
+	productService = service.NewProductService(productRepository, storeRepository, promotionRepository, NewFakeTransactionManager(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	storeService = service.NewStoreService(storeRepository)
+}
 
-	exitCode := m.Run()
-	os.Exit(exitCode)
+func getAllProducts(ctx context.Context) []domain.Product {
+	return getAllProductsByQuery(ctx, domain.ProductQuery{})
+}
+
+func getAllProductsByQuery(ctx context.Context, query domain.ProductQuery) []domain.Product {
+	productPage, _ := productService.GetProducts(ctx, query)
+	return productPage.Items
 }
 
 func Test_ShouldGetAllProducts(t *testing.T) {
+	ctx := context.Background()
 	t.Run("ShouldGetAllProducts", func(t *testing.T) {
-		actualProducts := productService.GetAllProducts()
+		actualProducts := getAllProducts(ctx)
 		assert.Equal(t, 4, len(actualProducts))
 	})
 }
 
-func Test_ShouldGetAllProductsByStoreName(t *testing.T) {
-	t.Run("ShouldGetAllProductsByStoreName", func(t *testing.T) {
-		actualProducts := productService.GetAllProductsByStoreName("BENQ")
+func Test_ShouldGetAllProductsByStoreId(t *testing.T) {
+	ctx := context.Background()
+	t.Run("ShouldGetAllProductsByStoreId", func(t *testing.T) {
+		actualProducts := getAllProductsByQuery(ctx, domain.ProductQuery{StoreId: 1})
 		assert.Equal(t, 1, len(actualProducts))
 	})
 }
 
 func Test_ShouldGetProductById(t *testing.T) {
+	ctx := context.Background()
 	expectedProduct := domain.Product{
 		Id:       4,
 		Name:     `Iphone 17`,
-		Price:    3000.0,
-		Discount: 0.0,
+		Price:    domain.MustMoney("3000", "USD"),
+		Discount: domain.MustDecimal("0"),
+		StoreId:  4,
 		Store:    "Apple",
 	}
 	t.Run("ShouldGetProductById", func(t *testing.T) {
-		actualProduct, _ := productService.GetById(4)
+		actualProduct, _ := productService.GetById(ctx, 4)
 		assert.Equal(t, expectedProduct, actualProduct)
-		_, err := productService.GetById(10)
+		_, err := productService.GetById(ctx, 10)
 		assert.Equal(t, "Product not found", err.Error())
+		assert.ErrorIs(t, err, domainerror.ErrNotFound)
 	})
 }
 
 func Test_WhenNoValidationErrorOccurred_ShouldAddProduct(t *testing.T) {
+	ctx := context.Background()
 	t.Run("WhenNoValidationErrorOccurred_ShouldAddProduct", func(t *testing.T) {
 		productRequest := dto.CreateProductRequestDto{
 			Name:     "Pencil",
-			Price:    200.0,
-			Discount: 0.0,
-			Store:    "Amazon",
+			Price:    domain.MustDecimal("200"),
+			Discount: domain.MustDecimal("0"),
+			StoreId:  5,
 		}
-		_ = productService.Add(productRequest)
-		actualProducts := productService.GetAllProducts()
+		addedProduct, _ := productService.Add(ctx, productRequest)
+		actualProducts := getAllProducts(ctx)
 		assert.Equal(t, 5, len(actualProducts))
+		assert.Equal(t, domain.Product{Id: 5, Name: "Pencil", Price: domain.MustMoney("200", "USD"), Discount: domain.MustDecimal("0"), StoreId: 5, Store: "Amazon", Version: 1}, addedProduct)
 	})
-	_ = productService.Delete(5)
+	_ = productService.Delete(ctx, 5, 0)
 }
 
 func Test_WhenNameFieldEmpty_ShouldNotAddProduct(t *testing.T) {
+	ctx := context.Background()
 	t.Run("WhenNameFieldEmpty_ShouldNotAddProduct", func(t *testing.T) {
 		productRequest := dto.CreateProductRequestDto{
 			Name:     "",
-			Price:    200.0,
-			Discount: 0.0,
-			Store:    "Amazon",
+			Price:    domain.MustDecimal("200"),
+			Discount: domain.MustDecimal("0"),
+			StoreId:  5,
 		}
 
-		err := productService.Add(productRequest)
-		actualProducts := productService.GetAllProducts()
+		_, err := productService.Add(ctx, productRequest)
+		actualProducts := getAllProducts(ctx)
 		assert.Equal(t, 4, len(actualProducts))
 		assert.Equal(t, "Name must be specified", err.Error())
+		var domainError *domainerror.Error
+		assert.ErrorAs(t, err, &domainError)
+		assert.ErrorIs(t, err, domainerror.ErrValidation)
+		assert.Equal(t, "name", domainError.Fields[0].Field)
 	})
 }
 
 func Test_WhenStoreFieldEmpty_ShouldNotAddProduct(t *testing.T) {
+	ctx := context.Background()
 	t.Run("WhenStoreFieldEmpty_ShouldNotAddProduct", func(t *testing.T) {
 		productRequest := dto.CreateProductRequestDto{
 			Name:     "Pencil",
-			Price:    200.0,
-			Discount: 0.0,
-			Store:    "",
+			Price:    domain.MustDecimal("200"),
+			Discount: domain.MustDecimal("0"),
+			StoreId:  0,
 		}
 
-		err := productService.Add(productRequest)
-		actualProducts := productService.GetAllProducts()
+		_, err := productService.Add(ctx, productRequest)
+		actualProducts := getAllProducts(ctx)
 		assert.Equal(t, 4, len(actualProducts))
 		assert.Equal(t, "Store must be specified", err.Error())
 	})
@@ -129,85 +185,384 @@ func Test_WhenStoreFieldEmpty_ShouldNotAddProduct(t *testing.T) {
 }
 
 func Test_WhenDiscountFieldHigherThan50_ShouldNotAddProduct(t *testing.T) {
+	ctx := context.Background()
 	t.Run("WhenDiscountFieldHigherThan50_ShouldNotAddProduct", func(t *testing.T) {
 		productRequest := dto.CreateProductRequestDto{
 			Name:     "Pencil",
-			Price:    200.0,
-			Discount: 51.0,
-			Store:    "Amazon",
+			Price:    domain.MustDecimal("200"),
+			Discount: domain.MustDecimal("51"),
+			StoreId:  5,
 		}
 
-		err := productService.Add(productRequest)
-		actualProducts := productService.GetAllProducts()
+		_, err := productService.Add(ctx, productRequest)
+		actualProducts := getAllProducts(ctx)
 		assert.Equal(t, 4, len(actualProducts))
 		assert.Equal(t, "Discount must be less than 50 percent", err.Error())
 	})
 }
 
 func Test_WhenPriceFieldLessThan10_ShouldNotAddProduct(t *testing.T) {
+	ctx := context.Background()
 	t.Run("WhenPriceFieldLessThan10_ShouldNotAddProduct", func(t *testing.T) {
 		productRequest := dto.CreateProductRequestDto{
 			Name:     "Pencil",
-			Price:    2.0,
-			Discount: 0.0,
-			Store:    "Amazon",
+			Price:    domain.MustDecimal("2"),
+			Discount: domain.MustDecimal("0"),
+			StoreId:  5,
 		}
 
-		err := productService.Add(productRequest)
-		actualProducts := productService.GetAllProducts()
+		_, err := productService.Add(ctx, productRequest)
+		actualProducts := getAllProducts(ctx)
 		assert.Equal(t, 4, len(actualProducts))
 		assert.Equal(t, "Price must be greater than 10", err.Error())
 	})
 }
 
+func Test_WhenSeveralFieldsInvalid_ShouldReportEveryField(t *testing.T) {
+	ctx := context.Background()
+	setupTest(t)
+	t.Run("WhenSeveralFieldsInvalid_ShouldReportEveryField", func(t *testing.T) {
+		productRequest := dto.CreateProductRequestDto{
+			Name:     "",
+			Price:    domain.MustDecimal("2"),
+			Discount: domain.MustDecimal("51"),
+			StoreId:  0,
+		}
+
+		_, err := productService.Add(ctx, productRequest)
+		var domainError *domainerror.Error
+		assert.ErrorAs(t, err, &domainError)
+		assert.Equal(t, []domainerror.FieldError{
+			{Field: "discount", Message: "Discount must be less than 50 percent"},
+			{Field: "store_id", Message: "Store must be specified"},
+			{Field: "name", Message: "Name must be specified"},
+			{Field: "price", Message: "Price must be greater than 10"},
+		}, domainError.Fields)
+		assert.Equal(t, "Discount must be less than 50 percent; Store must be specified; Name must be specified; Price must be greater than 10", err.Error())
+		assert.Equal(t, 4, len(getAllProducts(ctx)))
+	})
+}
+
 func Test_WhenNoValidationErrorOccurred_ShouldUpdateProductPrice(t *testing.T) {
+	ctx := context.Background()
 	t.Run("WhenNoValidationErrorOccurred_ShouldUpdateProductPrice", func(t *testing.T) {
 		updatedProductRequest := dto.UpdateProductRequestDto{
 			Id:    4,
-			Price: 4000.0,
+			Price: domain.MustDecimal("4000"),
 		}
-		_ = productService.UpdatePrice(updatedProductRequest)
-		actualProduct, _ := productService.GetById(4)
-		assert.Equal(t, updatedProductRequest.Price, actualProduct.Price)
+		_ = productService.UpdatePrice(ctx, updatedProductRequest)
+		actualProduct, _ := productService.GetById(ctx, 4)
+		assert.Equal(t, updatedProductRequest.Price, actualProduct.Price.Amount)
 	})
+	_ = productService.UpdatePrice(ctx, dto.UpdateProductRequestDto{Id: 4, Price: domain.MustDecimal("3000")})
 }
 
 func Test_WhenIdFieldEmpty_ShouldNotUpdateProductPrice(t *testing.T) {
+	ctx := context.Background()
 	t.Run("WhenIdFieldEmpty_ShouldNotUpdateProductPrice", func(t *testing.T) {
 		updatedProductRequest := dto.UpdateProductRequestDto{
-			Price: 4000.0,
+			Price: domain.MustDecimal("4000"),
 		}
-		err := productService.UpdatePrice(updatedProductRequest)
+		err := productService.UpdatePrice(ctx, updatedProductRequest)
 		assert.Equal(t, "Id must be specified", err.Error())
 	})
 }
 
 func Test_WhenPriceFieldLessThan10_ShouldNotUpdateProductPrice(t *testing.T) {
+	ctx := context.Background()
 	t.Run("WhenPriceFieldLessThan10_ShouldNotUpdateProductPrice", func(t *testing.T) {
 		updatedProductRequest := dto.UpdateProductRequestDto{
 			Id:    4,
-			Price: 4.0,
+			Price: domain.MustDecimal("4"),
 		}
-		err := productService.UpdatePrice(updatedProductRequest)
-		actualProduct, _ := productService.GetById(4)
+		err := productService.UpdatePrice(ctx, updatedProductRequest)
+		actualProduct, _ := productService.GetById(ctx, 4)
 		assert.Equal(t, "Price must be greater than 10", err.Error())
-		assert.Equal(t, float32(3000.0), actualProduct.Price)
+		assert.Equal(t, domain.MustMoney("3000", "USD"), actualProduct.Price)
 	})
 }
 
 func Test_WhenGivenCorrectProductId_ShouldDeleteProduct(t *testing.T) {
+	ctx := context.Background()
 	t.Run("WhenGivenCorrectProductId_ShouldDeleteProduct", func(t *testing.T) {
-		_ = productService.Delete(4)
-		actualProducts := productService.GetAllProducts()
+		_ = productService.Delete(ctx, 4, 0)
+		actualProducts := getAllProducts(ctx)
 		assert.Equal(t, 3, len(actualProducts))
 	})
+	_, _ = productService.Restore(ctx, 4)
 }
 
 func Test_WhenGivenWrongProductId_ShouldNotDeleteProduct(t *testing.T) {
+	ctx := context.Background()
 	t.Run("WhenGivenCorrectProductId_ShouldDeleteProduct", func(t *testing.T) {
-		err := productService.Delete(10)
-		actualProducts := productService.GetAllProducts()
+		err := productService.Delete(ctx, 10, 0)
+		actualProducts := getAllProducts(ctx)
 		assert.Equal(t, 4, len(actualProducts))
 		assert.Equal(t, "Product not found", err.Error())
 	})
 }
+
+func Test_WhenLimitGiven_ShouldPaginateProductsWithCursor(t *testing.T) {
+	ctx := context.Background()
+	setupTest(t)
+	t.Run("WhenLimitGiven_ShouldPaginateProductsWithCursor", func(t *testing.T) {
+		query := domain.ProductQuery{SortField: domain.SortByPrice, SortDirection: domain.Descending, Limit: 3}
+		firstPage, _ := productService.GetProducts(ctx, query)
+		assert.Equal(t, int64(4), firstPage.TotalCount)
+		assert.Equal(t, []int64{3, 4, 1}, productIds(firstPage.Items))
+		assert.NotEmpty(t, firstPage.NextCursor)
+
+		cursor, _ := domain.DecodeProductCursor(firstPage.NextCursor)
+		query.Cursor = &cursor
+		secondPage, _ := productService.GetProducts(ctx, query)
+		assert.Equal(t, []int64{2}, productIds(secondPage.Items))
+		assert.Empty(t, secondPage.NextCursor)
+	})
+}
+
+func Test_WhenOffsetGiven_ShouldSkipProducts(t *testing.T) {
+	ctx := context.Background()
+	setupTest(t)
+	t.Run("WhenOffsetGiven_ShouldSkipProducts", func(t *testing.T) {
+		productPage, _ := productService.GetProducts(ctx, domain.ProductQuery{Limit: 2, Offset: 2})
+		assert.Equal(t, []int64{3, 4}, productIds(productPage.Items))
+		assert.Empty(t, productPage.NextCursor)
+	})
+}
+
+func Test_WhenFiltersGiven_ShouldGetMatchingProducts(t *testing.T) {
+	ctx := context.Background()
+	setupTest(t)
+	minPrice := domain.MustDecimal("1500")
+	maxDiscount := domain.MustDecimal("15")
+	t.Run("WhenFiltersGiven_ShouldGetMatchingProducts", func(t *testing.T) {
+		productPage, _ := productService.GetProducts(ctx, domain.ProductQuery{MinPrice: &minPrice, MaxDiscount: &maxDiscount})
+		assert.Equal(t, []int64{1, 4}, productIds(productPage.Items))
+		assert.Equal(t, int64(2), productPage.TotalCount)
+
+		productPage, _ = productService.GetProducts(ctx, domain.ProductQuery{NameContains: "mouse"})
+		assert.Equal(t, []int64{2}, productIds(productPage.Items))
+	})
+}
+
+func Test_WhenLimitTooHigh_ShouldNotGetProducts(t *testing.T) {
+	ctx := context.Background()
+	setupTest(t)
+	t.Run("WhenLimitTooHigh_ShouldNotGetProducts", func(t *testing.T) {
+		_, err := productService.GetProducts(ctx, domain.ProductQuery{Limit: 1000})
+		assert.ErrorIs(t, err, domainerror.ErrValidation)
+		assert.Equal(t, "Limit must be between 1 and 100", err.Error())
+	})
+}
+
+func Test_WhenCursorDoesNotMatchSort_ShouldNotGetProducts(t *testing.T) {
+	ctx := context.Background()
+	setupTest(t)
+	t.Run("WhenCursorDoesNotMatchSort_ShouldNotGetProducts", func(t *testing.T) {
+		cursor := domain.ProductCursor{SortField: domain.SortByName, SortDirection: domain.Ascending, SortValue: "EC-2B Mouse", Id: 2}
+		_, err := productService.GetProducts(ctx, domain.ProductQuery{Cursor: &cursor})
+		assert.Equal(t, "Cursor does not match the requested sort", err.Error())
+	})
+}
+
+func productIds(products []domain.Product) []int64 {
+	var ids []int64
+	for _, product := range products {
+		ids = append(ids, product.Id)
+	}
+	return ids
+}
+
+func Test_WhenNoValidationErrorOccurred_ShouldReplaceProduct(t *testing.T) {
+	ctx := context.Background()
+	setupTest(t)
+	t.Run("WhenNoValidationErrorOccurred_ShouldReplaceProduct", func(t *testing.T) {
+		replacedProduct, err := productService.Replace(ctx, dto.ReplaceProductRequestDto{
+			Id:       2,
+			Name:     "EC-3C Mouse",
+			Price:    domain.MustDecimal("1500"),
+			Discount: domain.MustDecimal("5"),
+			StoreId:  2,
+		})
+		actualProduct, _ := productService.GetById(ctx, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, domain.Product{Id: 2, Name: "EC-3C Mouse", Price: domain.MustMoney("1500", "USD"), Discount: domain.MustDecimal("5"), StoreId: 2, Store: "Zowie", Version: 1}, actualProduct)
+		assert.Equal(t, actualProduct, replacedProduct)
+	})
+}
+
+func Test_WhenProductDoesNotExist_ShouldNotReplaceProduct(t *testing.T) {
+	ctx := context.Background()
+	setupTest(t)
+	t.Run("WhenProductDoesNotExist_ShouldNotReplaceProduct", func(t *testing.T) {
+		_, err := productService.Replace(ctx, dto.ReplaceProductRequestDto{Id: 10, Name: "Pencil", Price: domain.MustDecimal("200"), StoreId: 5})
+		assert.ErrorIs(t, err, domainerror.ErrNotFound)
+	})
+}
+
+func Test_WhenOnlyPriceGiven_ShouldPatchOnlyPrice(t *testing.T) {
+	ctx := context.Background()
+	setupTest(t)
+	price := domain.MustDecimal("2500")
+	t.Run("WhenOnlyPriceGiven_ShouldPatchOnlyPrice", func(t *testing.T) {
+		_, err := productService.Patch(ctx, dto.PatchProductRequestDto{Id: 1, Price: &price})
+		actualProduct, _ := productService.GetById(ctx, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, domain.Product{Id: 1, Name: `360Hz 24" Monitor`, Price: domain.MustMoney("2500", "USD"), Discount: domain.MustDecimal("12"), StoreId: 1, Store: "BENQ", Version: 1}, actualProduct)
+	})
+}
+
+func Test_WhenPatchClearsName_ShouldNotPatchProduct(t *testing.T) {
+	ctx := context.Background()
+	setupTest(t)
+	name := ""
+	t.Run("WhenPatchClearsName_ShouldNotPatchProduct", func(t *testing.T) {
+		_, err := productService.Patch(ctx, dto.PatchProductRequestDto{Id: 1, Name: &name})
+		actualProduct, _ := productService.GetById(ctx, 1)
+		assert.Equal(t, "Name must be specified", err.Error())
+		assert.Equal(t, `360Hz 24" Monitor`, actualProduct.Name)
+	})
+}
+
+func Test_WhenPatchDiscountHigherThan50_ShouldNotPatchProduct(t *testing.T) {
+	ctx := context.Background()
+	setupTest(t)
+	discount := domain.MustDecimal("60")
+	t.Run("WhenPatchDiscountHigherThan50_ShouldNotPatchProduct", func(t *testing.T) {
+		_, err := productService.Patch(ctx, dto.PatchProductRequestDto{Id: 1, Discount: &discount})
+		assert.ErrorIs(t, err, domainerror.ErrValidation)
+		assert.Equal(t, "Discount must be less than 50 percent", err.Error())
+	})
+}
+
+func Test_WhenPriceMorePreciseThanCurrency_ShouldNotAddProduct(t *testing.T) {
+	ctx := context.Background()
+	setupTest(t)
+	t.Run("WhenPriceMorePreciseThanCurrency_ShouldNotAddProduct", func(t *testing.T) {
+		productRequest := dto.CreateProductRequestDto{
+			Name:     "Pencil",
+			Price:    domain.MustDecimal("200.005"),
+			Currency: "USD",
+			StoreId:  5,
+		}
+
+		_, err := productService.Add(ctx, productRequest)
+		actualProducts := getAllProducts(ctx)
+		assert.Equal(t, 4, len(actualProducts))
+		assert.Equal(t, "Price must not have more than 2 decimal places for USD", err.Error())
+	})
+}
+
+func Test_WhenCurrencyUnknown_ShouldNotAddProduct(t *testing.T) {
+	ctx := context.Background()
+	setupTest(t)
+	t.Run("WhenCurrencyUnknown_ShouldNotAddProduct", func(t *testing.T) {
+		productRequest := dto.CreateProductRequestDto{
+			Name:     "Pencil",
+			Price:    domain.MustDecimal("200"),
+			Currency: "ABC",
+			StoreId:  5,
+		}
+
+		_, err := productService.Add(ctx, productRequest)
+		assert.Equal(t, "Currency must be a supported ISO 4217 code", err.Error())
+	})
+}
+
+func Test_WhenStoreDoesNotExist_ShouldNotAddProduct(t *testing.T) {
+	ctx := context.Background()
+	setupTest(t)
+	t.Run("WhenStoreDoesNotExist_ShouldNotAddProduct", func(t *testing.T) {
+		productRequest := dto.CreateProductRequestDto{
+			Name:    "Pencil",
+			Price:   domain.MustDecimal("200"),
+			StoreId: 10,
+		}
+
+		_, err := productService.Add(ctx, productRequest)
+		actualProducts := getAllProducts(ctx)
+		assert.Equal(t, 4, len(actualProducts))
+		assert.ErrorIs(t, err, domainerror.ErrValidation)
+		assert.Equal(t, "Store not found with id 10", err.Error())
+	})
+}
+
+func Test_WhenStoreDoesNotExist_ShouldNotGetStoreProducts(t *testing.T) {
+	ctx := context.Background()
+	setupTest(t)
+	t.Run("WhenStoreDoesNotExist_ShouldNotGetStoreProducts", func(t *testing.T) {
+		_, err := productService.GetProducts(ctx, domain.ProductQuery{StoreId: 10})
+		assert.ErrorIs(t, err, domainerror.ErrNotFound)
+	})
+}
+
+func Test_WhenStoreIdPatched_ShouldMoveProductToStore(t *testing.T) {
+	ctx := context.Background()
+	setupTest(t)
+	storeId := int64(5)
+	t.Run("WhenStoreIdPatched_ShouldMoveProductToStore", func(t *testing.T) {
+		patchedProduct, err := productService.Patch(ctx, dto.PatchProductRequestDto{Id: 1, StoreId: &storeId})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(5), patchedProduct.StoreId)
+		assert.Equal(t, "Amazon", patchedProduct.Store)
+		assert.Equal(t, []int64{1}, productIds(getAllProductsByQuery(ctx, domain.ProductQuery{StoreId: 5})))
+	})
+}
+
+func Test_WhenDeletedProductRestored_ShouldListItAgain(t *testing.T) {
+	ctx := context.Background()
+	setupTest(t)
+	t.Run("WhenDeletedProductRestored_ShouldListItAgain", func(t *testing.T) {
+		_ = productService.Delete(ctx, 4, 0)
+		_, getErr := productService.GetById(ctx, 4)
+		deletedPage, _ := productService.GetProducts(ctx, domain.ProductQuery{IncludeDeleted: true})
+
+		restoredProduct, _ := productService.Restore(ctx, 4)
+		actualProducts := getAllProducts(ctx)
+
+		assert.ErrorIs(t, getErr, domainerror.ErrNotFound)
+		assert.Equal(t, 4, len(deletedPage.Items))
+		assert.NotNil(t, deletedPage.Items[3].DeletedAt)
+		assert.Nil(t, restoredProduct.DeletedAt)
+		assert.Equal(t, 4, len(actualProducts))
+	})
+}
+
+func Test_WhenRetentionElapsed_ShouldPurgeDeletedProducts(t *testing.T) {
+	ctx := context.Background()
+	setupTest(t)
+	t.Run("WhenRetentionElapsed_ShouldPurgeDeletedProducts", func(t *testing.T) {
+		_ = productService.Delete(ctx, 4, 0)
+
+		retainedCount, _ := service.NewProductPurgeJob(productRepository, purge.Config{Retention: time.Hour, Interval: time.Hour}, slog.New(slog.NewTextHandler(io.Discard, nil))).PurgeOnce(ctx)
+		purgedCount, _ := service.NewProductPurgeJob(productRepository, purge.Config{Retention: 0, Interval: time.Hour}, slog.New(slog.NewTextHandler(io.Discard, nil))).PurgeOnce(ctx)
+		_, restoreErr := productService.Restore(ctx, 4)
+
+		assert.Equal(t, int64(0), retainedCount)
+		assert.Equal(t, int64(1), purgedCount)
+		assert.ErrorIs(t, restoreErr, domainerror.ErrNotFound)
+	})
+}
+
+func Test_WhenExpectedVersionIsStale_ShouldNotUpdateProduct(t *testing.T) {
+	ctx := context.Background()
+	setupTest(t)
+	t.Run("WhenExpectedVersionIsStale_ShouldNotUpdateProduct", func(t *testing.T) {
+		addedProduct, _ := productService.Add(ctx, dto.CreateProductRequestDto{Name: "Pencil", Price: domain.MustDecimal("200"), StoreId: 5})
+		name := "Blue Pencil"
+		patchedProduct, _ := productService.Patch(ctx, dto.PatchProductRequestDto{Id: addedProduct.Id, Name: &name, ExpectedVersion: addedProduct.Version})
+
+		staleName := "Red Pencil"
+		_, patchErr := productService.Patch(ctx, dto.PatchProductRequestDto{Id: addedProduct.Id, Name: &staleName, ExpectedVersion: addedProduct.Version})
+		priceErr := productService.UpdatePrice(ctx, dto.UpdateProductRequestDto{Id: addedProduct.Id, Price: domain.MustDecimal("300"), ExpectedVersion: addedProduct.Version})
+		deleteErr := productService.Delete(ctx, addedProduct.Id, addedProduct.Version)
+		actualProduct, _ := productService.GetById(ctx, addedProduct.Id)
+
+		assert.Equal(t, int64(2), patchedProduct.Version)
+		assert.ErrorIs(t, patchErr, domainerror.ErrPreconditionFailed)
+		assert.ErrorIs(t, priceErr, domainerror.ErrPreconditionFailed)
+		assert.ErrorIs(t, deleteErr, domainerror.ErrPreconditionFailed)
+		assert.Equal(t, "Product with id 5 was modified since version 1", patchErr.Error())
+		assert.Equal(t, patchedProduct, actualProduct)
+	})
+}
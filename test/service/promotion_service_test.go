@@ -0,0 +1,92 @@
+package service
+
+import (
+	"Service-schema/domain"
+	"Service-schema/domain/domainerror"
+	"Service-schema/service"
+	"Service-schema/service/dto"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+var promotionService service.IPromotionService
+
+var promotionStart = time.Date(2025, time.November, 28, 0, 0, 0, 0, time.UTC)
+
+func setupPromotions(t *testing.T) {
+	setupTest(t)
+	promotionService = service.NewPromotionService(promotionRepository)
+}
+
+func blackFridayRequest() dto.PromotionRequestDto {
+	return dto.PromotionRequestDto{
+		Name:     "Black Friday",
+		Type:     domain.PercentagePromotion,
+		Value:    domain.MustDecimal("30"),
+		StartsAt: promotionStart,
+		EndsAt:   promotionStart.AddDate(0, 0, 4),
+	}
+}
+
+func Test_WhenPromotionActive_ShouldResolveDiscountedPriceAtGivenTime(t *testing.T) {
+	ctx := context.Background()
+	setupPromotions(t)
+	t.Run("WhenPromotionActive_ShouldResolveDiscountedPriceAtGivenTime", func(t *testing.T) {
+		_, _ = promotionService.Add(ctx, blackFridayRequest())
+
+		productDuringPromotion, _ := productService.GetByIdAt(ctx, 3, promotionStart.Add(time.Hour))
+		productAfterPromotion, _ := productService.GetByIdAt(ctx, 3, promotionStart.AddDate(0, 0, 4))
+
+		assert.Equal(t, domain.MustMoney("7000", "USD"), productDuringPromotion.DiscountedPrice())
+		assert.Equal(t, 1, len(productDuringPromotion.AppliedPromotions))
+		assert.Equal(t, domain.MustMoney("8000", "USD"), productAfterPromotion.DiscountedPrice())
+		assert.Empty(t, productAfterPromotion.AppliedPromotions)
+	})
+}
+
+func Test_WhenListingProductsAtGivenTime_ShouldApplyPromotions(t *testing.T) {
+	ctx := context.Background()
+	setupPromotions(t)
+	t.Run("WhenListingProductsAtGivenTime_ShouldApplyPromotions", func(t *testing.T) {
+		_, _ = promotionService.Add(ctx, blackFridayRequest())
+
+		productPage, _ := productService.GetProducts(ctx, domain.ProductQuery{At: promotionStart})
+		assert.Equal(t, domain.MustMoney("2100", "USD"), productPage.Items[3].DiscountedPrice())
+	})
+}
+
+func Test_WhenEndTimeNotAfterStartTime_ShouldNotAddPromotion(t *testing.T) {
+	ctx := context.Background()
+	setupPromotions(t)
+	t.Run("WhenEndTimeNotAfterStartTime_ShouldNotAddPromotion", func(t *testing.T) {
+		promotionRequest := blackFridayRequest()
+		promotionRequest.EndsAt = promotionRequest.StartsAt
+		_, err := promotionService.Add(ctx, promotionRequest)
+		assert.ErrorIs(t, err, domainerror.ErrValidation)
+		assert.Equal(t, "End time must be after start time", err.Error())
+	})
+}
+
+func Test_WhenFixedAmountPromotionHasNoCurrency_ShouldNotAddPromotion(t *testing.T) {
+	ctx := context.Background()
+	setupPromotions(t)
+	t.Run("WhenFixedAmountPromotionHasNoCurrency_ShouldNotAddPromotion", func(t *testing.T) {
+		promotionRequest := blackFridayRequest()
+		promotionRequest.Type = domain.FixedAmountPromotion
+		_, err := promotionService.Add(ctx, promotionRequest)
+		assert.Equal(t, "Currency must be a supported ISO 4217 code", err.Error())
+	})
+}
+
+func Test_WhenPercentageAbove100_ShouldNotAddPromotion(t *testing.T) {
+	ctx := context.Background()
+	setupPromotions(t)
+	t.Run("WhenPercentageAbove100_ShouldNotAddPromotion", func(t *testing.T) {
+		promotionRequest := blackFridayRequest()
+		promotionRequest.Value = domain.MustDecimal("101")
+		_, err := promotionService.Add(ctx, promotionRequest)
+		assert.Equal(t, "Value must not be greater than 100 percent", err.Error())
+	})
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"Service-schema/domain"
+	"Service-schema/domain/domainerror"
+	"Service-schema/service"
+	"Service-schema/service/dto"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var stockService service.IStockService
+
+func setupStock() {
+	var initializedStockLevels = []domain.StockLevel{
+		{ProductId: 1, StoreId: 1, OnHand: 10, Reserved: 2},
+		{ProductId: 2, StoreId: 2, OnHand: 0, Reserved: 0},
+	}
+	stockService = service.NewStockService(NewFakeStockRepository(initializedStockLevels), NewFakeStoreRepository([]domain.Store{{Id: 1, Name: "BENQ"}, {Id: 2, Name: "Zowie"}}))
+}
+
+func Test_WhenEnoughStockAvailable_ShouldReserveStock(t *testing.T) {
+	ctx := context.Background()
+	setupStock()
+	t.Run("WhenEnoughStockAvailable_ShouldReserveStock", func(t *testing.T) {
+		stockLevel, err := stockService.Reserve(ctx, dto.StockOperationRequestDto{ProductId: 1, Quantity: 8})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(10), stockLevel.Reserved)
+		assert.Equal(t, int64(0), stockLevel.Available())
+	})
+}
+
+func Test_WhenReservationWouldGoNegative_ShouldNotReserveStock(t *testing.T) {
+	ctx := context.Background()
+	setupStock()
+	t.Run("WhenReservationWouldGoNegative_ShouldNotReserveStock", func(t *testing.T) {
+		_, err := stockService.Reserve(ctx, dto.StockOperationRequestDto{ProductId: 1, Quantity: 9})
+		actualStockLevel, _ := stockService.GetProductStock(ctx, 1)
+		assert.ErrorIs(t, err, domainerror.ErrConflict)
+		assert.Equal(t, int64(2), actualStockLevel.Reserved)
+	})
+}
+
+func Test_ShouldCommitReservedStock(t *testing.T) {
+	ctx := context.Background()
+	setupStock()
+	t.Run("ShouldCommitReservedStock", func(t *testing.T) {
+		stockLevel, err := stockService.Commit(ctx, dto.StockOperationRequestDto{ProductId: 1, Quantity: 2})
+		assert.Nil(t, err)
+		assert.Equal(t, domain.StockLevel{ProductId: 1, StoreId: 1, OnHand: 8, Reserved: 0}, stockLevel)
+	})
+}
+
+func Test_WhenProductDoesNotExist_ShouldNotAdjustStock(t *testing.T) {
+	ctx := context.Background()
+	setupStock()
+	t.Run("WhenProductDoesNotExist_ShouldNotAdjustStock", func(t *testing.T) {
+		_, err := stockService.Adjust(ctx, dto.StockOperationRequestDto{ProductId: 10, Quantity: 5})
+		assert.ErrorIs(t, err, domainerror.ErrNotFound)
+	})
+}
+
+func Test_WhenStoreDoesNotExist_ShouldNotGetStoreStock(t *testing.T) {
+	ctx := context.Background()
+	setupStock()
+	t.Run("WhenStoreDoesNotExist_ShouldNotGetStoreStock", func(t *testing.T) {
+		stockLevels, _ := stockService.GetStoreStock(ctx, 2)
+		_, err := stockService.GetStoreStock(ctx, 10)
+		assert.Equal(t, 1, len(stockLevels))
+		assert.ErrorIs(t, err, domainerror.ErrNotFound)
+	})
+}
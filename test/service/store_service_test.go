@@ -0,0 +1,84 @@
+package service
+
+import (
+	"Service-schema/domain"
+	"Service-schema/domain/domainerror"
+	"Service-schema/service/dto"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func Test_ShouldGetAllStores(t *testing.T) {
+	ctx := context.Background()
+	setupTest(t)
+	t.Run("ShouldGetAllStores", func(t *testing.T) {
+		actualStores, _ := storeService.GetAllStores(ctx)
+		assert.Equal(t, 5, len(actualStores))
+	})
+}
+
+func Test_WhenNoValidationErrorOccurred_ShouldAddStore(t *testing.T) {
+	ctx := context.Background()
+	setupTest(t)
+	t.Run("WhenNoValidationErrorOccurred_ShouldAddStore", func(t *testing.T) {
+		addedStore, err := storeService.Add(ctx, dto.CreateStoreRequestDto{Name: " Samsung "})
+		assert.Nil(t, err)
+		assert.Equal(t, domain.Store{Id: 6, Name: "Samsung"}, addedStore)
+	})
+}
+
+func Test_WhenNameFieldEmpty_ShouldNotAddStore(t *testing.T) {
+	ctx := context.Background()
+	setupTest(t)
+	t.Run("WhenNameFieldEmpty_ShouldNotAddStore", func(t *testing.T) {
+		_, err := storeService.Add(ctx, dto.CreateStoreRequestDto{Name: "  "})
+		assert.ErrorIs(t, err, domainerror.ErrValidation)
+		assert.Equal(t, "Name must be specified", err.Error())
+	})
+}
+
+func Test_WhenNameHas255MultibyteCharacters_ShouldAddStore(t *testing.T) {
+	ctx := context.Background()
+	setupTest(t)
+	t.Run("WhenNameHas255MultibyteCharacters_ShouldAddStore", func(t *testing.T) {
+		addedStore, err := storeService.Add(ctx, dto.CreateStoreRequestDto{Name: strings.Repeat("ü", 255)})
+		assert.Nil(t, err)
+		assert.Equal(t, strings.Repeat("ü", 255), addedStore.Name)
+
+		_, err = storeService.Add(ctx, dto.CreateStoreRequestDto{Name: strings.Repeat("ü", 256)})
+		assert.ErrorIs(t, err, domainerror.ErrValidation)
+	})
+}
+
+func Test_WhenStoreNameDiffersOnlyInCase_ShouldNotAddStore(t *testing.T) {
+	ctx := context.Background()
+	setupTest(t)
+	t.Run("WhenStoreNameDiffersOnlyInCase_ShouldNotAddStore", func(t *testing.T) {
+		_, err := storeService.Add(ctx, dto.CreateStoreRequestDto{Name: "NVIDIA"})
+		actualStores, _ := storeService.GetAllStores(ctx)
+		assert.ErrorIs(t, err, domainerror.ErrConflict)
+		assert.Equal(t, 5, len(actualStores))
+	})
+}
+
+func Test_WhenNoValidationErrorOccurred_ShouldUpdateStore(t *testing.T) {
+	ctx := context.Background()
+	setupTest(t)
+	t.Run("WhenNoValidationErrorOccurred_ShouldUpdateStore", func(t *testing.T) {
+		_, err := storeService.Update(ctx, dto.UpdateStoreRequestDto{Id: 3, Name: "NVIDIA"})
+		actualStore, _ := storeService.GetById(ctx, 3)
+		assert.Nil(t, err)
+		assert.Equal(t, domain.Store{Id: 3, Name: "NVIDIA"}, actualStore)
+	})
+}
+
+func Test_WhenGivenWrongStoreId_ShouldNotDeleteStore(t *testing.T) {
+	ctx := context.Background()
+	setupTest(t)
+	t.Run("WhenGivenWrongStoreId_ShouldNotDeleteStore", func(t *testing.T) {
+		err := storeService.Delete(ctx, 10)
+		assert.ErrorIs(t, err, domainerror.ErrNotFound)
+	})
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"Service-schema/core/tracing"
+	"Service-schema/persistence"
+	"Service-schema/service"
+	"context"
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"io"
+	"log/slog"
+	"testing"
+)
+
+func Test_WhenProductServiceTraced_ShouldNestRepositorySpansUnderServiceSpan(t *testing.T) {
+	ctx := context.Background()
+	setupTest(t)
+	t.Run("WhenProductServiceTraced_ShouldNestRepositorySpansUnderServiceSpan", func(t *testing.T) {
+		recorder := tracetest.NewSpanRecorder()
+		tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+		tracedRepository := persistence.NewTracedProductRepository(productRepository, tracerProvider)
+		tracedService := service.NewTracedProductService(
+			service.NewProductService(tracedRepository, storeRepository, promotionRepository, NewFakeTransactionManager(), slog.New(slog.NewTextHandler(io.Discard, nil))), tracerProvider)
+
+		_, err := tracedService.GetById(ctx, 1)
+
+		assert.Nil(t, err)
+		spans := recorder.Ended()
+		assert.Len(t, spans, 2)
+		repositorySpan, serviceSpan := spans[0], spans[1]
+		assert.Equal(t, "ProductRepository.GetById", repositorySpan.Name())
+		assert.Equal(t, "ProductService.GetById", serviceSpan.Name())
+		assert.Equal(t, serviceSpan.SpanContext().SpanID(), repositorySpan.Parent().SpanID())
+		assert.Equal(t, serviceSpan.SpanContext().TraceID(), repositorySpan.SpanContext().TraceID())
+		assert.Contains(t, serviceSpan.Attributes(), tracing.ProductIdKey.Int64(1))
+		assert.Contains(t, serviceSpan.Attributes(), tracing.OutcomeKey.String("ok"))
+	})
+}
+
+func Test_WhenTracedProductServiceFails_ShouldRecordOutcome(t *testing.T) {
+	ctx := context.Background()
+	setupTest(t)
+	t.Run("WhenTracedProductServiceFails_ShouldRecordOutcome", func(t *testing.T) {
+		recorder := tracetest.NewSpanRecorder()
+		tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+		tracedService := service.NewTracedProductService(productService, tracerProvider)
+
+		_, err := tracedService.GetById(ctx, 42)
+
+		assert.NotNil(t, err)
+		spans := recorder.Ended()
+		assert.Len(t, spans, 1)
+		assert.Contains(t, spans[0].Attributes(), tracing.OutcomeKey.String("not_found"))
+		assert.Equal(t, "Unset", spans[0].Status().Code.String())
+	})
+}